@@ -1,23 +1,21 @@
-import React,{useState} from "react";
+import React,{useState,useEffect} from "react";
+import axios from "axios";
 import JsonData from '../data/codes.json'
-import { useTable, usePagination,useFilters } from 'react-table'
+import { useTable, usePagination } from 'react-table'
 import {useNavigate} from "react-router-dom";
 import 'bootstrap/dist/css/bootstrap.min.css';
 import "./Prices.css"
 
 var currentPage = 0;
 
-function Table({ columns, data }) {
-    const [filterInput, setFilterInput] = useState("");
+function Table({ columns, data, query, onQueryChange }) {
     const navigate = useNavigate();
     const onRowClick = (e) => {
         navigate(`/${e.symbol}`);
     };
 
     const handleFilterChange = (e) => {
-      const value = e.target.value || undefined;
-      setFilter("symbol", value); 
-      setFilterInput(value);
+      onQueryChange(e.target.value);
     };
     
 
@@ -26,7 +24,6 @@ function Table({ columns, data }) {
         getTableBodyProps,
         headerGroups,
         prepareRow,
-        setFilter,
         page,
         canPreviousPage,
         canNextPage,
@@ -44,7 +41,6 @@ function Table({ columns, data }) {
             initialState: { pageIndex: currentPage, pageSize: 5 },
             autoResetPage: false,
         },
-        useFilters,
         usePagination
     )
 
@@ -52,7 +48,7 @@ function Table({ columns, data }) {
         <div className="price-c">
             <h1><span className="blue"></span>Current Stock<span className="blue"></span> <span className="yellow">Prices</span></h1>
             <h2>Created by <a href="https://github.com/VrMolodyakov" target="_blank">Vyachesav</a></h2>
-            <input className = "symbol-input" value={filterInput} onChange={handleFilterChange} placeholder={"Search symbol"}/>
+            <input className = "symbol-input" value={query} onChange={handleFilterChange} placeholder={"Search symbol or company"}/>
             <table className="container" {...getTableProps()}>
                 <thead>
                     {headerGroups.map(headerGroup => (
@@ -135,6 +131,26 @@ function Table({ columns, data }) {
 }
 
 function Prices() {
+    const [query, setQuery] = useState("");
+    const [symbols, setSymbols] = useState(JsonData);
+
+    useEffect(() => {
+        if (query.trim() === "") {
+            setSymbols(JsonData);
+            return;
+        }
+        const timer = setTimeout(() => {
+            axios.get("http://localhost:8080/api/stock/search", {
+                params: { q: query, limit: 50 },
+                withCredentials: true,
+                headers: { "Authorization": 'Bearer ' + localStorage.getItem("access_token") },
+            })
+            .then((response) => setSymbols(response.data.data))
+            .catch((error) => console.log(error));
+        }, 250);
+        return () => clearTimeout(timer);
+    }, [query]);
+
     const columns = React.useMemo(
         () => [
             {
@@ -159,7 +175,7 @@ function Prices() {
         []
     )
     return (
-        <Table columns={columns} data={JsonData} />
+        <Table columns={columns} data={symbols} query={query} onQueryChange={setQuery} />
     )
 }
 
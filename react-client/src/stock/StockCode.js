@@ -110,16 +110,15 @@ const Code = (props) => {
       const response = await getStockData();
       console.log(response)
       const data = response.data;
-      const stockInfo = data.chart.result[0];
-      console.log(stockInfo);
-      setPrice(stockInfo.meta.regularMarketPrice.toFixed(2));
-      setPriceTime(new Intl.DateTimeFormat('ru', {year: 'numeric', month: '2-digit',day: '2-digit', hour: '2-digit', minute: '2-digit', second: '2-digit',timeZone: 'GMT'}).format(stockInfo.meta.regularMarketTime * 1000));
-      setSymbol(stockInfo.meta.symbol);
-      const quote = stockInfo.indicators.quote[0];
-      const prices = stockInfo.timestamp.map((timestamp, index) => ({
-        x: new Date(timestamp * 1000),
-        y: [quote.open[index], quote.high[index], quote.low[index], quote.close[index]].map(round)
-      }));
+      setPrice(data.price.toFixed(2));
+      setPriceTime(new Intl.DateTimeFormat('ru', {year: 'numeric', month: '2-digit',day: '2-digit', hour: '2-digit', minute: '2-digit', second: '2-digit',timeZone: 'GMT'}).format(data.price_time * 1000));
+      setSymbol(data.symbol);
+      const prices = data.bars
+        .filter(bar => bar.close !== null)
+        .map(bar => ({
+          x: new Date(bar.timestamp * 1000),
+          y: [bar.open, bar.high, bar.low, bar.close].map(round)
+        }));
       setPriceInfo([{
         data: prices,
       }]);
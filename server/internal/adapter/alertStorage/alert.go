@@ -0,0 +1,112 @@
+package alertstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DbClient interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type alertStorage struct {
+	logger *logging.Logger
+	client DbClient
+}
+
+func New(logger *logging.Logger, client DbClient) *alertStorage {
+	return &alertStorage{logger: logger, client: client}
+}
+
+const selectAlert = `SELECT a_id,a_user_id,a_symbol,a_condition,a_threshold,a_channel,a_target,a_rearm,a_armed,a_active,
+		a_fired_at,create_at FROM alerts`
+
+func (a *alertStorage) Insert(ctx context.Context, alert entity.Alert) (entity.Alert, error) {
+	sql := `INSERT INTO alerts(a_user_id,a_symbol,a_condition,a_threshold,a_channel,a_target,a_rearm,a_armed,a_active,create_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7,true,true,$8) RETURNING a_id,create_at`
+	alert.Armed = true
+	alert.Active = true
+	alert.FiredAt = time.Time{}
+	err := a.client.QueryRow(ctx, sql, alert.UserId, alert.Symbol, string(alert.Condition), alert.Threshold,
+		string(alert.Channel), alert.Target, alert.Rearm, time.Now()).Scan(&alert.Id, &alert.CreateAt)
+	if err != nil {
+		return entity.Alert{}, err
+	}
+	return alert, nil
+}
+
+func (a *alertStorage) FindAll(ctx context.Context, userId int) ([]entity.Alert, error) {
+	rows, err := a.client.Query(ctx, selectAlert+` WHERE a_user_id = $1 ORDER BY a_id`, userId)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+// Active returns the alerts of the symbol which can still fire.
+func (a *alertStorage) Active(ctx context.Context, symbol string) ([]entity.Alert, error) {
+	rows, err := a.client.Query(ctx, selectAlert+` WHERE a_symbol = $1 AND a_active ORDER BY a_id`, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return scanAlerts(rows)
+}
+
+func (a *alertStorage) Delete(ctx context.Context, userId int, id int) error {
+	tag, err := a.client.Exec(ctx, `DELETE FROM alerts WHERE a_id = $1 AND a_user_id = $2`, id, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.New(errs.NotExist, errs.Code("alert not found"), errs.Parameter("id"), pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// Fire disarms the alert and deactivates it unless it re-arms. It reports
+// false if the alert has already fired, so that it's delivered only once even
+// when several instances evaluate the same quote.
+func (a *alertStorage) Fire(ctx context.Context, id int, firedAt time.Time) (bool, error) {
+	sql := `UPDATE alerts SET a_armed = false, a_active = a_rearm, a_fired_at = $1
+			WHERE a_id = $2 AND a_armed AND a_active`
+	tag, err := a.client.Exec(ctx, sql, firedAt, id)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+func (a *alertStorage) Rearm(ctx context.Context, id int) error {
+	_, err := a.client.Exec(ctx, `UPDATE alerts SET a_armed = true WHERE a_id = $1 AND a_active`, id)
+	return err
+}
+
+func scanAlerts(rows pgx.Rows) ([]entity.Alert, error) {
+	defer rows.Close()
+	alerts := make([]entity.Alert, 0)
+	for rows.Next() {
+		var alert entity.Alert
+		var condition, channel string
+		var firedAt *time.Time
+		err := rows.Scan(&alert.Id, &alert.UserId, &alert.Symbol, &condition, &alert.Threshold, &channel, &alert.Target,
+			&alert.Rearm, &alert.Armed, &alert.Active, &firedAt, &alert.CreateAt)
+		if err != nil {
+			return nil, err
+		}
+		alert.Condition = entity.AlertCondition(condition)
+		alert.Channel = entity.AlertChannel(channel)
+		if firedAt != nil {
+			alert.FiredAt = *firedAt
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts, rows.Err()
+}
@@ -0,0 +1,163 @@
+package alertstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActive(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	createAt := time.Now()
+	columns := []string{"a_id", "a_user_id", "a_symbol", "a_condition", "a_threshold", "a_channel", "a_target", "a_rearm",
+		"a_armed", "a_active", "a_fired_at", "create_at"}
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		want    []entity.Alert
+		isError bool
+	}{
+		{
+			title: "Should return active alerts",
+			mock: func() {
+				rows := pgxpoolmock.NewRows(columns).
+					AddRow(1, 2, "AAPL", "cross_above", 200.0, "webhook", "https://example.com", true, true, true, nil, createAt).
+					ToPgxRows()
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "AAPL").Return(rows, nil)
+			},
+			want: []entity.Alert{{
+				Id: 1, UserId: 2, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook,
+				Target: "https://example.com", Rearm: true, Armed: true, Active: true, CreateAt: createAt,
+			}},
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "AAPL").Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Active(context.Background(), "AAPL")
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestFire(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	firedAt := time.Now()
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		want    bool
+		isError bool
+	}{
+		{
+			title: "Should fire armed alert",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), firedAt, 1).Return(pgconn.CommandTag("UPDATE 1"), nil)
+			},
+			want: true,
+		},
+		{
+			title: "Should not fire alert fired already",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), firedAt, 1).Return(pgconn.CommandTag("UPDATE 0"), nil)
+			},
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), firedAt, 1).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Fire(context.Background(), 1, firedAt)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mock     mockCall
+		isError  bool
+		notExist bool
+	}{
+		{
+			title: "Should delete alert",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), 4, 7).Return(pgconn.CommandTag("DELETE 1"), nil)
+			},
+		},
+		{
+			title: "Should return not exist if alert isn't found",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), 4, 7).Return(pgconn.CommandTag("DELETE 0"), nil)
+			},
+			isError:  true,
+			notExist: true,
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), 4, 7).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			err := storage.Delete(context.Background(), 7, 4)
+			if test.isError {
+				assert.Error(t, err)
+				var e *errs.Error
+				assert.Equal(t, test.notExist, errors.As(err, &e) && e.Kind == errs.NotExist)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package barstorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DbClient interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type barStorage struct {
+	logger *logging.Logger
+	client DbClient
+}
+
+func New(logger *logging.Logger, client DbClient) *barStorage {
+	return &barStorage{logger: logger, client: client}
+}
+
+// Upsert writes the bars of one symbol and interval in a single statement,
+// bars that are already stored are overwritten with the latest values.
+func (b *barStorage) Upsert(ctx context.Context, symbol string, interval string, bars []entity.Bar) error {
+	if len(bars) == 0 {
+		return nil
+	}
+	sql := `INSERT INTO bars(b_symbol,b_interval,b_timestamp,b_open,b_high,b_low,b_close,b_volume)
+			SELECT $1,$2,unnest($3::timestamptz[]),unnest($4::float8[]),unnest($5::float8[]),unnest($6::float8[]),unnest($7::float8[]),unnest($8::float8[])
+			ON CONFLICT (b_symbol,b_interval,b_timestamp) DO UPDATE SET
+			b_open = EXCLUDED.b_open, b_high = EXCLUDED.b_high, b_low = EXCLUDED.b_low,
+			b_close = EXCLUDED.b_close, b_volume = EXCLUDED.b_volume`
+	timestamps := make([]time.Time, len(bars))
+	opens := make([]float64, len(bars))
+	highs := make([]float64, len(bars))
+	lows := make([]float64, len(bars))
+	closes := make([]float64, len(bars))
+	volumes := make([]float64, len(bars))
+	for i, bar := range bars {
+		timestamps[i] = bar.Timestamp
+		opens[i] = bar.Open
+		highs[i] = bar.High
+		lows[i] = bar.Low
+		closes[i] = bar.Close
+		volumes[i] = bar.Volume
+	}
+	_, err := b.client.Exec(ctx, sql, symbol, interval, timestamps, opens, highs, lows, closes, volumes)
+	return err
+}
+
+func (b *barStorage) Find(ctx context.Context, symbol string, interval string, from time.Time, to time.Time) ([]entity.Bar, error) {
+	sql := `SELECT b_timestamp,b_open,b_high,b_low,b_close,b_volume FROM bars
+			WHERE b_symbol = $1 AND b_interval = $2 AND b_timestamp >= $3 AND b_timestamp < $4
+			ORDER BY b_timestamp`
+	rows, err := b.client.Query(ctx, sql, symbol, interval, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	bars := make([]entity.Bar, 0)
+	for rows.Next() {
+		bar := entity.Bar{Symbol: symbol, Interval: interval}
+		err := rows.Scan(&bar.Timestamp, &bar.Open, &bar.High, &bar.Low, &bar.Close, &bar.Volume)
+		if err != nil {
+			return nil, err
+		}
+		bars = append(bars, bar)
+	}
+	return bars, rows.Err()
+}
@@ -0,0 +1,113 @@
+package barstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	at := time.Unix(100, 0).UTC()
+	bars := []entity.Bar{{Symbol: "AAPL", Interval: "1d", Timestamp: at, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10}}
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		input   []entity.Bar
+		isError bool
+	}{
+		{
+			title: "Should upsert bars as arrays",
+			mock: func() {
+				mockPool.EXPECT().Exec(
+					gomock.Any(),
+					gomock.Any(),
+					"AAPL", "1d",
+					[]time.Time{at}, []float64{1}, []float64{2}, []float64{0.5}, []float64{1.5}, []float64{10},
+				).Return(pgconn.CommandTag("INSERT 0 1"), nil)
+			},
+			input: bars,
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(),
+					gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			input:   bars,
+			isError: true,
+		},
+		{
+			title: "Should skip empty bars",
+			mock:  func() {},
+			input: nil,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			err := storage.Upsert(context.Background(), "AAPL", "1d", test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestFind(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	at := time.Unix(100, 0).UTC()
+	columns := []string{"b_timestamp", "b_open", "b_high", "b_low", "b_close", "b_volume"}
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		want    []entity.Bar
+		isError bool
+	}{
+		{
+			title: "Should find bars",
+			mock: func() {
+				rows := pgxpoolmock.NewRows(columns).AddRow(at, 1.0, 2.0, 0.5, 1.5, 10.0).ToPgxRows()
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "AAPL", "1d", gomock.Any(), gomock.Any()).Return(rows, nil)
+			},
+			want: []entity.Bar{{Symbol: "AAPL", Interval: "1d", Timestamp: at, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10}},
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Find(context.Background(), "AAPL", "1d", time.Unix(0, 0), time.Unix(200, 0))
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
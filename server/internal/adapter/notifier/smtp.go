@@ -0,0 +1,91 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+type SmtpOptions struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+type smtpNotifier struct {
+	logger  *logging.Logger
+	options SmtpOptions
+}
+
+// NewSmtpNotifier returns a notifier mailing the alert to the target address
+// of the alert. STARTTLS is used whenever the server offers it, credentials
+// are sent only over TLS.
+func NewSmtpNotifier(logger *logging.Logger, options SmtpOptions) *smtpNotifier {
+	return &smtpNotifier{logger: logger, options: options}
+}
+
+func (s *smtpNotifier) Notify(ctx context.Context, event entity.AlertEvent) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.options.Host, s.options.Port))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	client, err := smtp.NewClient(conn, s.options.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.options.Host}); err != nil {
+			return err
+		}
+	}
+	if s.options.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.options.Username, s.options.Password, s.options.Host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(s.options.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(event.Alert.Target); err != nil {
+		return err
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(s.mail(event)); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+func (s *smtpNotifier) mail(event entity.AlertEvent) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %v\r\n", s.options.From)
+	fmt.Fprintf(&b, "To: %v\r\n", event.Alert.Target)
+	fmt.Fprintf(&b, "Subject: %v\r\n", mime.QEncoding.Encode("utf-8", event.Alert.Symbol+" alert"))
+	fmt.Fprintf(&b, "Date: %v\r\n", event.FiredAt.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(message(event))
+	b.WriteString("\r\n")
+	return []byte(b.String())
+}
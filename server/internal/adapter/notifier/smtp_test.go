@@ -0,0 +1,118 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeSmtp accepts a single session and returns the commands and the mail
+// data it received.
+func fakeSmtp(t *testing.T, rejectRcpt bool) (string, string, <-chan []string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received := make(chan []string, 1)
+	go func() {
+		defer listener.Close()
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		reader := bufio.NewReader(conn)
+		write := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		lines := make([]string, 0)
+		defer func() { received <- lines }()
+		write("220 localhost ESMTP")
+		data := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			line = strings.TrimRight(line, "\r\n")
+			lines = append(lines, line)
+			switch {
+			case data:
+				if line == "." {
+					data = false
+					write("250 queued")
+				}
+			case strings.HasPrefix(line, "EHLO"), strings.HasPrefix(line, "HELO"):
+				write("250 localhost")
+			case strings.HasPrefix(line, "RCPT") && rejectRcpt:
+				write("550 no such user")
+			case strings.HasPrefix(line, "DATA"):
+				data = true
+				write("354 go ahead")
+			case strings.HasPrefix(line, "QUIT"):
+				write("221 bye")
+				return
+			default:
+				write("250 ok")
+			}
+		}
+	}()
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	return host, port, received
+}
+
+func TestSmtpNotify(t *testing.T) {
+	event := entity.AlertEvent{
+		Alert:   entity.Alert{Id: 3, Symbol: "TSLA", Condition: entity.DropPercent, Threshold: 5, Target: "user@example.com"},
+		Price:   190,
+		Change:  -5.5,
+		FiredAt: time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+	}
+	testCases := []struct {
+		title      string
+		rejectRcpt bool
+		isError    bool
+	}{
+		{
+			title: "mail is sent to the target",
+		},
+		{
+			title:      "rejected recipient",
+			rejectRcpt: true,
+			isError:    true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			host, port, received := fakeSmtp(t, test.rejectRcpt)
+			notifier := NewSmtpNotifier(logging.GetLogger("debug"), SmtpOptions{Host: host, Port: port, From: "alerts@example.com"})
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err := notifier.Notify(ctx, event)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			session := strings.Join(<-received, "\n")
+			assert.Contains(t, session, "MAIL FROM:<alerts@example.com>")
+			assert.Contains(t, session, "RCPT TO:<user@example.com>")
+			assert.Contains(t, session, "Subject: TSLA alert")
+			assert.Contains(t, session, "TSLA dropped 5.50% intraday, price is 190")
+		})
+	}
+}
+
+func TestSmtpSubjectEncoded(t *testing.T) {
+	notifier := NewSmtpNotifier(logging.GetLogger("debug"), SmtpOptions{From: "alerts@example.com"})
+	event := entity.AlertEvent{Alert: entity.Alert{Symbol: "TSLA\r\nBcc: victim@example.com", Target: "user@example.com"}}
+	headers := strings.SplitN(string(notifier.mail(event)), "\r\n\r\n", 2)[0]
+	for _, line := range strings.Split(headers, "\r\n") {
+		assert.False(t, strings.HasPrefix(line, "Bcc:"), "the symbol adds a header")
+	}
+}
@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/netguard"
+)
+
+type webhookPayload struct {
+	AlertId   int     `json:"alert_id"`
+	Symbol    string  `json:"symbol"`
+	Condition string  `json:"condition"`
+	Threshold float64 `json:"threshold"`
+	Price     float64 `json:"price"`
+	Change    float64 `json:"change"`
+	FiredAt   string  `json:"fired_at"`
+	Message   string  `json:"message"`
+}
+
+type webhookNotifier struct {
+	logger *logging.Logger
+	client *http.Client
+}
+
+// NewWebhookNotifier returns a notifier posting the alert as JSON to the
+// target url of the alert.
+func NewWebhookNotifier(logger *logging.Logger, client *http.Client) *webhookNotifier {
+	return &webhookNotifier{logger: logger, client: client}
+}
+
+// NewWebhookClient returns the client of the webhooks. It connects only to
+// public addresses and never through a proxy, so the targets can't reach the
+// services inside the network.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second, Control: netguard.Control}).DialContext
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+func (w *webhookNotifier) Notify(ctx context.Context, event entity.AlertEvent) error {
+	body, err := json.Marshal(webhookPayload{
+		AlertId:   event.Alert.Id,
+		Symbol:    event.Alert.Symbol,
+		Condition: string(event.Alert.Condition),
+		Threshold: event.Alert.Threshold,
+		Price:     event.Price,
+		Change:    event.Change,
+		FiredAt:   event.FiredAt.Format(time.RFC3339),
+		Message:   message(event),
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, event.Alert.Target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errs.New(errs.Internal, fmt.Sprintf("webhook responded with status %v", resp.StatusCode))
+	}
+	return nil
+}
+
+func message(event entity.AlertEvent) string {
+	alert := event.Alert
+	switch alert.Condition {
+	case entity.CrossAbove:
+		return fmt.Sprintf("%v crossed above %v, price is %v", alert.Symbol, alert.Threshold, event.Price)
+	case entity.CrossBelow:
+		return fmt.Sprintf("%v crossed below %v, price is %v", alert.Symbol, alert.Threshold, event.Price)
+	case entity.RisePercent:
+		return fmt.Sprintf("%v rose %.2f%% intraday, price is %v", alert.Symbol, event.Change, event.Price)
+	case entity.DropPercent:
+		return fmt.Sprintf("%v dropped %.2f%% intraday, price is %v", alert.Symbol, -event.Change, event.Price)
+	}
+	return fmt.Sprintf("%v alert fired, price is %v", alert.Symbol, event.Price)
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotify(t *testing.T) {
+	event := entity.AlertEvent{
+		Alert:   entity.Alert{Id: 3, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200},
+		Price:   201.5,
+		FiredAt: time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+	}
+	testCases := []struct {
+		title   string
+		status  int
+		isError bool
+	}{
+		{
+			title:  "payload is posted to the target",
+			status: http.StatusNoContent,
+		},
+		{
+			title:   "error status of the target",
+			status:  http.StatusBadGateway,
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				var payload webhookPayload
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				assert.Equal(t, 3, payload.AlertId)
+				assert.Equal(t, 201.5, payload.Price)
+				assert.Equal(t, "AAPL crossed above 200, price is 201.5", payload.Message)
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+			event := event
+			event.Alert.Target = server.URL
+			err := NewWebhookNotifier(logging.GetLogger("debug"), server.Client()).Notify(context.Background(), event)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestWebhookClientRefusesInternalTargets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("internal target is reached")
+	}))
+	defer server.Close()
+	webhook := NewWebhookNotifier(logging.GetLogger("debug"), NewWebhookClient(time.Second))
+	event := entity.AlertEvent{Alert: entity.Alert{Id: 3, Symbol: "AAPL", Condition: entity.CrossAbove, Target: server.URL}}
+	assert.Error(t, webhook.Notify(context.Background(), event))
+}
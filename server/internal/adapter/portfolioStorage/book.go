@@ -0,0 +1,160 @@
+package portfoliostorage
+
+import (
+	"context"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/jackc/pgx/v4"
+)
+
+const selectBookOrder = `SELECT bo_id,bo_user_id,bo_symbol,bo_side,bo_type,bo_quantity,bo_filled,bo_limit_price,bo_stop_price,
+		bo_triggered,bo_tif,bo_state,bo_expires_at,create_at,update_at FROM book_orders`
+
+func (p *portfolioStorage) InsertBookOrder(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error) {
+	sql := `INSERT INTO book_orders(bo_user_id,bo_symbol,bo_side,bo_type,bo_quantity,bo_filled,bo_limit_price,bo_stop_price,
+			bo_triggered,bo_tif,bo_state,bo_expires_at,create_at,update_at)
+			VALUES ($1,$2,$3,$4,$5,0,$6,$7,false,$8,$9,$10,$11,$11) RETURNING bo_id,create_at,update_at`
+	order.Filled = 0
+	order.Triggered = false
+	order.State = entity.Open
+	err := p.client.QueryRow(ctx, sql, order.UserId, order.Symbol, string(order.Side), string(order.Type), order.Quantity,
+		order.LimitPrice, order.StopPrice, string(order.TimeInForce), string(order.State), nullTime(order.ExpiresAt), time.Now()).
+		Scan(&order.Id, &order.CreateAt, &order.UpdateAt)
+	if err != nil {
+		return entity.BookOrder{}, err
+	}
+	return order, nil
+}
+
+func (p *portfolioStorage) BookOrders(ctx context.Context, userId int, limit int) ([]entity.BookOrder, error) {
+	sql := selectBookOrder + ` WHERE bo_user_id = $1 ORDER BY bo_id DESC LIMIT $2`
+	rows, err := p.client.Query(ctx, sql, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanBookOrders(rows)
+}
+
+// ActiveBookOrders returns the open and partially filled orders of the symbol.
+func (p *portfolioStorage) ActiveBookOrders(ctx context.Context, symbol string) ([]entity.BookOrder, error) {
+	sql := selectBookOrder + ` WHERE bo_symbol = $1 AND bo_state IN ('open', 'partially_filled') ORDER BY bo_id`
+	rows, err := p.client.Query(ctx, sql, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return scanBookOrders(rows)
+}
+
+func (p *portfolioStorage) CancelBookOrder(ctx context.Context, userId int, id int) error {
+	sql := `UPDATE book_orders SET bo_state = 'cancelled', update_at = $1
+			WHERE bo_id = $2 AND bo_user_id = $3 AND bo_state IN ('open', 'partially_filled')`
+	tag, err := p.client.Exec(ctx, sql, time.Now(), id, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.New(errs.NotExist, errs.Code("active order not found"), errs.Parameter("id"), pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// ExpireBookOrders expires the active orders whose time in force is over and
+// returns how many orders were expired.
+func (p *portfolioStorage) ExpireBookOrders(ctx context.Context, now time.Time) (int64, error) {
+	sql := `UPDATE book_orders SET bo_state = 'expired', update_at = $1
+			WHERE bo_state IN ('open', 'partially_filled') AND bo_expires_at <= $1`
+	tag, err := p.client.Exec(ctx, sql, now)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// MatchBookOrder locks the order, the account and the position and passes them
+// to match. The updated order is stored together with the fill, if there is
+// one, in a single transaction. Orders that are no longer active are returned
+// without calling match.
+func (p *portfolioStorage) MatchBookOrder(
+	ctx context.Context,
+	id int,
+	initialCash float64,
+	match func(order entity.BookOrder, account entity.Account, position entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error),
+) (entity.BookOrder, error) {
+	var order entity.BookOrder
+	err := p.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, selectBookOrder+` WHERE bo_id = $1 FOR UPDATE`, id)
+		if err != nil {
+			return err
+		}
+		orders, err := scanBookOrders(rows)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return errs.New(errs.Validation, errs.Code("order not found"), errs.Parameter("id"), pgx.ErrNoRows)
+		}
+		order = orders[0]
+		if !order.Active() {
+			return nil
+		}
+		account, position, err := lock(ctx, tx, order.UserId, order.Symbol, initialCash)
+		if err != nil {
+			return err
+		}
+		var filled *entity.Order
+		order, account, position, filled, err = match(order, account, position)
+		if err != nil {
+			return err
+		}
+		sql := `UPDATE book_orders SET bo_filled = $1, bo_triggered = $2, bo_state = $3, update_at = $4 WHERE bo_id = $5`
+		_, err = tx.Exec(ctx, sql, order.Filled, order.Triggered, string(order.State), time.Now(), order.Id)
+		if err != nil {
+			return err
+		}
+		if filled == nil {
+			return nil
+		}
+		err = store(ctx, tx, account, position)
+		if err != nil {
+			return err
+		}
+		return insertOrder(ctx, tx, filled)
+	})
+	if err != nil {
+		return entity.BookOrder{}, err
+	}
+	return order, nil
+}
+
+func scanBookOrders(rows pgx.Rows) ([]entity.BookOrder, error) {
+	defer rows.Close()
+	orders := make([]entity.BookOrder, 0)
+	for rows.Next() {
+		var order entity.BookOrder
+		var side, orderType, tif, state string
+		var expiresAt *time.Time
+		err := rows.Scan(&order.Id, &order.UserId, &order.Symbol, &side, &orderType, &order.Quantity, &order.Filled,
+			&order.LimitPrice, &order.StopPrice, &order.Triggered, &tif, &state, &expiresAt, &order.CreateAt, &order.UpdateAt)
+		if err != nil {
+			return nil, err
+		}
+		order.Side = entity.OrderSide(side)
+		order.Type = entity.OrderType(orderType)
+		order.TimeInForce = entity.TimeInForce(tif)
+		order.State = entity.OrderState(state)
+		if expiresAt != nil {
+			order.ExpiresAt = *expiresAt
+		}
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,144 @@
+package portfoliostorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/adapter/portfolioStorage/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+var bookColumns = []string{"bo_id", "bo_user_id", "bo_symbol", "bo_side", "bo_type", "bo_quantity", "bo_filled", "bo_limit_price",
+	"bo_stop_price", "bo_triggered", "bo_tif", "bo_state", "bo_expires_at", "create_at", "update_at"}
+
+func TestCancelBookOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mock     mockCall
+		isError  bool
+		notExist bool
+	}{
+		{
+			title: "Should cancel active order",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), 3, 1).Return(pgconn.CommandTag("UPDATE 1"), nil)
+			},
+		},
+		{
+			title: "Should return error when there is no active order",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), 3, 1).Return(pgconn.CommandTag("UPDATE 0"), nil)
+			},
+			isError:  true,
+			notExist: true,
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), 3, 1).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			err := storage.CancelBookOrder(context.Background(), 1, 3)
+			if test.isError {
+				assert.Error(t, err)
+				var e *errs.Error
+				assert.Equal(t, test.notExist, errors.As(err, &e) && e.Kind == errs.NotExist)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestMatchBookOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	tx := mocks.NewMockTx(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	createAt := time.Now()
+	ok := pgconn.CommandTag("OK")
+	bookRows := func(state string) pgx.Rows {
+		return pgxpoolmock.NewRows(bookColumns).
+			AddRow(3, 1, "AAPL", "buy", "limit", int64(2), int64(0), 110.0, 0.0, false, "GTC", state, nil, createAt, createAt).
+			ToPgxRows()
+	}
+	type mockCall func()
+	testCases := []struct {
+		title     string
+		mock      mockCall
+		wantState entity.OrderState
+		isError   bool
+	}{
+		{
+			title: "Should store the fill together with the order",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(pgx.Tx) error) error { return f(tx) })
+				tx.EXPECT().Query(gomock.Any(), gomock.Any(), 3).Return(bookRows("open"), nil)
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 1, 1000.0, gomock.Any()).Return(ok, nil)
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1).Return(valuesRow{Values: []interface{}{1000.0, 0.0}})
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1, "AAPL").Return(valuesRow{Err: pgx.ErrNoRows})
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), int64(2), false, "filled", gomock.Any(), 3).Return(ok, nil)
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 800.0, 0.0, 1).Return(ok, nil)
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 1, "AAPL", int64(2), 100.0).Return(ok, nil)
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1, "AAPL", "buy", int64(2), 100.0, 0.0, gomock.Any()).
+					Return(valuesRow{Values: []interface{}{9, createAt}})
+			},
+			wantState: entity.Filled,
+		},
+		{
+			title: "Should skip order that is no longer active",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(pgx.Tx) error) error { return f(tx) })
+				tx.EXPECT().Query(gomock.Any(), gomock.Any(), 3).Return(bookRows("cancelled"), nil)
+			},
+			wantState: entity.Cancelled,
+		},
+		{
+			title: "Should return error when transaction can't start",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.MatchBookOrder(context.Background(), 3, 1000, func(order entity.BookOrder, account entity.Account, position entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error) {
+				filled := entity.Order{UserId: order.UserId, Symbol: order.Symbol, Side: order.Side, Quantity: order.Quantity, Price: 100}
+				account.Cash -= 200
+				position.Quantity, position.AverageCost = 2, 100
+				order.Filled, order.State = 2, entity.Filled
+				return order, account, position, &filled, nil
+			})
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantState, got.State)
+			}
+		})
+	}
+}
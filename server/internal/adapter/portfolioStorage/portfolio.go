@@ -0,0 +1,170 @@
+package portfoliostorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DbClient interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+	BeginFunc(ctx context.Context, f func(pgx.Tx) error) error
+}
+
+type portfolioStorage struct {
+	logger *logging.Logger
+	client DbClient
+}
+
+func New(logger *logging.Logger, client DbClient) *portfolioStorage {
+	return &portfolioStorage{logger: logger, client: client}
+}
+
+// Account returns the account and the open positions of the user, the account
+// is opened with initialCash on the first call.
+func (p *portfolioStorage) Account(ctx context.Context, userId int, initialCash float64) (entity.Account, []entity.Position, error) {
+	err := open(ctx, p.client, userId, initialCash)
+	if err != nil {
+		return entity.Account{}, nil, err
+	}
+	account := entity.Account{UserId: userId}
+	sql := `SELECT p_cash,p_realized FROM portfolios WHERE p_user_id = $1`
+	err = p.client.QueryRow(ctx, sql, userId).Scan(&account.Cash, &account.Realized)
+	if err != nil {
+		return entity.Account{}, nil, err
+	}
+	sql = `SELECT pos_symbol,pos_quantity,pos_avg_cost FROM positions WHERE pos_user_id = $1 ORDER BY pos_symbol`
+	rows, err := p.client.Query(ctx, sql, userId)
+	if err != nil {
+		return entity.Account{}, nil, err
+	}
+	defer rows.Close()
+	positions := make([]entity.Position, 0)
+	for rows.Next() {
+		var position entity.Position
+		err := rows.Scan(&position.Symbol, &position.Quantity, &position.AverageCost)
+		if err != nil {
+			return entity.Account{}, nil, err
+		}
+		positions = append(positions, position)
+	}
+	return account, positions, rows.Err()
+}
+
+// Execute locks the account and the position of the order symbol, applies
+// fill to them and stores the result together with the order in a single
+// transaction. Nothing is stored if fill returns an error.
+func (p *portfolioStorage) Execute(
+	ctx context.Context,
+	order entity.Order,
+	initialCash float64,
+	fill func(account entity.Account, position entity.Position) (entity.Account, entity.Position, entity.Order, error),
+) (entity.Order, error) {
+	var filled entity.Order
+	err := p.client.BeginFunc(ctx, func(tx pgx.Tx) error {
+		account, position, err := lock(ctx, tx, order.UserId, order.Symbol, initialCash)
+		if err != nil {
+			return err
+		}
+		account, position, filled, err = fill(account, position)
+		if err != nil {
+			return err
+		}
+		err = store(ctx, tx, account, position)
+		if err != nil {
+			return err
+		}
+		return insertOrder(ctx, tx, &filled)
+	})
+	if err != nil {
+		return entity.Order{}, err
+	}
+	return filled, nil
+}
+
+func (p *portfolioStorage) Orders(ctx context.Context, userId int, limit int) ([]entity.Order, error) {
+	sql := `SELECT o_id,o_symbol,o_side,o_quantity,o_price,o_realized,create_at FROM orders
+			WHERE o_user_id = $1 ORDER BY o_id DESC LIMIT $2`
+	rows, err := p.client.Query(ctx, sql, userId, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	orders := make([]entity.Order, 0)
+	for rows.Next() {
+		order := entity.Order{UserId: userId}
+		var side string
+		err := rows.Scan(&order.Id, &order.Symbol, &side, &order.Quantity, &order.Price, &order.Realized, &order.CreateAt)
+		if err != nil {
+			return nil, err
+		}
+		order.Side = entity.OrderSide(side)
+		orders = append(orders, order)
+	}
+	return orders, rows.Err()
+}
+
+type executor interface {
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+// lock opens the account if needed and locks it together with the position
+// of the symbol until the end of the transaction.
+func lock(ctx context.Context, tx pgx.Tx, userId int, symbol string, initialCash float64) (entity.Account, entity.Position, error) {
+	err := open(ctx, tx, userId, initialCash)
+	if err != nil {
+		return entity.Account{}, entity.Position{}, err
+	}
+	account := entity.Account{UserId: userId}
+	sql := `SELECT p_cash,p_realized FROM portfolios WHERE p_user_id = $1 FOR UPDATE`
+	err = tx.QueryRow(ctx, sql, userId).Scan(&account.Cash, &account.Realized)
+	if err != nil {
+		return entity.Account{}, entity.Position{}, err
+	}
+	position := entity.Position{Symbol: symbol}
+	sql = `SELECT pos_quantity,pos_avg_cost FROM positions WHERE pos_user_id = $1 AND pos_symbol = $2 FOR UPDATE`
+	err = tx.QueryRow(ctx, sql, userId, symbol).Scan(&position.Quantity, &position.AverageCost)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return entity.Account{}, entity.Position{}, err
+	}
+	return account, position, nil
+}
+
+func store(ctx context.Context, tx pgx.Tx, account entity.Account, position entity.Position) error {
+	sql := `UPDATE portfolios SET p_cash = $1, p_realized = $2 WHERE p_user_id = $3`
+	_, err := tx.Exec(ctx, sql, account.Cash, account.Realized, account.UserId)
+	if err != nil {
+		return err
+	}
+	if position.Quantity == 0 {
+		sql = `DELETE FROM positions WHERE pos_user_id = $1 AND pos_symbol = $2`
+		_, err = tx.Exec(ctx, sql, account.UserId, position.Symbol)
+		return err
+	}
+	sql = `INSERT INTO positions(pos_user_id,pos_symbol,pos_quantity,pos_avg_cost) VALUES ($1,$2,$3,$4)
+			ON CONFLICT (pos_user_id,pos_symbol) DO UPDATE SET
+			pos_quantity = EXCLUDED.pos_quantity, pos_avg_cost = EXCLUDED.pos_avg_cost`
+	_, err = tx.Exec(ctx, sql, account.UserId, position.Symbol, position.Quantity, position.AverageCost)
+	return err
+}
+
+func insertOrder(ctx context.Context, tx pgx.Tx, order *entity.Order) error {
+	sql := `INSERT INTO orders(o_user_id,o_symbol,o_side,o_quantity,o_price,o_realized,create_at)
+			VALUES ($1,$2,$3,$4,$5,$6,$7) RETURNING o_id,create_at`
+	return tx.QueryRow(ctx, sql, order.UserId, order.Symbol, string(order.Side), order.Quantity, order.Price, order.Realized, time.Now()).
+		Scan(&order.Id, &order.CreateAt)
+}
+
+func open(ctx context.Context, client executor, userId int, initialCash float64) error {
+	sql := `INSERT INTO portfolios(p_user_id,p_cash,p_realized,create_at) VALUES ($1,$2,0,$3)
+			ON CONFLICT (p_user_id) DO NOTHING`
+	_, err := client.Exec(ctx, sql, userId, initialCash, time.Now())
+	return err
+}
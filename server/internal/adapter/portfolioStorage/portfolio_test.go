@@ -0,0 +1,117 @@
+package portfoliostorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/adapter/portfolioStorage/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type valuesRow struct {
+	Values []interface{}
+	Err    error
+}
+
+func (this valuesRow) Scan(dest ...interface{}) error {
+	if this.Err != nil {
+		return this.Err
+	}
+	for i, value := range this.Values {
+		switch d := dest[i].(type) {
+		case *float64:
+			*d = value.(float64)
+		case *int64:
+			*d = value.(int64)
+		case *int:
+			*d = value.(int)
+		case *time.Time:
+			*d = value.(time.Time)
+		}
+	}
+	return nil
+}
+
+func TestExecute(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	tx := mocks.NewMockTx(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	createAt := time.Now()
+	order := entity.Order{UserId: 1, Symbol: "AAPL", Side: entity.Sell, Quantity: 2, Price: 120}
+	ok := pgconn.CommandTag("OK")
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		fillErr error
+		want    entity.Order
+		isError bool
+	}{
+		{
+			title: "Should store the filled order and close the position",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(pgx.Tx) error) error { return f(tx) })
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 1, 1000.0, gomock.Any()).Return(ok, nil)
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1).Return(valuesRow{Values: []interface{}{500.0, 0.0}})
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1, "AAPL").Return(valuesRow{Values: []interface{}{int64(2), 100.0}})
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 740.0, 40.0, 1).Return(ok, nil)
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 1, "AAPL").Return(ok, nil)
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1, "AAPL", "sell", int64(2), 120.0, 40.0, gomock.Any()).
+					Return(valuesRow{Values: []interface{}{9, createAt}})
+			},
+			want: entity.Order{Id: 9, UserId: 1, Symbol: "AAPL", Side: entity.Sell, Quantity: 2, Price: 120, Realized: 40, CreateAt: createAt},
+		},
+		{
+			title: "Should not store anything when fill fails",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, f func(pgx.Tx) error) error { return f(tx) })
+				tx.EXPECT().Exec(gomock.Any(), gomock.Any(), 1, 1000.0, gomock.Any()).Return(ok, nil)
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1).Return(valuesRow{Values: []interface{}{500.0, 0.0}})
+				tx.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 1, "AAPL").Return(valuesRow{Err: pgx.ErrNoRows})
+			},
+			fillErr: errors.New("insufficient shares"),
+			isError: true,
+		},
+		{
+			title: "Should return error when transaction can't start",
+			mock: func() {
+				mockPool.EXPECT().BeginFunc(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Execute(context.Background(), order, 1000, func(account entity.Account, position entity.Position) (entity.Account, entity.Position, entity.Order, error) {
+				if test.fillErr != nil {
+					return account, position, order, test.fillErr
+				}
+				filled := order
+				filled.Realized = (order.Price - position.AverageCost) * float64(order.Quantity)
+				account.Cash += order.Price * float64(order.Quantity)
+				account.Realized += filled.Realized
+				position.Quantity -= order.Quantity
+				return account, position, filled, nil
+			})
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package quotebus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/go-redis/redis"
+)
+
+const (
+	channelPrefix = "quotes:"
+	defaultLease  = 15 * time.Second
+)
+
+// QuoteListener receives every quote published by any replica, including the
+// quotes published by this one. OnQuote is called from a single goroutine and
+// must not block.
+type QuoteListener interface {
+	OnQuote(tick entity.Tick)
+}
+
+type Options struct {
+	Lease time.Duration
+}
+
+type bus struct {
+	logger    *logging.Logger
+	client    *redis.Client
+	owner     string
+	options   Options
+	listeners []QuoteListener
+
+	pubsub *redis.PubSub
+	wg     sync.WaitGroup
+}
+
+// New returns a quote bus shared by the replicas through Redis. Quotes are
+// published on a channel per symbol and the symbol is refreshed only by the
+// replica that holds its lease.
+func New(logger *logging.Logger, client *redis.Client, options Options) *bus {
+	if options.Lease <= 0 {
+		options.Lease = defaultLease
+	}
+	return &bus{logger: logger, client: client, owner: newOwner(), options: options}
+}
+
+// Subscribe adds a listener of quotes, it must be called before Start.
+func (b *bus) Subscribe(listener QuoteListener) {
+	b.listeners = append(b.listeners, listener)
+}
+
+// Start subscribes to the quote channels of all symbols. The subscription is
+// restored by the client after Redis reconnects.
+func (b *bus) Start() error {
+	pubsub := b.client.PSubscribe(channelPrefix + "*")
+	if _, err := pubsub.Receive(); err != nil {
+		pubsub.Close()
+		return errs.New(errs.Database, errs.Code("cannot subscribe to quotes"), err)
+	}
+	b.pubsub = pubsub
+	messages := pubsub.Channel()
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for message := range messages {
+			b.dispatch(message)
+		}
+	}()
+	return nil
+}
+
+// Close stops the subscription, the leases expire on their own.
+func (b *bus) Close() error {
+	if b.pubsub == nil {
+		return nil
+	}
+	err := b.pubsub.Close()
+	b.wg.Wait()
+	return err
+}
+
+func (b *bus) Publish(tick entity.Tick) error {
+	payload, err := json.Marshal(messageFromTick(tick))
+	if err != nil {
+		return errs.New(errs.Internal, errs.Code("cannot encode quote"), err)
+	}
+	err = b.client.Publish(channelPrefix+tick.Symbol, payload).Err()
+	if err != nil {
+		return errs.New(errs.Database, errs.Code("cannot publish quote"), errs.Parameter(tick.Symbol), err)
+	}
+	return nil
+}
+
+func (b *bus) dispatch(message *redis.Message) {
+	var m quoteMessage
+	err := json.Unmarshal([]byte(message.Payload), &m)
+	if err != nil {
+		b.logger.Errorf("cannot decode quote from %v due to : %v", message.Channel, err)
+		return
+	}
+	tick := m.toTick()
+	if tick.Symbol != strings.TrimPrefix(message.Channel, channelPrefix) {
+		b.logger.Errorf("quote of %v is published on %v", tick.Symbol, message.Channel)
+		return
+	}
+	for _, listener := range b.listeners {
+		listener.OnQuote(tick)
+	}
+}
+
+func newOwner() string {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return time.Now().String()
+	}
+	return hex.EncodeToString(token)
+}
@@ -0,0 +1,100 @@
+package quotebus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+type listener chan entity.Tick
+
+func (l listener) OnQuote(tick entity.Tick) {
+	l <- tick
+}
+
+func newClient(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	server, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() {
+		client.Close()
+		server.Close()
+	})
+	return server, client
+}
+
+func TestCampaign(t *testing.T) {
+	server, client := newClient(t)
+	logger := logging.GetLogger("debug")
+	first := New(logger, client, Options{Lease: 10 * time.Second})
+	second := New(logger, client, Options{Lease: 10 * time.Second})
+	ctx := context.Background()
+
+	assert.True(t, first.Campaign(ctx, "AAPL"), "a free lease is taken")
+	assert.False(t, second.Campaign(ctx, "AAPL"), "a held lease is not taken")
+	assert.True(t, second.Campaign(ctx, "MSFT"), "leases are per symbol")
+
+	server.FastForward(8 * time.Second)
+	assert.True(t, first.Campaign(ctx, "AAPL"), "the holder renews the lease")
+	assert.Equal(t, 10*time.Second, server.TTL(leasePrefix+"AAPL"))
+
+	second.Resign("AAPL")
+	assert.False(t, second.Campaign(ctx, "AAPL"), "only the holder can resign")
+	first.Resign("AAPL")
+	assert.True(t, second.Campaign(ctx, "AAPL"), "a resigned lease is free")
+
+	server.FastForward(11 * time.Second)
+	assert.True(t, first.Campaign(ctx, "AAPL"), "an expired lease is free")
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, first.Campaign(cancelled, "TSLA"))
+
+	server.SetError("internal redis error")
+	assert.True(t, second.Campaign(ctx, "AAPL"), "every replica refreshes when redis fails")
+}
+
+func TestPublish(t *testing.T) {
+	_, client := newClient(t)
+	logger := logging.GetLogger("debug")
+	publisher := New(logger, client, Options{})
+	subscriber := New(logger, client, Options{})
+	received := make(listener, 1)
+	subscriber.Subscribe(received)
+	assert.NoError(t, subscriber.Start())
+	defer subscriber.Close()
+
+	tick := entity.Tick{
+		Id:     7,
+		Symbol: "AAPL",
+		Price:  110,
+		Change: 10,
+		Time:   time.Unix(1700000000, 0).UTC(),
+		Bar:    entity.Bar{Symbol: "AAPL", Timestamp: time.Unix(1699999860, 0).UTC(), Open: 105, High: 111, Low: 104, Close: 110, Volume: 2000},
+	}
+	assert.NoError(t, publisher.Publish(tick))
+	select {
+	case got := <-received:
+		assert.Equal(t, tick, got)
+	case <-time.After(time.Second):
+		t.Fatal("no quote")
+	}
+
+	assert.NoError(t, client.Publish(channelPrefix+"MSFT", `{"symbol":"AAPL"}`).Err())
+	assert.NoError(t, client.Publish(channelPrefix+"MSFT", `not a quote`).Err())
+	assert.NoError(t, publisher.Publish(entity.Tick{Id: 8, Symbol: "MSFT", Price: 300, Time: time.Unix(1700000000, 0).UTC()}))
+	select {
+	case got := <-received:
+		assert.Equal(t, entity.Tick{Id: 8, Symbol: "MSFT", Price: 300, Time: time.Unix(1700000000, 0).UTC()}, got, "malformed quotes are dropped")
+	case <-time.After(time.Second):
+		t.Fatal("no quote")
+	}
+}
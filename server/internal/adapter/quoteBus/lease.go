@@ -0,0 +1,56 @@
+package quotebus
+
+import (
+	"context"
+
+	"github.com/go-redis/redis"
+)
+
+const leasePrefix = "quotes:lease:"
+
+// campaign takes the lease if it is free and extends it if this replica
+// already holds it.
+var campaign = redis.NewScript(`
+local owner = redis.call('GET', KEYS[1])
+if owner == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return 1
+end
+if not owner then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+	return 1
+end
+return 0
+`)
+
+var resign = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Campaign reports whether this replica should refresh the key, a symbol of
+// the stream or a chart. It must be called more often than the lease expires
+// to keep the lease. If Redis can't be reached every replica refreshes the key
+// on its own.
+func (b *bus) Campaign(ctx context.Context, key string) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	held, err := campaign.Run(b.client, []string{leasePrefix + key}, b.owner, b.options.Lease.Milliseconds()).Int()
+	if err != nil {
+		b.logger.Errorf("cannot campaign for %v due to : %v", key, err)
+		return true
+	}
+	return held == 1
+}
+
+// Resign releases the lease of the key if this replica holds it, so that
+// another replica can take over without waiting for the lease to expire.
+func (b *bus) Resign(key string) {
+	err := resign.Run(b.client, []string{leasePrefix + key}, b.owner).Err()
+	if err != nil {
+		b.logger.Errorf("cannot resign from %v due to : %v", key, err)
+	}
+}
@@ -0,0 +1,57 @@
+package quotebus
+
+import (
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type barMessage struct {
+	Timestamp int64   `json:"t"`
+	Open      float64 `json:"o"`
+	High      float64 `json:"h"`
+	Low       float64 `json:"l"`
+	Close     float64 `json:"c"`
+	Volume    float64 `json:"v"`
+}
+
+// quoteMessage is the normalized quote sent between the replicas.
+type quoteMessage struct {
+	Id     uint64      `json:"id"`
+	Symbol string      `json:"symbol"`
+	Price  float64     `json:"price"`
+	Change float64     `json:"change"`
+	Time   int64       `json:"time"`
+	Bar    *barMessage `json:"bar,omitempty"`
+}
+
+func messageFromTick(tick entity.Tick) quoteMessage {
+	m := quoteMessage{Id: tick.Id, Symbol: tick.Symbol, Price: tick.Price, Change: tick.Change, Time: tick.Time.Unix()}
+	if !tick.Bar.Timestamp.IsZero() {
+		m.Bar = &barMessage{
+			Timestamp: tick.Bar.Timestamp.Unix(),
+			Open:      tick.Bar.Open,
+			High:      tick.Bar.High,
+			Low:       tick.Bar.Low,
+			Close:     tick.Bar.Close,
+			Volume:    tick.Bar.Volume,
+		}
+	}
+	return m
+}
+
+func (m quoteMessage) toTick() entity.Tick {
+	tick := entity.Tick{Id: m.Id, Symbol: m.Symbol, Price: m.Price, Change: m.Change, Time: time.Unix(m.Time, 0).UTC()}
+	if m.Bar != nil {
+		tick.Bar = entity.Bar{
+			Symbol:    m.Symbol,
+			Timestamp: time.Unix(m.Bar.Timestamp, 0).UTC(),
+			Open:      m.Bar.Open,
+			High:      m.Bar.High,
+			Low:       m.Bar.Low,
+			Close:     m.Bar.Close,
+			Volume:    m.Bar.Volume,
+		}
+	}
+	return tick
+}
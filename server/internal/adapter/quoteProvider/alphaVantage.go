@@ -0,0 +1,230 @@
+package quoteprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const AlphaVantageUrl string = "https://www.alphavantage.co/query?function=TIME_SERIES_DAILY&symbol=%v&apikey=%v"
+
+const AlphaVantageProfileUrl string = "https://www.alphavantage.co/query?function=OVERVIEW&symbol=%v&apikey=%v"
+
+const dayLayout string = "2006-01-02"
+
+type alphaVantageResponse struct {
+	MetaData struct {
+		Symbol   string `json:"2. Symbol"`
+		TimeZone string `json:"5. Time Zone"`
+	} `json:"Meta Data"`
+	TimeSeries   map[string]alphaVantageBar `json:"Time Series (Daily)"`
+	ErrorMessage string                     `json:"Error Message"`
+	Note         string                     `json:"Note"`
+	Information  string                     `json:"Information"`
+}
+
+type alphaVantageBar struct {
+	Open   string `json:"1. open"`
+	High   string `json:"2. high"`
+	Low    string `json:"3. low"`
+	Close  string `json:"4. close"`
+	Volume string `json:"5. volume"`
+}
+
+// alphaVantageOverview is the company overview of Alpha Vantage, the numbers
+// are strings and "None" when they are unknown.
+type alphaVantageOverview struct {
+	Symbol        string `json:"Symbol"`
+	Name          string `json:"Name"`
+	Exchange      string `json:"Exchange"`
+	Sector        string `json:"Sector"`
+	Industry      string `json:"Industry"`
+	Currency      string `json:"Currency"`
+	MarketCap     string `json:"MarketCapitalization"`
+	PERatio       string `json:"PERatio"`
+	DividendYield string `json:"DividendYield"`
+	Week52Low     string `json:"52WeekLow"`
+	Week52High    string `json:"52WeekHigh"`
+	ErrorMessage  string `json:"Error Message"`
+	Note          string `json:"Note"`
+	Information   string `json:"Information"`
+}
+
+type alphaVantageProvider struct {
+	logger     *logging.Logger
+	client     HttpClient
+	url        string
+	profileUrl string
+	apiKey     string
+}
+
+func NewAlphaVantageProvider(logger *logging.Logger, client HttpClient, url string, profileUrl string, apiKey string) *alphaVantageProvider {
+	if url == "" {
+		url = AlphaVantageUrl
+	}
+	if profileUrl == "" {
+		profileUrl = AlphaVantageProfileUrl
+	}
+	return &alphaVantageProvider{logger: logger, client: client, url: url, profileUrl: profileUrl, apiKey: apiKey}
+}
+
+func (a *alphaVantageProvider) Name() string {
+	return "alphavantage"
+}
+
+func (a *alphaVantageProvider) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	if !isDaily(query) {
+		return entity.Series{}, errs.New(errs.Invalid, errs.Code("alpha vantage supports only daily interval"), errs.Parameter("interval"), "alpha vantage supports only daily interval")
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(a.url, url.QueryEscape(query.Symbol), url.QueryEscape(a.apiKey)), nil)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return entity.Series{}, errs.New(errs.Internal, fmt.Sprintf("alpha vantage responded with status %v", resp.StatusCode))
+	}
+	var payload alphaVantageResponse
+	err = json.NewDecoder(resp.Body).Decode(&payload)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	switch {
+	case payload.ErrorMessage != "":
+		// the error message is the answer to an unknown symbol
+		return entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), payload.ErrorMessage)
+	case payload.Note != "":
+		return entity.Series{}, errs.New(errs.Internal, payload.Note)
+	case len(payload.TimeSeries) == 0 && payload.Information != "":
+		return entity.Series{}, errs.New(errs.Internal, payload.Information)
+	}
+	series, err := a.toSeries(query.Symbol, payload)
+	if err != nil {
+		return entity.Series{}, err
+	}
+	trim(&series, query, time.Now())
+	return series, nil
+}
+
+// GetProfile reads the fundamentals from the company overview.
+func (a *alphaVantageProvider) GetProfile(ctx context.Context, symbol string) (entity.Profile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(a.profileUrl, url.QueryEscape(symbol), url.QueryEscape(a.apiKey)), nil)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return entity.Profile{}, errs.New(errs.Internal, fmt.Sprintf("alpha vantage responded with status %v", resp.StatusCode))
+	}
+	var payload alphaVantageOverview
+	err = json.NewDecoder(resp.Body).Decode(&payload)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	switch {
+	case payload.ErrorMessage != "":
+		return entity.Profile{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), payload.ErrorMessage)
+	case payload.Note != "":
+		return entity.Profile{}, errs.New(errs.Internal, payload.Note)
+	case payload.Information != "":
+		return entity.Profile{}, errs.New(errs.Internal, payload.Information)
+	case payload.Symbol == "":
+		return entity.Profile{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), "alpha vantage returned no overview")
+	}
+	return entity.Profile{
+		Symbol:        payload.Symbol,
+		Name:          payload.Name,
+		Exchange:      payload.Exchange,
+		Sector:        payload.Sector,
+		Industry:      payload.Industry,
+		Currency:      payload.Currency,
+		MarketCap:     optionalFloat(payload.MarketCap),
+		PERatio:       optionalFloat(payload.PERatio),
+		DividendYield: optionalFloat(payload.DividendYield),
+		Week52Low:     optionalFloat(payload.Week52Low),
+		Week52High:    optionalFloat(payload.Week52High),
+	}, nil
+}
+
+func (a *alphaVantageProvider) toSeries(symbol string, payload alphaVantageResponse) (entity.Series, error) {
+	location := time.UTC
+	if payload.MetaData.TimeZone != "" {
+		if loc, err := time.LoadLocation(payload.MetaData.TimeZone); err == nil {
+			location = loc
+		}
+	}
+	dates := make([]string, 0, len(payload.TimeSeries))
+	for date := range payload.TimeSeries {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	series := entity.Series{Symbol: symbol, Bars: make([]entity.Bar, 0, len(dates))}
+	for _, date := range dates {
+		t, err := time.ParseInLocation(dayLayout, date, location)
+		if err != nil {
+			return entity.Series{}, errs.New(errs.Internal, err)
+		}
+		values, err := parseFloats(payload.TimeSeries[date])
+		if err != nil {
+			return entity.Series{}, errs.New(errs.Internal, err)
+		}
+		series.Bars = append(series.Bars, entity.Bar{
+			Timestamp: t.UTC(),
+			Open:      values[0],
+			High:      values[1],
+			Low:       values[2],
+			Close:     values[3],
+			Volume:    values[4],
+		})
+	}
+	if payload.MetaData.Symbol != "" {
+		series.Symbol = payload.MetaData.Symbol
+	}
+	if n := len(series.Bars); n > 0 {
+		series.PriceTime = series.Bars[n-1].Timestamp
+		series.Price = series.Bars[n-1].Close
+	}
+	if n := len(series.Bars); n > 1 {
+		series.PreviousClose = series.Bars[n-2].Close
+	}
+	return series, nil
+}
+
+func parseFloats(bar alphaVantageBar) ([5]float64, error) {
+	var values [5]float64
+	for i, raw := range []string{bar.Open, bar.High, bar.Low, bar.Close, bar.Volume} {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return values, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// optionalFloat parses a number of the overview, "None" and "-" are nil.
+func optionalFloat(raw string) *float64 {
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,164 @@
+package quoteprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAlphaVantageGetChart(t *testing.T) {
+	testCases := []struct {
+		title     string
+		query     entity.ChartQuery
+		body      string
+		isError   bool
+		wantKind  errs.Kind
+		wantClose []float64
+	}{
+		{
+			title: "success conversion sorted by date",
+			query: entity.ChartQuery{Symbol: "TEST"},
+			body: `{"Meta Data":{"2. Symbol":"TEST","5. Time Zone":"UTC"},"Time Series (Daily)":{
+				"2022-10-04":{"1. open":"2","2. high":"3","3. low":"1","4. close":"2.5","5. volume":"200"},
+				"2022-10-03":{"1. open":"1","2. high":"2","3. low":"0.5","4. close":"1.5","5. volume":"100"}}}`,
+			wantClose: []float64{1.5, 2.5},
+		},
+		{
+			title:    "unknown symbol and return not exist error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			body:     `{"Error Message":"Invalid API call"}`,
+			isError:  true,
+			wantKind: errs.NotExist,
+		},
+		{
+			title:    "rate limit note and return error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			body:     `{"Note":"Thank you for using Alpha Vantage!"}`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+		{
+			title:    "intraday interval and return unsupported error",
+			query:    entity.ChartQuery{Symbol: "TEST", Interval: "1m"},
+			isError:  true,
+			wantKind: errs.Invalid,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, test.query.Symbol, r.URL.Query().Get("symbol"))
+				assert.Equal(t, "key", r.URL.Query().Get("apikey"))
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			provider := NewAlphaVantageProvider(logging.GetLogger("debug"), server.Client(), server.URL+"/?symbol=%v&apikey=%v", "", "key")
+			series, err := provider.GetChart(context.Background(), test.query)
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			closes := make([]float64, len(series.Bars))
+			for i, bar := range series.Bars {
+				closes[i] = bar.Close
+			}
+			assert.Equal(t, test.wantClose, closes)
+			assert.Equal(t, 2.5, series.Price)
+			assert.Equal(t, 1.5, series.PreviousClose)
+			assert.Equal(t, series.Bars[1].Timestamp, series.PriceTime)
+		})
+	}
+}
+
+func TestAlphaVantageGetProfile(t *testing.T) {
+	capitalization, dividend := 2.5e12, 0.0055
+	testCases := []struct {
+		title    string
+		body     string
+		isError  bool
+		wantKind errs.Kind
+		want     entity.Profile
+	}{
+		{
+			title: "success overview conversion, unknown values are nil",
+			body: `{"Symbol":"TEST","Name":"Apple Inc","Exchange":"NASDAQ","Currency":"USD","Sector":"TECHNOLOGY","Industry":"ELECTRONIC COMPUTERS",
+				"MarketCapitalization":"2500000000000","PERatio":"None","DividendYield":"0.0055","52WeekHigh":"-","52WeekLow":""}`,
+			want: entity.Profile{
+				Symbol:        "TEST",
+				Name:          "Apple Inc",
+				Exchange:      "NASDAQ",
+				Sector:        "TECHNOLOGY",
+				Industry:      "ELECTRONIC COMPUTERS",
+				Currency:      "USD",
+				MarketCap:     &capitalization,
+				DividendYield: &dividend,
+			},
+		},
+		{
+			title:    "empty overview and return not exist error",
+			body:     `{}`,
+			isError:  true,
+			wantKind: errs.NotExist,
+		},
+		{
+			title:    "unknown symbol and return not exist error",
+			body:     `{"Error Message":"Invalid API call"}`,
+			isError:  true,
+			wantKind: errs.NotExist,
+		},
+		{
+			title:    "rate limit note and return error",
+			body:     `{"Note":"Thank you for using Alpha Vantage!"}`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "TEST", r.URL.Query().Get("symbol"))
+				assert.Equal(t, "key", r.URL.Query().Get("apikey"))
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			provider := NewAlphaVantageProvider(logging.GetLogger("debug"), server.Client(), "", server.URL+"/?symbol=%v&apikey=%v", "key")
+			profile, err := provider.GetProfile(context.Background(), "TEST")
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, profile)
+		})
+	}
+}
+
+func TestAlphaVantageEscapesSymbol(t *testing.T) {
+	symbol := "TEST&function=OVERVIEW&apikey=other"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, []string{symbol}, query["symbol"])
+		assert.Equal(t, []string{"key"}, query["apikey"])
+		assert.Empty(t, query["function"])
+		w.Write([]byte(`{"Error Message":"Invalid API call"}`))
+	}))
+	defer server.Close()
+	template := server.URL + "/?symbol=%v&apikey=%v"
+	provider := NewAlphaVantageProvider(logging.GetLogger("debug"), server.Client(), template, template, "key")
+	_, err := provider.GetChart(context.Background(), entity.ChartQuery{Symbol: symbol})
+	assert.Error(t, err)
+	_, err = provider.GetProfile(context.Background(), symbol)
+	assert.Error(t, err)
+}
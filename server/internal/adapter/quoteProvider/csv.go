@@ -0,0 +1,129 @@
+package quoteprovider
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+var csvColumns = []string{"date", "open", "high", "low", "close", "volume"}
+
+// csvProvider reads charts from <dir>/<SYMBOL>.csv files in the format of
+// Yahoo's history download: Date,Open,High,Low,Close[,Adj Close],Volume.
+type csvProvider struct {
+	logger *logging.Logger
+	dir    string
+}
+
+func NewCsvProvider(logger *logging.Logger, dir string) *csvProvider {
+	return &csvProvider{logger: logger, dir: dir}
+}
+
+func (c *csvProvider) Name() string {
+	return "csv"
+}
+
+func (c *csvProvider) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	symbol := query.Symbol
+	if !isDaily(query) {
+		return entity.Series{}, errs.New(errs.Invalid, errs.Code("csv files contain only daily interval"), errs.Parameter("interval"), "csv files contain only daily interval")
+	}
+	if strings.ContainsAny(symbol, `/\`) {
+		return entity.Series{}, errs.New(errs.Validation, errs.Code("incorrect symbol"), errs.Parameter("symbol"), "incorrect symbol")
+	}
+	path := filepath.Join(c.dir, strings.ToUpper(symbol)+".csv")
+	c.logger.Debugf("try to read chart from %v", path)
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), err)
+		}
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	defer file.Close()
+	series, err := readCsv(file)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, fmt.Errorf("couldn't read %v: %w", path, err))
+	}
+	series.Symbol = strings.ToUpper(symbol)
+	trim(&series, query, time.Now())
+	return series, nil
+}
+
+// readCsv reads the bars of the file, a row with a null price becomes a
+// missing bar and a null volume becomes zero.
+func readCsv(r io.Reader) (entity.Series, error) {
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return entity.Series{}, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range csvColumns {
+		if _, ok := index[column]; !ok {
+			return entity.Series{}, fmt.Errorf("column %v is missing", column)
+		}
+	}
+
+	var series entity.Series
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return entity.Series{}, err
+		}
+		t, err := parseCsvTime(record[index["date"]])
+		if err != nil {
+			return entity.Series{}, err
+		}
+		var values [5]float64
+		missing := false
+		for i, column := range csvColumns[1:] {
+			raw := record[index[column]]
+			if raw == "null" || raw == "" {
+				missing = missing || column != "volume"
+				continue
+			}
+			values[i], err = strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return entity.Series{}, err
+			}
+		}
+		bar := entity.Bar{Timestamp: t.UTC(), Missing: true}
+		if !missing {
+			bar = entity.Bar{Timestamp: t.UTC(), Open: values[0], High: values[1], Low: values[2], Close: values[3], Volume: values[4]}
+		}
+		series.Bars = append(series.Bars, bar)
+	}
+	if last, ok := series.LastBar(); ok {
+		series.PriceTime = last.Timestamp
+		series.Price = last.Close
+	}
+	return series, nil
+}
+
+func parseCsvTime(raw string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	return time.Parse(dayLayout, raw)
+}
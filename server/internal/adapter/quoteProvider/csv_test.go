@@ -0,0 +1,59 @@
+package quoteprovider
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCsvGetChart(t *testing.T) {
+	dir := t.TempDir()
+	content := "Date,Open,High,Low,Close,Adj Close,Volume\n" +
+		"2022-10-03,1,2,0.5,1.5,1.5,100\n" +
+		"2022-10-04,2,3,1,2.5,2.5,200\n" +
+		"2022-10-05,null,null,null,null,null,0\n"
+	if err := os.WriteFile(filepath.Join(dir, "TEST.csv"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "BROKEN.csv"), []byte("Date,Close\n2022-10-03,1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	provider := NewCsvProvider(logging.GetLogger("debug"), dir)
+	testCases := []struct {
+		title    string
+		input    string
+		interval string
+		isError  bool
+		wantKind errs.Kind
+	}{
+		{title: "success reading csv file", input: "test"},
+		{title: "unknown symbol and return not exist error", input: "NONE", isError: true, wantKind: errs.NotExist},
+		{title: "missing columns and return internal error", input: "BROKEN", isError: true, wantKind: errs.Internal},
+		{title: "path in symbol and return validation error", input: "../TEST", isError: true, wantKind: errs.Validation},
+		{title: "intraday interval and return unsupported error", input: "TEST", interval: "5m", isError: true, wantKind: errs.Invalid},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			series, err := provider.GetChart(context.Background(), entity.ChartQuery{Symbol: test.input, Interval: test.interval})
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "TEST", series.Symbol)
+			assert.Len(t, series.Bars, 3)
+			assert.Equal(t, 200.0, series.Bars[1].Volume)
+			assert.True(t, series.Bars[2].Missing, "null prices become a missing bar")
+			assert.Equal(t, 2.5, series.Price)
+		})
+	}
+}
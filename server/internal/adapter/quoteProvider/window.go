@@ -0,0 +1,57 @@
+package quoteprovider
+
+import (
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+// isDaily reports whether the query can be served from daily bars, which is
+// the only granularity the file and Alpha Vantage providers have.
+func isDaily(query entity.ChartQuery) bool {
+	return query.Interval == "" || query.Interval == "1d"
+}
+
+// trim drops the bars that are outside of the requested range or period,
+// mimicking what Yahoo does on its side.
+func trim(series *entity.Series, query entity.ChartQuery, now time.Time) {
+	from, to := window(query, now)
+	bars := make([]entity.Bar, 0, len(series.Bars))
+	for _, bar := range series.Bars {
+		ts := bar.Timestamp.Unix()
+		if ts < from || (to != 0 && ts > to) {
+			continue
+		}
+		bars = append(bars, bar)
+	}
+	series.Bars = bars
+}
+
+func window(query entity.ChartQuery, now time.Time) (int64, int64) {
+	if query.Period1 != 0 {
+		return query.Period1, query.Period2
+	}
+	switch query.Range {
+	case "1d":
+		return now.AddDate(0, 0, -1).Unix(), 0
+	case "5d":
+		return now.AddDate(0, 0, -5).Unix(), 0
+	case "1mo":
+		return now.AddDate(0, -1, 0).Unix(), 0
+	case "3mo":
+		return now.AddDate(0, -3, 0).Unix(), 0
+	case "6mo":
+		return now.AddDate(0, -6, 0).Unix(), 0
+	case "1y":
+		return now.AddDate(-1, 0, 0).Unix(), 0
+	case "2y":
+		return now.AddDate(-2, 0, 0).Unix(), 0
+	case "5y":
+		return now.AddDate(-5, 0, 0).Unix(), 0
+	case "10y":
+		return now.AddDate(-10, 0, 0).Unix(), 0
+	case "ytd":
+		return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location()).Unix(), 0
+	}
+	return 0, 0
+}
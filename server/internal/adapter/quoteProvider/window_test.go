@@ -0,0 +1,38 @@
+package quoteprovider
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrim(t *testing.T) {
+	now := time.Date(2022, time.October, 10, 0, 0, 0, 0, time.UTC)
+	day := int(24 * time.Hour / time.Second)
+	base := int(now.Unix())
+	testCases := []struct {
+		title string
+		query entity.ChartQuery
+		want  []int
+	}{
+		{title: "without range keeps everything", query: entity.ChartQuery{}, want: []int{base - 10*day, base - 3*day, base - day}},
+		{title: "5d range keeps last days", query: entity.ChartQuery{Range: "5d"}, want: []int{base - 3*day, base - day}},
+		{title: "period keeps bars inside", query: entity.ChartQuery{Period1: int64(base - 4*day), Period2: int64(base - 2*day)}, want: []int{base - 3*day}},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			series := entity.Series{}
+			for i, ts := range []int{base - 10*day, base - 3*day, base - day} {
+				series.Bars = append(series.Bars, entity.Bar{Timestamp: time.Unix(int64(ts), 0), Close: float64(i)})
+			}
+			trim(&series, test.query, now)
+			got := make([]int, len(series.Bars))
+			for i, bar := range series.Bars {
+				got[i] = int(bar.Timestamp.Unix())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package quoteprovider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const YahooUrl string = "https://query1.finance.yahoo.com/v8/finance/chart/%v"
+
+const YahooProfileUrl string = "https://query2.finance.yahoo.com/v10/finance/quoteSummary/%v?modules=price,summaryProfile,summaryDetail"
+
+// yahooResponse mirrors the chart schema of Yahoo, values are pointers since
+// Yahoo returns nulls for the points without trades.
+type yahooResponse struct {
+	Chart struct {
+		Result []yahooResult `json:"result"`
+		Error  *yahooError   `json:"error"`
+	} `json:"chart"`
+}
+
+type yahooError struct {
+	Code        string `json:"code"`
+	Description string `json:"description"`
+}
+
+type yahooResult struct {
+	Meta struct {
+		Symbol             string   `json:"symbol"`
+		ExchangeName       string   `json:"exchangeName"`
+		RegularMarketTime  int64    `json:"regularMarketTime"`
+		RegularMarketPrice *float64 `json:"regularMarketPrice"`
+		ChartPreviousClose *float64 `json:"chartPreviousClose"`
+	} `json:"meta"`
+	Timestamp  []int64 `json:"timestamp"`
+	Indicators struct {
+		Quote []struct {
+			Open   []*float64 `json:"open"`
+			High   []*float64 `json:"high"`
+			Low    []*float64 `json:"low"`
+			Close  []*float64 `json:"close"`
+			Volume []*float64 `json:"volume"`
+		} `json:"quote"`
+	} `json:"indicators"`
+}
+
+type yahooValue struct {
+	Raw *float64 `json:"raw"`
+}
+
+type yahooSummaryResponse struct {
+	QuoteSummary struct {
+		Result []struct {
+			Price struct {
+				LongName     string     `json:"longName"`
+				ShortName    string     `json:"shortName"`
+				ExchangeName string     `json:"exchangeName"`
+				Currency     string     `json:"currency"`
+				MarketCap    yahooValue `json:"marketCap"`
+			} `json:"price"`
+			SummaryProfile struct {
+				Sector   string `json:"sector"`
+				Industry string `json:"industry"`
+			} `json:"summaryProfile"`
+			SummaryDetail struct {
+				TrailingPE       yahooValue `json:"trailingPE"`
+				DividendYield    yahooValue `json:"dividendYield"`
+				FiftyTwoWeekLow  yahooValue `json:"fiftyTwoWeekLow"`
+				FiftyTwoWeekHigh yahooValue `json:"fiftyTwoWeekHigh"`
+			} `json:"summaryDetail"`
+		} `json:"result"`
+		Error *yahooError `json:"error"`
+	} `json:"quoteSummary"`
+}
+
+type HttpClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+type yahooProvider struct {
+	logger     *logging.Logger
+	client     HttpClient
+	url        string
+	profileUrl string
+}
+
+func NewYahooProvider(logger *logging.Logger, client HttpClient, url string, profileUrl string) *yahooProvider {
+	if url == "" {
+		url = YahooUrl
+	}
+	if profileUrl == "" {
+		profileUrl = YahooProfileUrl
+	}
+	return &yahooProvider{logger: logger, client: client, url: url, profileUrl: profileUrl}
+}
+
+func (y *yahooProvider) Name() string {
+	return "yahoo"
+}
+
+func (y *yahooProvider) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	chartUrl, err := y.chartUrl(query)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	y.logger.Info(chartUrl)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, chartUrl, nil)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return entity.Series{}, errs.New(errs.Internal, fmt.Sprintf("yahoo responded with status %v", resp.StatusCode))
+	}
+	var payload yahooResponse
+	err = json.NewDecoder(resp.Body).Decode(&payload)
+	if err != nil {
+		return entity.Series{}, errs.New(errs.Internal, err)
+	}
+	if e := payload.Chart.Error; e != nil {
+		if e.Code == "Not Found" {
+			return entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), e.Description)
+		}
+		return entity.Series{}, errs.New(errs.Internal, fmt.Sprintf("yahoo responded with %v: %v", e.Code, e.Description))
+	}
+	if len(payload.Chart.Result) == 0 {
+		return entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), "yahoo returned no chart")
+	}
+	return toSeries(payload.Chart.Result[0]), nil
+}
+
+// GetProfile reads the fundamentals from the quote summary of Yahoo.
+func (y *yahooProvider) GetProfile(ctx context.Context, symbol string) (entity.Profile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(y.profileUrl, url.PathEscape(symbol)), nil)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	resp, err := y.client.Do(req)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return entity.Profile{}, errs.New(errs.Internal, fmt.Sprintf("yahoo responded with status %v", resp.StatusCode))
+	}
+	var payload yahooSummaryResponse
+	err = json.NewDecoder(resp.Body).Decode(&payload)
+	if err != nil {
+		return entity.Profile{}, errs.New(errs.Internal, err)
+	}
+	if e := payload.QuoteSummary.Error; e != nil {
+		if e.Code == "Not Found" {
+			return entity.Profile{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), e.Description)
+		}
+		return entity.Profile{}, errs.New(errs.Internal, fmt.Sprintf("yahoo responded with %v: %v", e.Code, e.Description))
+	}
+	if len(payload.QuoteSummary.Result) == 0 {
+		return entity.Profile{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"), "yahoo returned no profile")
+	}
+	result := payload.QuoteSummary.Result[0]
+	name := result.Price.LongName
+	if name == "" {
+		name = result.Price.ShortName
+	}
+	return entity.Profile{
+		Symbol:        symbol,
+		Name:          name,
+		Exchange:      result.Price.ExchangeName,
+		Sector:        result.SummaryProfile.Sector,
+		Industry:      result.SummaryProfile.Industry,
+		Currency:      result.Price.Currency,
+		MarketCap:     result.Price.MarketCap.Raw,
+		PERatio:       result.SummaryDetail.TrailingPE.Raw,
+		DividendYield: result.SummaryDetail.DividendYield.Raw,
+		Week52Low:     result.SummaryDetail.FiftyTwoWeekLow.Raw,
+		Week52High:    result.SummaryDetail.FiftyTwoWeekHigh.Raw,
+	}, nil
+}
+
+// toSeries converts the chart of Yahoo, points with a null price become
+// missing bars and a null volume becomes zero.
+func toSeries(result yahooResult) entity.Series {
+	meta := result.Meta
+	series := entity.Series{
+		Symbol:        meta.Symbol,
+		Exchange:      meta.ExchangeName,
+		Price:         valueOf(meta.RegularMarketPrice),
+		PriceTime:     time.Unix(meta.RegularMarketTime, 0).UTC(),
+		PreviousClose: valueOf(meta.ChartPreviousClose),
+		Bars:          make([]entity.Bar, len(result.Timestamp)),
+	}
+	for i, timestamp := range result.Timestamp {
+		bar := entity.Bar{Timestamp: time.Unix(timestamp, 0).UTC(), Missing: true}
+		if len(result.Indicators.Quote) > 0 {
+			quote := result.Indicators.Quote[0]
+			open, high, low, close := at(quote.Open, i), at(quote.High, i), at(quote.Low, i), at(quote.Close, i)
+			if open != nil && high != nil && low != nil && close != nil {
+				bar = entity.Bar{Timestamp: bar.Timestamp, Open: *open, High: *high, Low: *low, Close: *close, Volume: valueOf(at(quote.Volume, i))}
+			}
+		}
+		series.Bars[i] = bar
+	}
+	return series
+}
+
+func at(values []*float64, i int) *float64 {
+	if i >= len(values) {
+		return nil
+	}
+	return values[i]
+}
+
+func valueOf(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return *value
+}
+
+func (y *yahooProvider) chartUrl(query entity.ChartQuery) (string, error) {
+	u, err := url.Parse(fmt.Sprintf(y.url, url.PathEscape(query.Symbol)))
+	if err != nil {
+		return "", err
+	}
+	values := u.Query()
+	if query.Range != "" {
+		values.Set("range", query.Range)
+	}
+	if query.Interval != "" {
+		values.Set("interval", query.Interval)
+	}
+	if query.Period1 != 0 {
+		values.Set("period1", strconv.FormatInt(query.Period1, 10))
+		period2 := query.Period2
+		if period2 == 0 {
+			period2 = time.Now().Unix()
+		}
+		values.Set("period2", strconv.FormatInt(period2, 10))
+	}
+	u.RawQuery = values.Encode()
+	return u.String(), nil
+}
@@ -0,0 +1,187 @@
+package quoteprovider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYahooGetChart(t *testing.T) {
+	testCases := []struct {
+		title     string
+		query     entity.ChartQuery
+		wantQuery string
+		status    int
+		body      string
+		isError   bool
+		wantKind  errs.Kind
+		wantPrice float64
+		wantBars  []entity.Bar
+	}{
+		{
+			title:     "success chart decoding",
+			query:     entity.ChartQuery{Symbol: "TEST"},
+			status:    http.StatusOK,
+			body:      `{"chart":{"result":[{"meta":{"symbol":"TEST","regularMarketTime":42,"regularMarketPrice":42.5}}],"error":null}}`,
+			wantPrice: 42.5,
+		},
+		{
+			title:     "range and interval are forwarded to yahoo",
+			query:     entity.ChartQuery{Symbol: "TEST", Range: "5y", Interval: "1wk"},
+			wantQuery: "interval=1wk&range=5y",
+			status:    http.StatusOK,
+			body:      `{"chart":{"result":[{"meta":{"symbol":"TEST","regularMarketPrice":1}}],"error":null}}`,
+			wantPrice: 1,
+		},
+		{
+			title:     "periods are forwarded to yahoo",
+			query:     entity.ChartQuery{Symbol: "TEST", Interval: "1d", Period1: 100, Period2: 200},
+			wantQuery: "interval=1d&period1=100&period2=200",
+			status:    http.StatusOK,
+			body:      `{"chart":{"result":[{"meta":{"symbol":"TEST","regularMarketPrice":2}}],"error":null}}`,
+			wantPrice: 2,
+		},
+		{
+			title:     "null prices become missing bars",
+			query:     entity.ChartQuery{Symbol: "TEST"},
+			status:    http.StatusOK,
+			body:      `{"chart":{"result":[{"meta":{"symbol":"TEST","regularMarketPrice":3},"timestamp":[10,20],"indicators":{"quote":[{"open":[1,null],"high":[2,null],"low":[0.5,null],"close":[1.5,null],"volume":[null,null]}]}}],"error":null}}`,
+			wantPrice: 3,
+			wantBars: []entity.Bar{
+				{Timestamp: time.Unix(10, 0).UTC(), Open: 1, High: 2, Low: 0.5, Close: 1.5},
+				{Timestamp: time.Unix(20, 0).UTC(), Missing: true},
+			},
+		},
+		{
+			title:    "unknown symbol and return not exist error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			status:   http.StatusNotFound,
+			body:     `{"chart":{"result":null,"error":{"code":"Not Found","description":"No data found, symbol may be delisted"}}}`,
+			isError:  true,
+			wantKind: errs.NotExist,
+		},
+		{
+			title:    "other yahoo error and return internal error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			status:   http.StatusBadRequest,
+			body:     `{"chart":{"result":null,"error":{"code":"Bad Request","description":"Invalid input"}}}`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+		{
+			title:    "upstream failure and return error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			status:   http.StatusBadGateway,
+			body:     `bad gateway`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+		{
+			title:    "malformed body and return error",
+			query:    entity.ChartQuery{Symbol: "TEST"},
+			status:   http.StatusOK,
+			body:     `{"chart":`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/TEST", r.URL.Path)
+				assert.Equal(t, test.wantQuery, r.URL.RawQuery)
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			provider := NewYahooProvider(logging.GetLogger("debug"), server.Client(), server.URL+"/%v", "")
+			series, err := provider.GetChart(context.Background(), test.query)
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, "TEST", series.Symbol)
+			assert.Equal(t, test.wantPrice, series.Price)
+			if test.wantBars != nil {
+				assert.Equal(t, test.wantBars, series.Bars)
+			}
+		})
+	}
+}
+
+func TestYahooGetProfile(t *testing.T) {
+	capitalization, pe, low, high := 2.5e12, 28.5, 124.17, 198.23
+	testCases := []struct {
+		title    string
+		status   int
+		body     string
+		isError  bool
+		wantKind errs.Kind
+		want     entity.Profile
+	}{
+		{
+			title:  "success profile decoding",
+			status: http.StatusOK,
+			body: `{"quoteSummary":{"result":[{"price":{"longName":"Apple Inc.","exchangeName":"NasdaqGS","currency":"USD","marketCap":{"raw":2.5e12,"fmt":"2.5T"}},
+				"summaryProfile":{"sector":"Technology","industry":"Consumer Electronics"},
+				"summaryDetail":{"trailingPE":{"raw":28.5},"dividendYield":{},"fiftyTwoWeekLow":{"raw":124.17},"fiftyTwoWeekHigh":{"raw":198.23}}}],"error":null}}`,
+			want: entity.Profile{
+				Symbol:     "TEST",
+				Name:       "Apple Inc.",
+				Exchange:   "NasdaqGS",
+				Sector:     "Technology",
+				Industry:   "Consumer Electronics",
+				Currency:   "USD",
+				MarketCap:  &capitalization,
+				PERatio:    &pe,
+				Week52Low:  &low,
+				Week52High: &high,
+			},
+		},
+		{
+			title:    "unknown symbol and return not exist error",
+			status:   http.StatusNotFound,
+			body:     `{"quoteSummary":{"result":null,"error":{"code":"Not Found","description":"Quote not found for ticker symbol: TEST"}}}`,
+			isError:  true,
+			wantKind: errs.NotExist,
+		},
+		{
+			title:    "upstream failure and return error",
+			status:   http.StatusBadGateway,
+			body:     `bad gateway`,
+			isError:  true,
+			wantKind: errs.Internal,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/TEST", r.URL.Path)
+				w.WriteHeader(test.status)
+				w.Write([]byte(test.body))
+			}))
+			defer server.Close()
+			provider := NewYahooProvider(logging.GetLogger("debug"), server.Client(), "", server.URL+"/%v")
+			profile, err := provider.GetProfile(context.Background(), "TEST")
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, profile)
+		})
+	}
+}
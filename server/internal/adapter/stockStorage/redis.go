@@ -35,3 +35,21 @@ func (sc *stockCache) Get(symbol string) (string, error) {
 	}
 	return url, err
 }
+
+func (sc *stockCache) GetMany(keys []string) (map[string]string, error) {
+	sc.logger.Infof("try to get %v keys", len(keys))
+	found := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return found, nil
+	}
+	values, err := sc.client.MGet(keys...).Result()
+	if err != nil {
+		return nil, errs.New(errs.Database, err)
+	}
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			found[keys[i]] = str
+		}
+	}
+	return found, nil
+}
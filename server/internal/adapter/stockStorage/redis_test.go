@@ -0,0 +1,124 @@
+package stockstorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	redisServer *miniredis.Miniredis
+	redisClient *redis.Client
+)
+
+func TestGet(t *testing.T) {
+	setUp()
+	defer teardown()
+	storage := NewStockStorage(logging.GetLogger("debug"), redisClient)
+	testCases := []struct {
+		title   string
+		mock    func()
+		input   string
+		want    string
+		isError bool
+	}{
+		{
+			title: "Get should find saved chart",
+			mock: func() {
+				assert.NoError(t, storage.Set("AAPL", "chart", time.Minute))
+			},
+			input: "AAPL",
+			want:  "chart",
+		},
+		{
+			title:   "Get doesn't find key and should return error",
+			mock:    func() {},
+			input:   "MSFT",
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Get(test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetMany(t *testing.T) {
+	setUp()
+	defer teardown()
+	storage := NewStockStorage(logging.GetLogger("debug"), redisClient)
+	testCases := []struct {
+		title   string
+		mock    func()
+		input   []string
+		want    map[string]string
+		isError bool
+	}{
+		{
+			title: "GetMany should skip missing keys",
+			mock: func() {
+				assert.NoError(t, storage.Set("AAPL", "apple", time.Minute))
+				assert.NoError(t, storage.Set("MSFT", "microsoft", time.Minute))
+			},
+			input: []string{"AAPL", "TSLA", "MSFT"},
+			want:  map[string]string{"AAPL": "apple", "MSFT": "microsoft"},
+		},
+		{
+			title: "empty keys and return empty map",
+			mock:  func() {},
+			input: []string{},
+			want:  map[string]string{},
+		},
+		{
+			title: "redis internal error and GetMany return error",
+			mock: func() {
+				redisServer.SetError("internal redis error")
+			},
+			input:   []string{"AAPL"},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.GetMany(test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func setUp() {
+	redisServer = mockRedis()
+	redisClient = redis.NewClient(&redis.Options{
+		Addr: redisServer.Addr(),
+	})
+}
+
+func mockRedis() *miniredis.Miniredis {
+	s, err := miniredis.Run()
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+func teardown() {
+	redisServer.Close()
+}
@@ -0,0 +1,91 @@
+package symboldirectory
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+)
+
+var requiredColumns = []string{"symbol", "name"}
+
+// Read loads the symbol directory from a .csv file with the header
+// symbol,name[,exchange,type,currency] or from a .json array of objects with
+// the same keys.
+func Read(path string) ([]entity.SymbolInfo, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errs.New(errs.Internal, err)
+	}
+	defer file.Close()
+	var infos []entity.SymbolInfo
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		infos, err = readCsv(file)
+	case ".json":
+		infos, err = readJson(file)
+	default:
+		return nil, errs.New(errs.Validation, errs.Code("directory must be a csv or json file"), errs.Parameter("directory"))
+	}
+	if err != nil {
+		return nil, errs.New(errs.Internal, fmt.Errorf("couldn't read %v: %w", path, err))
+	}
+	return infos, nil
+}
+
+func readCsv(r io.Reader) ([]entity.SymbolInfo, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, column := range requiredColumns {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("column %v is missing", column)
+		}
+	}
+	value := func(record []string, column string) string {
+		i, ok := index[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+	infos := make([]entity.SymbolInfo, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, entity.SymbolInfo{
+			Symbol:   value(record, "symbol"),
+			Name:     value(record, "name"),
+			Exchange: value(record, "exchange"),
+			Type:     value(record, "type"),
+			Currency: value(record, "currency"),
+		})
+	}
+	return infos, nil
+}
+
+func readJson(r io.Reader) ([]entity.SymbolInfo, error) {
+	infos := make([]entity.SymbolInfo, 0)
+	if err := json.NewDecoder(r).Decode(&infos); err != nil {
+		return nil, err
+	}
+	return infos, nil
+}
@@ -0,0 +1,70 @@
+package symboldirectory
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRead(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"symbols.csv": "Symbol,Name,Exchange,Type,Currency\n" +
+			"AAPL,Apple Inc.,NASDAQ,stock,USD\n" +
+			"SPY,SPDR S&P 500 ETF Trust,NYSE,etf,USD\n",
+		"short.csv":    "symbol,name\nMSFT,Microsoft Corporation\n",
+		"broken.csv":   "symbol,exchange\nMSFT,NASDAQ\n",
+		"symbols.json": `[{"symbol":"AAPL","name":"Apple Inc.","exchange":"NASDAQ","type":"stock","currency":"USD"}]`,
+		"symbols.txt":  "AAPL",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	apple := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}
+	testCases := []struct {
+		title    string
+		input    string
+		want     []entity.SymbolInfo
+		isError  bool
+		wantKind errs.Kind
+	}{
+		{
+			title: "success reading csv file",
+			input: "symbols.csv",
+			want:  []entity.SymbolInfo{apple, {Symbol: "SPY", Name: "SPDR S&P 500 ETF Trust", Exchange: "NYSE", Type: "etf", Currency: "USD"}},
+		},
+		{
+			title: "optional columns are empty",
+			input: "short.csv",
+			want:  []entity.SymbolInfo{{Symbol: "MSFT", Name: "Microsoft Corporation"}},
+		},
+		{
+			title: "success reading json file",
+			input: "symbols.json",
+			want:  []entity.SymbolInfo{apple},
+		},
+		{title: "missing name column and return internal error", input: "broken.csv", isError: true, wantKind: errs.Internal},
+		{title: "unknown format and return validation error", input: "symbols.txt", isError: true, wantKind: errs.Validation},
+		{title: "missing file and return internal error", input: "none.csv", isError: true, wantKind: errs.Internal},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			got, err := Read(filepath.Join(dir, test.input))
+			if test.isError {
+				var e *errs.Error
+				assert.True(t, errors.As(err, &e))
+				assert.Equal(t, test.wantKind, e.Kind)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
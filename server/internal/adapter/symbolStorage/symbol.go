@@ -0,0 +1,120 @@
+package symbolstorage
+
+import (
+	"context"
+	"strings"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+type DbClient interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type symbolStorage struct {
+	logger *logging.Logger
+	client DbClient
+}
+
+func New(logger *logging.Logger, client DbClient) *symbolStorage {
+	return &symbolStorage{logger: logger, client: client}
+}
+
+// Tracked returns the configured symbols together with the symbols of the
+// active book orders and alerts, whose prices must stay fresh.
+func (s *symbolStorage) Tracked(ctx context.Context) ([]string, error) {
+	sql := `SELECT ts_symbol FROM tracked_symbols
+			UNION
+			SELECT DISTINCT bo_symbol FROM book_orders WHERE bo_state IN ('open', 'partially_filled')
+			UNION
+			SELECT DISTINCT a_symbol FROM alerts WHERE a_active
+			ORDER BY 1`
+	rows, err := s.client.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	symbols := make([]string, 0)
+	for rows.Next() {
+		var symbol string
+		if err := rows.Scan(&symbol); err != nil {
+			return nil, err
+		}
+		symbols = append(symbols, symbol)
+	}
+	return symbols, rows.Err()
+}
+
+// Upsert writes the directory entries in a single statement, entries that are
+// already stored are overwritten. The symbols must be unique.
+func (s *symbolStorage) Upsert(ctx context.Context, infos []entity.SymbolInfo) error {
+	if len(infos) == 0 {
+		return nil
+	}
+	sql := `INSERT INTO symbols(s_symbol,s_name,s_exchange,s_type,s_currency)
+			SELECT unnest($1::text[]),unnest($2::text[]),unnest($3::text[]),unnest($4::text[]),unnest($5::text[])
+			ON CONFLICT (s_symbol) DO UPDATE SET
+			s_name = EXCLUDED.s_name, s_exchange = EXCLUDED.s_exchange,
+			s_type = EXCLUDED.s_type, s_currency = EXCLUDED.s_currency`
+	symbols := make([]string, len(infos))
+	names := make([]string, len(infos))
+	exchanges := make([]string, len(infos))
+	types := make([]string, len(infos))
+	currencies := make([]string, len(infos))
+	for i, info := range infos {
+		symbols[i] = info.Symbol
+		names[i] = info.Name
+		exchanges[i] = info.Exchange
+		types[i] = info.Type
+		currencies[i] = info.Currency
+	}
+	_, err := s.client.Exec(ctx, sql, symbols, names, exchanges, types, currencies)
+	return err
+}
+
+// Directory returns every entry of the symbol directory ordered by symbol.
+func (s *symbolStorage) Directory(ctx context.Context) ([]entity.SymbolInfo, error) {
+	sql := `SELECT s_symbol,s_name,s_exchange,s_type,s_currency FROM symbols ORDER BY s_symbol`
+	rows, err := s.client.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	return scanSymbols(rows)
+}
+
+// Search matches the query against the symbols and the words of the names
+// with pg_trgm, so misspelled queries still find the symbol. Symbols that
+// start with the query go first, then the closest matches.
+func (s *symbolStorage) Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error) {
+	sql := `SELECT s_symbol,s_name,s_exchange,s_type,s_currency FROM symbols
+			WHERE s_symbol LIKE $2 OR s_symbol % $1 OR $1 <% s_name
+			ORDER BY s_symbol LIKE $2 DESC, GREATEST(similarity(s_symbol, $1), word_similarity($1, s_name)) DESC, s_symbol
+			LIMIT $3`
+	rows, err := s.client.Query(ctx, sql, query, escapeLike(strings.ToUpper(query))+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	return scanSymbols(rows)
+}
+
+func scanSymbols(rows pgx.Rows) ([]entity.SymbolInfo, error) {
+	defer rows.Close()
+	infos := make([]entity.SymbolInfo, 0)
+	for rows.Next() {
+		var info entity.SymbolInfo
+		if err := rows.Scan(&info.Symbol, &info.Name, &info.Exchange, &info.Type, &info.Currency); err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, rows.Err()
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
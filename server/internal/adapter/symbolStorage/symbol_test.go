@@ -0,0 +1,161 @@
+package symbolstorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		want    []string
+		isError bool
+	}{
+		{
+			title: "Should return tracked symbols",
+			mock: func() {
+				rows := pgxpoolmock.NewRows([]string{"ts_symbol"}).AddRow("AAPL").AddRow("MSFT").ToPgxRows()
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any()).Return(rows, nil)
+			},
+			want: []string{"AAPL", "MSFT"},
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Tracked(context.Background())
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestUpsert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	infos := []entity.SymbolInfo{{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}}
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		input   []entity.SymbolInfo
+		isError bool
+	}{
+		{
+			title: "Should upsert symbols as arrays",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(),
+					[]string{"AAPL"}, []string{"Apple Inc."}, []string{"NASDAQ"}, []string{"stock"}, []string{"USD"},
+				).Return(pgconn.CommandTag("INSERT 0 1"), nil)
+			},
+			input: infos,
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(nil, errors.New("db error"))
+			},
+			input:   infos,
+			isError: true,
+		},
+		{
+			title: "Should skip empty directory",
+			mock:  func() {},
+			input: nil,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			err := storage.Upsert(context.Background(), test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	columns := []string{"s_symbol", "s_name", "s_exchange", "s_type", "s_currency"}
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		input   string
+		want    []entity.SymbolInfo
+		isError bool
+	}{
+		{
+			title: "Should return matched symbols",
+			mock: func() {
+				rows := pgxpoolmock.NewRows(columns).AddRow("AAPL", "Apple Inc.", "NASDAQ", "stock", "USD").ToPgxRows()
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "appl", "APPL%", 10).Return(rows, nil)
+			},
+			input: "appl",
+			want:  []entity.SymbolInfo{{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}},
+		},
+		{
+			title: "Should escape like wildcards",
+			mock: func() {
+				rows := pgxpoolmock.NewRows(columns).ToPgxRows()
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), "A_B%", `A\_B\%%`, 10).Return(rows, nil)
+			},
+			input: "A_B%",
+			want:  []entity.SymbolInfo{},
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			input:   "appl",
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Search(context.Background(), test.input, 10)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
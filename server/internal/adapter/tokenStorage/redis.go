@@ -4,11 +4,41 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/go-redis/redis"
 )
 
+const (
+	tokenPrefix    = "refresh:"
+	familyPrefix   = "family:"
+	sessionsPrefix = "sessions:"
+	deniedPrefix   = "denied:"
+)
+
+// rotateScript marks the old token as rotated and stores the new one in the
+// same family, unless the old token was rotated before or its family is
+// revoked. It returns the user, the family, how many times the old token was
+// rotated including this call and whether the family is alive.
+var rotateScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+local rotated = redis.call('HINCRBY', KEYS[1], 'rotated', 1)
+local token = redis.call('HMGET', KEYS[1], 'user', 'family')
+local family = KEYS[3] .. token[2]
+local alive = redis.call('EXISTS', family)
+if rotated == 1 and alive == 1 then
+	redis.call('HSET', KEYS[2], 'user', token[1], 'family', token[2])
+	redis.call('PEXPIRE', KEYS[2], ARGV[1])
+	redis.call('HSET', family, 'last_used', ARGV[2])
+	redis.call('PEXPIRE', family, ARGV[1])
+	redis.call('PEXPIRE', KEYS[4] .. token[1], ARGV[1])
+end
+return {token[1], token[2], rotated, alive}
+`)
+
 type tokenStorage struct {
 	logger *logging.Logger
 	client *redis.Client
@@ -18,31 +48,199 @@ func NewChoiceCache(client *redis.Client, logger *logging.Logger) *tokenStorage
 	return &tokenStorage{logger: logger, client: client}
 }
 
-func (t *tokenStorage) Set(refreshToken string, userId int, expireAt time.Duration) error {
-	t.logger.Debugf("try to save token = %v for user with id = %v", refreshToken, userId)
-	err := t.client.Set(refreshToken, strconv.Itoa(userId), expireAt).Err()
-	if err != nil {
+// Set stores the first token of the session and adds the session to the
+// index of the user.
+func (t *tokenStorage) Set(refreshToken string, session entity.Session, expireAt time.Duration) error {
+	t.logger.Debugf("try to save session = %v for user with id = %v", session.Id, session.UserId)
+	pipe := t.client.TxPipeline()
+	pipe.HMSet(tokenKey(refreshToken), map[string]interface{}{"user": session.UserId, "family": session.Id})
+	pipe.Expire(tokenKey(refreshToken), expireAt)
+	pipe.HMSet(familyKey(session.Id), map[string]interface{}{
+		"user":       session.UserId,
+		"user_agent": session.UserAgent,
+		"ip":         session.IP,
+		"created":    session.CreatedAt.Unix(),
+		"last_used":  session.LastUsedAt.Unix(),
+	})
+	pipe.Expire(familyKey(session.Id), expireAt)
+	pipe.SAdd(sessionsKey(session.UserId), session.Id)
+	pipe.Expire(sessionsKey(session.UserId), expireAt)
+	if _, err := pipe.Exec(); err != nil {
 		return errs.New(errs.Database, err)
 	}
 	return nil
 }
 
-func (t *tokenStorage) Get(refreshToken string) (int, error) {
-	value, err := t.client.Get(refreshToken).Result()
+func (t *tokenStorage) Get(refreshToken string) (entity.RefreshToken, error) {
+	values, err := t.client.HGetAll(tokenKey(refreshToken)).Result()
+	if err != nil {
+		return entity.RefreshToken{}, errs.New(errs.Database, err)
+	}
+	if len(values) == 0 {
+		return entity.RefreshToken{}, errs.New(errs.Unauthorized, errs.Code("refresh token is unknown"), "refresh token is unknown")
+	}
+	alive, err := t.client.Exists(familyKey(values["family"])).Result()
+	if err != nil {
+		return entity.RefreshToken{}, errs.New(errs.Database, err)
+	}
+	rotated, _ := strconv.Atoi(values["rotated"])
+	return newRefreshToken(values["user"], values["family"], int64(rotated), alive)
+}
+
+// Rotate exchanges the old token for the new one atomically, so that two
+// concurrent refreshes with the same token can't both succeed.
+func (t *tokenStorage) Rotate(oldToken string, newToken string, usedAt time.Time, expireAt time.Duration) (entity.RefreshToken, error) {
+	keys := []string{tokenKey(oldToken), tokenKey(newToken), familyPrefix, sessionsPrefix}
+	value, err := rotateScript.Run(t.client, keys, expireAt.Milliseconds(), usedAt.Unix()).Result()
+	if err == redis.Nil {
+		return entity.RefreshToken{}, errs.New(errs.Unauthorized, errs.Code("refresh token is unknown"), "refresh token is unknown")
+	}
 	if err != nil {
-		return -1, errs.New(errs.Database, err)
+		return entity.RefreshToken{}, errs.New(errs.Database, err)
 	}
-	count, err := strconv.Atoi(value)
+	values, ok := value.([]interface{})
+	if !ok || len(values) != 4 {
+		return entity.RefreshToken{}, errs.New(errs.Database, "unexpected reply of token rotation")
+	}
+	user, _ := values[0].(string)
+	family, _ := values[1].(string)
+	rotated, _ := values[2].(int64)
+	alive, _ := values[3].(int64)
+	// the count includes this rotation
+	return newRefreshToken(user, family, rotated-1, alive)
+}
+
+// Sessions returns the sessions of the user, the expired ones are dropped
+// from the index.
+func (t *tokenStorage) Sessions(userId int) ([]entity.Session, error) {
+	ids, err := t.client.SMembers(sessionsKey(userId)).Result()
 	if err != nil {
-		return -1, errs.New(errs.Validation, errs.Code("couldn't parse user id"), errs.Parameter("user_id"), err)
+		return nil, errs.New(errs.Database, err)
+	}
+	pipe := t.client.Pipeline()
+	cmds := make([]*redis.StringStringMapCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(familyKey(id))
 	}
-	return count, nil
+	if _, err := pipe.Exec(); err != nil {
+		return nil, errs.New(errs.Database, err)
+	}
+	sessions := make([]entity.Session, 0, len(ids))
+	expired := make([]interface{}, 0)
+	for i, cmd := range cmds {
+		values := cmd.Val()
+		if len(values) == 0 {
+			expired = append(expired, ids[i])
+			continue
+		}
+		created, _ := strconv.ParseInt(values["created"], 10, 64)
+		lastUsed, _ := strconv.ParseInt(values["last_used"], 10, 64)
+		sessions = append(sessions, entity.Session{
+			Id:         ids[i],
+			UserId:     userId,
+			UserAgent:  values["user_agent"],
+			IP:         values["ip"],
+			CreatedAt:  time.Unix(created, 0).UTC(),
+			LastUsedAt: time.Unix(lastUsed, 0).UTC(),
+		})
+	}
+	if len(expired) > 0 {
+		if err := t.client.SRem(sessionsKey(userId), expired...).Err(); err != nil {
+			t.logger.Errorf("cannot drop expired sessions of user with id = %v due to : %v", userId, err)
+		}
+	}
+	return sessions, nil
 }
 
 func (t *tokenStorage) Delete(refreshToken string) error {
-	err := t.client.Del(refreshToken).Err()
+	err := t.client.Del(tokenKey(refreshToken)).Err()
+	if err != nil {
+		return errs.New(errs.Database, err)
+	}
+	return nil
+}
+
+// DeleteFamily revokes every token of the family and ends its session.
+func (t *tokenStorage) DeleteFamily(family string) error {
+	user, err := t.client.HGet(familyKey(family), "user").Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return errs.New(errs.Database, err)
+	}
+	userId, err := strconv.Atoi(user)
 	if err != nil {
+		return errs.New(errs.Validation, errs.Code("couldn't parse user id"), errs.Parameter("user_id"), err)
+	}
+	pipe := t.client.TxPipeline()
+	pipe.Del(familyKey(family))
+	pipe.SRem(sessionsKey(userId), family)
+	if _, err := pipe.Exec(); err != nil {
 		return errs.New(errs.Database, err)
 	}
 	return nil
 }
+
+// DeleteSessions ends every session of the user.
+func (t *tokenStorage) DeleteSessions(userId int) error {
+	ids, err := t.client.SMembers(sessionsKey(userId)).Result()
+	if err != nil {
+		return errs.New(errs.Database, err)
+	}
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, familyKey(id))
+	}
+	keys = append(keys, sessionsKey(userId))
+	if err := t.client.Del(keys...).Err(); err != nil {
+		return errs.New(errs.Database, err)
+	}
+	return nil
+}
+
+// Alive reports whether the session of the family hasn't ended.
+func (t *tokenStorage) Alive(family string) (bool, error) {
+	count, err := t.client.Exists(familyKey(family)).Result()
+	if err != nil {
+		return false, errs.New(errs.Database, err)
+	}
+	return count > 0, nil
+}
+
+// Deny adds the id of an access token to the denylist until the token expires.
+func (t *tokenStorage) Deny(id string, expireAt time.Duration) error {
+	err := t.client.Set(deniedPrefix+id, "1", expireAt).Err()
+	if err != nil {
+		return errs.New(errs.Database, err)
+	}
+	return nil
+}
+
+func (t *tokenStorage) Denied(id string) (bool, error) {
+	count, err := t.client.Exists(deniedPrefix + id).Result()
+	if err != nil {
+		return false, errs.New(errs.Database, err)
+	}
+	return count > 0, nil
+}
+
+func newRefreshToken(user string, family string, rotated int64, alive int64) (entity.RefreshToken, error) {
+	userId, err := strconv.Atoi(user)
+	if err != nil {
+		return entity.RefreshToken{}, errs.New(errs.Validation, errs.Code("couldn't parse user id"), errs.Parameter("user_id"), err)
+	}
+	return entity.RefreshToken{UserId: userId, Family: family, Rotated: rotated > 0, Revoked: alive == 0}, nil
+}
+
+func tokenKey(refreshToken string) string {
+	return tokenPrefix + refreshToken
+}
+
+func familyKey(family string) string {
+	return familyPrefix + family
+}
+
+func sessionsKey(userId int) string {
+	return sessionsPrefix + strconv.Itoa(userId)
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/go-redis/redis"
@@ -50,7 +51,7 @@ func TestSet(t *testing.T) {
 	for _, test := range testCases {
 		t.Run(test.title, func(t *testing.T) {
 			test.mock()
-			err := repo.Set(test.input.refreshToken, test.input.userId, test.input.expire)
+			err := repo.Set(test.input.refreshToken, entity.Session{Id: "family", UserId: test.input.userId}, test.input.expire)
 			if test.isError {
 				assert.Error(t, err)
 			} else {
@@ -76,16 +77,28 @@ func TestGet(t *testing.T) {
 		input   args
 		isError bool
 		mock    mockCall
-		want    int
+		want    entity.RefreshToken
 	}{
 		{
 			title:   "Get should find title and return count",
 			input:   args{refreshToken: "refresh token", userId: 1, expire: 5 * time.Second},
 			isError: false,
 			mock: func(refreshToken string, userId int, expire time.Duration) error {
-				return repo.Set(refreshToken, userId, expire)
+				return repo.Set(refreshToken, entity.Session{Id: "family", UserId: userId}, expire)
 			},
-			want: 1,
+			want: entity.RefreshToken{UserId: 1, Family: "family"},
+		},
+		{
+			title:   "Get finds token of revoked family",
+			input:   args{refreshToken: "revoked token", userId: 1, expire: 5 * time.Second},
+			isError: false,
+			mock: func(refreshToken string, userId int, expire time.Duration) error {
+				if err := repo.Set(refreshToken, entity.Session{Id: "revoked", UserId: userId}, expire); err != nil {
+					return err
+				}
+				return repo.DeleteFamily("revoked")
+			},
+			want: entity.RefreshToken{UserId: 1, Family: "revoked", Revoked: true},
 		},
 		{
 			title:   "Get doens't find key and should return error",
@@ -94,7 +107,6 @@ func TestGet(t *testing.T) {
 			mock: func(refreshToken string, userId int, expire time.Duration) error {
 				return nil
 			},
-			want: -1,
 		},
 		{
 			title:   "reddis internal error and Get return error ",
@@ -104,7 +116,6 @@ func TestGet(t *testing.T) {
 				redisServer.SetError("interanl redis error")
 				return errors.New("internal error")
 			},
-			want: -1,
 		},
 	}
 	for _, test := range testCases {
@@ -145,7 +156,7 @@ func TestDelete(t *testing.T) {
 			input:   args{refreshToken: "refresh token", userId: 1, expire: 5 * time.Second},
 			isError: false,
 			mock: func(refreshToken string, userId int, expire time.Duration) error {
-				return repo.Set(refreshToken, userId, expire)
+				return repo.Set(refreshToken, entity.Session{Id: "family", UserId: userId}, expire)
 			},
 		},
 		{
@@ -173,6 +184,125 @@ func TestDelete(t *testing.T) {
 
 }
 
+func TestRotate(t *testing.T) {
+	setUp()
+	defer teardown()
+
+	repo := NewChoiceCache(redisClient, logging.GetLogger("debug"))
+	assert.NoError(t, repo.Set("first", entity.Session{Id: "family", UserId: 1, CreatedAt: time.Unix(100, 0)}, time.Minute))
+
+	got, err := repo.Rotate("first", "second", time.Unix(200, 0), time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RefreshToken{UserId: 1, Family: "family"}, got)
+	second, err := repo.Get("second")
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RefreshToken{UserId: 1, Family: "family"}, second)
+	assert.Equal(t, "200", redisServer.HGet("family:family", "last_used"))
+
+	got, err = repo.Rotate("first", "stolen", time.Unix(300, 0), time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RefreshToken{UserId: 1, Family: "family", Rotated: true}, got)
+	assert.False(t, redisServer.Exists("refresh:stolen"))
+	first, err := repo.Get("first")
+	assert.NoError(t, err)
+	assert.True(t, first.Rotated)
+
+	assert.NoError(t, repo.DeleteFamily("family"))
+	got, err = repo.Rotate("second", "third", time.Unix(300, 0), time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.RefreshToken{UserId: 1, Family: "family", Revoked: true}, got)
+	assert.False(t, redisServer.Exists("refresh:third"))
+
+	_, err = repo.Rotate("unknown", "fourth", time.Unix(300, 0), time.Minute)
+	assert.Error(t, err)
+	assert.False(t, redisServer.Exists("refresh:unknown"))
+
+	redisServer.SetError("interanl redis error")
+	_, err = repo.Rotate("second", "fifth", time.Unix(300, 0), time.Minute)
+	assert.Error(t, err)
+}
+
+func TestSessions(t *testing.T) {
+	setUp()
+	defer teardown()
+
+	repo := NewChoiceCache(redisClient, logging.GetLogger("debug"))
+	laptop := entity.Session{Id: "laptop", UserId: 1, UserAgent: "Firefox", IP: "10.0.0.1", CreatedAt: time.Unix(100, 0).UTC(), LastUsedAt: time.Unix(100, 0).UTC()}
+	phone := entity.Session{Id: "phone", UserId: 1, UserAgent: "Safari", IP: "10.0.0.2", CreatedAt: time.Unix(200, 0).UTC(), LastUsedAt: time.Unix(200, 0).UTC()}
+	other := entity.Session{Id: "other", UserId: 2, CreatedAt: time.Unix(300, 0).UTC(), LastUsedAt: time.Unix(300, 0).UTC()}
+	assert.NoError(t, repo.Set("laptop token", laptop, time.Minute))
+	assert.NoError(t, repo.Set("phone token", phone, time.Minute))
+	assert.NoError(t, repo.Set("other token", other, time.Minute))
+
+	sessions, err := repo.Sessions(1)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []entity.Session{laptop, phone}, sessions)
+
+	redisServer.Del("family:laptop")
+	sessions, err = repo.Sessions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Session{phone}, sessions)
+	members, _ := redisServer.Members("sessions:1")
+	assert.Equal(t, []string{"phone"}, members)
+
+	assert.NoError(t, repo.DeleteFamily("phone"))
+	assert.False(t, redisServer.Exists("family:phone"))
+	assert.False(t, redisServer.Exists("sessions:1"))
+	token, err := repo.Get("phone token")
+	assert.NoError(t, err)
+	assert.True(t, token.Revoked)
+
+	assert.NoError(t, repo.DeleteSessions(2))
+	assert.False(t, redisServer.Exists("family:other"))
+	sessions, err = repo.Sessions(2)
+	assert.NoError(t, err)
+	assert.Empty(t, sessions)
+
+	redisServer.SetError("interanl redis error")
+	_, err = repo.Sessions(1)
+	assert.Error(t, err)
+}
+
+func TestDeny(t *testing.T) {
+	setUp()
+	defer teardown()
+
+	repo := NewChoiceCache(redisClient, logging.GetLogger("debug"))
+	assert.NoError(t, repo.Deny("token id", time.Minute))
+	assert.Equal(t, time.Minute, redisServer.TTL("denied:token id"))
+	denied, err := repo.Denied("token id")
+	assert.NoError(t, err)
+	assert.True(t, denied)
+	denied, err = repo.Denied("other id")
+	assert.NoError(t, err)
+	assert.False(t, denied)
+
+	redisServer.SetError("interanl redis error")
+	_, err = repo.Denied("token id")
+	assert.Error(t, err)
+	assert.Error(t, repo.Deny("token id", time.Minute))
+}
+
+func TestAlive(t *testing.T) {
+	setUp()
+	defer teardown()
+
+	repo := NewChoiceCache(redisClient, logging.GetLogger("debug"))
+	assert.NoError(t, repo.Set("refresh token", entity.Session{Id: "family", UserId: 1}, time.Minute))
+	alive, err := repo.Alive("family")
+	assert.NoError(t, err)
+	assert.True(t, alive)
+
+	assert.NoError(t, repo.DeleteFamily("family"))
+	alive, err = repo.Alive("family")
+	assert.NoError(t, err)
+	assert.False(t, alive)
+
+	redisServer.SetError("interanl redis error")
+	_, err = repo.Alive("family")
+	assert.Error(t, err)
+}
+
 func setUp() {
 	redisServer = mockRedis()
 	redisClient = redis.NewClient(&redis.Options{
@@ -0,0 +1,147 @@
+package watchliststorage
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+)
+
+const uniqueViolation = "23505"
+
+type DbClient interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	Exec(ctx context.Context, sql string, arguments ...interface{}) (pgconn.CommandTag, error)
+}
+
+type watchlistStorage struct {
+	logger *logging.Logger
+	client DbClient
+}
+
+func New(logger *logging.Logger, client DbClient) *watchlistStorage {
+	return &watchlistStorage{logger: logger, client: client}
+}
+
+const selectWatchlist = `SELECT w.w_id,w.w_user_id,w.w_name,w.create_at,
+		COALESCE(array_agg(ws.ws_symbol ORDER BY ws.ws_position) FILTER (WHERE ws.ws_symbol IS NOT NULL), '{}')
+		FROM watchlists w LEFT JOIN watchlist_symbols ws ON ws.ws_watchlist_id = w.w_id`
+
+func (w *watchlistStorage) Insert(ctx context.Context, userId int, name string) (entity.Watchlist, error) {
+	sql := `INSERT INTO watchlists(w_user_id,w_name,create_at) VALUES ($1,$2,$3)
+			ON CONFLICT (w_user_id,w_name) DO NOTHING RETURNING w_id,w_user_id,w_name,create_at`
+	watchlist := entity.Watchlist{Symbols: []string{}}
+	err := w.client.QueryRow(ctx, sql, userId, name, time.Now()).Scan(&watchlist.Id, &watchlist.UserId, &watchlist.Name, &watchlist.CreateAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Watchlist{}, errs.New(
+				errs.Validation,
+				errs.Code("watchlist already exists"),
+				errs.Parameter("name"),
+				err)
+		}
+		return entity.Watchlist{}, err
+	}
+	return watchlist, nil
+}
+
+func (w *watchlistStorage) FindAll(ctx context.Context, userId int) ([]entity.Watchlist, error) {
+	sql := selectWatchlist + ` WHERE w.w_user_id = $1 GROUP BY w.w_id ORDER BY w.w_id`
+	rows, err := w.client.Query(ctx, sql, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	watchlists := make([]entity.Watchlist, 0)
+	for rows.Next() {
+		var watchlist entity.Watchlist
+		err := rows.Scan(&watchlist.Id, &watchlist.UserId, &watchlist.Name, &watchlist.CreateAt, &watchlist.Symbols)
+		if err != nil {
+			return nil, err
+		}
+		watchlists = append(watchlists, watchlist)
+	}
+	return watchlists, rows.Err()
+}
+
+func (w *watchlistStorage) Find(ctx context.Context, userId int, id int) (entity.Watchlist, error) {
+	sql := selectWatchlist + ` WHERE w.w_id = $1 AND w.w_user_id = $2 GROUP BY w.w_id`
+	var watchlist entity.Watchlist
+	err := w.client.QueryRow(ctx, sql, id, userId).Scan(&watchlist.Id, &watchlist.UserId, &watchlist.Name, &watchlist.CreateAt, &watchlist.Symbols)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return entity.Watchlist{}, notFound(err)
+		}
+		return entity.Watchlist{}, err
+	}
+	return watchlist, nil
+}
+
+func (w *watchlistStorage) Rename(ctx context.Context, userId int, id int, name string) error {
+	sql := `UPDATE watchlists SET w_name = $1 WHERE w_id = $2 AND w_user_id = $3`
+	tag, err := w.client.Exec(ctx, sql, name, id, userId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return errs.New(errs.Validation, errs.Code("watchlist already exists"), errs.Parameter("name"), err)
+		}
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+func (w *watchlistStorage) Delete(ctx context.Context, userId int, id int) error {
+	sql := `DELETE FROM watchlists WHERE w_id = $1 AND w_user_id = $2`
+	tag, err := w.client.Exec(ctx, sql, id, userId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return notFound(pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// AddSymbol appends the symbol to the end of the watchlist, adding a symbol
+// that is already there is a no-op.
+func (w *watchlistStorage) AddSymbol(ctx context.Context, id int, symbol string) error {
+	sql := `INSERT INTO watchlist_symbols(ws_watchlist_id,ws_symbol,ws_position)
+			SELECT $1,$2,COALESCE(MAX(ws_position) + 1, 0) FROM watchlist_symbols WHERE ws_watchlist_id = $1
+			ON CONFLICT (ws_watchlist_id,ws_symbol) DO NOTHING`
+	_, err := w.client.Exec(ctx, sql, id, symbol)
+	return err
+}
+
+func (w *watchlistStorage) RemoveSymbol(ctx context.Context, id int, symbol string) error {
+	sql := `DELETE FROM watchlist_symbols WHERE ws_watchlist_id = $1 AND ws_symbol = $2`
+	tag, err := w.client.Exec(ctx, sql, id, symbol)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return errs.New(errs.NotExist, errs.Code("symbol not found in watchlist"), errs.Parameter("symbol"), pgx.ErrNoRows)
+	}
+	return nil
+}
+
+// Reorder sets the position of every symbol to its index in symbols.
+func (w *watchlistStorage) Reorder(ctx context.Context, id int, symbols []string) error {
+	sql := `UPDATE watchlist_symbols SET ws_position = s.position
+			FROM unnest($2::text[]) WITH ORDINALITY AS s(symbol, position)
+			WHERE ws_watchlist_id = $1 AND ws_symbol = s.symbol`
+	_, err := w.client.Exec(ctx, sql, id, symbols)
+	return err
+}
+
+func notFound(err error) error {
+	return errs.New(errs.NotExist, errs.Code("watchlist not found"), errs.Parameter("id"), err)
+}
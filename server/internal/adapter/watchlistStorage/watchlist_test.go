@@ -0,0 +1,149 @@
+package watchliststorage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/driftprogramming/pgxpoolmock"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type watchlistRow struct {
+	Id       int
+	UserId   int
+	Name     string
+	CreateAt time.Time
+	Err      error
+}
+
+func (this watchlistRow) Scan(dest ...interface{}) error {
+	if this.Err != nil {
+		return this.Err
+	}
+	*dest[0].(*int) = this.Id
+	*dest[1].(*int) = this.UserId
+	*dest[2].(*string) = this.Name
+	*dest[3].(*time.Time) = this.CreateAt
+	return nil
+}
+
+func TestInsertWatchlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	type mockCall func()
+	testCases := []struct {
+		title   string
+		mock    mockCall
+		want    entity.Watchlist
+		isError bool
+	}{
+		{
+			title: "Should insert new watchlist",
+			mock: func() {
+				row := watchlistRow{Id: 1, UserId: 7, Name: "Tech"}
+				mockPool.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 7, "Tech", gomock.Any()).Return(row)
+			},
+			want: entity.Watchlist{Id: 1, UserId: 7, Name: "Tech", Symbols: []string{}},
+		},
+		{
+			title: "Should return error if watchlist exists",
+			mock: func() {
+				row := watchlistRow{Err: pgx.ErrNoRows}
+				mockPool.EXPECT().QueryRow(gomock.Any(), gomock.Any(), 7, "Tech", gomock.Any()).Return(row)
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			got, err := storage.Insert(context.Background(), 7, "Tech")
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestFindAllWatchlists(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	createAt := time.Now()
+	columns := []string{"w_id", "w_user_id", "w_name", "create_at", "symbols"}
+	rows := pgxpoolmock.NewRows(columns).AddRow(1, 7, "Tech", createAt, []string{"AAPL", "MSFT"}).ToPgxRows()
+	mockPool.EXPECT().Query(gomock.Any(), gomock.Any(), 7).Return(rows, nil)
+	got, err := storage.FindAll(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Watchlist{{Id: 1, UserId: 7, Name: "Tech", Symbols: []string{"AAPL", "MSFT"}, CreateAt: createAt}}, got)
+}
+
+func TestRenameWatchlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	mockPool := pgxpoolmock.NewMockPgxPool(ctrl)
+	storage := New(logging.GetLogger("debug"), mockPool)
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mock     mockCall
+		isError  bool
+		notExist bool
+	}{
+		{
+			title: "Should rename watchlist",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), "New", 1, 7).Return(pgconn.CommandTag("UPDATE 1"), nil)
+			},
+		},
+		{
+			title: "Should return error if watchlist not found",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), "New", 1, 7).Return(pgconn.CommandTag("UPDATE 0"), nil)
+			},
+			isError:  true,
+			notExist: true,
+		},
+		{
+			title: "Should return error if name is taken",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), "New", 1, 7).Return(nil, &pgconn.PgError{Code: uniqueViolation})
+			},
+			isError: true,
+		},
+		{
+			title: "Should return db error",
+			mock: func() {
+				mockPool.EXPECT().Exec(gomock.Any(), gomock.Any(), "New", 1, 7).Return(nil, errors.New("db error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mock()
+			err := storage.Rename(context.Background(), 7, 1, "New")
+			if test.isError {
+				assert.Error(t, err)
+				var e *errs.Error
+				assert.Equal(t, test.notExist, errors.As(err, &e) && e.Kind == errs.NotExist)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
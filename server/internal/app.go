@@ -5,20 +5,40 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
+	"path/filepath"
 	"syscall"
 	"time"
 
+	alertstorage "github.com/VrMolodyakov/stock-market/internal/adapter/alertStorage"
+	barstorage "github.com/VrMolodyakov/stock-market/internal/adapter/barStorage"
+	"github.com/VrMolodyakov/stock-market/internal/adapter/notifier"
+	portfoliostorage "github.com/VrMolodyakov/stock-market/internal/adapter/portfolioStorage"
+	quotebus "github.com/VrMolodyakov/stock-market/internal/adapter/quoteBus"
+	quoteprovider "github.com/VrMolodyakov/stock-market/internal/adapter/quoteProvider"
 	stockstorage "github.com/VrMolodyakov/stock-market/internal/adapter/stockStorage"
+	symboldirectory "github.com/VrMolodyakov/stock-market/internal/adapter/symbolDirectory"
+	symbolstorage "github.com/VrMolodyakov/stock-market/internal/adapter/symbolStorage"
 	"github.com/VrMolodyakov/stock-market/internal/adapter/tokenStorage"
 	userstorage "github.com/VrMolodyakov/stock-market/internal/adapter/userStorage"
+	watchliststorage "github.com/VrMolodyakov/stock-market/internal/adapter/watchlistStorage"
 	"github.com/VrMolodyakov/stock-market/internal/config"
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/alert"
 	v1 "github.com/VrMolodyakov/stock-market/internal/controller/http/v1/auth"
 	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/portfolio"
 	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/route"
 	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/stock"
+	streamhandler "github.com/VrMolodyakov/stock-market/internal/controller/http/v1/stream"
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/watchlist"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/domain/service"
+	"github.com/VrMolodyakov/stock-market/internal/scheduler"
+	"github.com/VrMolodyakov/stock-market/internal/stream"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
 	"github.com/VrMolodyakov/stock-market/pkg/client/postgresql"
 	"github.com/VrMolodyakov/stock-market/pkg/client/redis"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
@@ -66,8 +86,8 @@ func (a *app) startHttp() {
 	}
 	stockStorage := stockstorage.NewStockStorage(a.logger, rdClient)
 	tokenStorage := tokenStorage.NewChoiceCache(rdClient, a.logger)
-	accessPair, refreshPair := a.initTokens()
-	tokenHandler := token.NewTokenHandler(a.logger, accessPair, refreshPair)
+	accessKeys, refreshKeys, reloaders := a.initKeys()
+	tokenHandler := token.NewTokenHandler(a.logger, accessKeys, refreshKeys)
 	tokenService := service.NewTokenService(tokenStorage, a.logger)
 	userService := service.NewUserService(a.logger, storage)
 	cacheService := service.NewCacheService(a.logger, stockStorage)
@@ -75,16 +95,96 @@ func (a *app) startHttp() {
 	authMiddleware := middleware.NewAuthMiddleware(userService, tokenService, tokenHandler, a.logger)
 	prometheusClient := metric.NewPrometheusClient(true)
 	metric := metric.NewMetric(prometheusClient.Registry())
-	stockHandler := stock.NewStockHandler(metric, a.logger, cacheService, http.DefaultClient)
+	providers := a.initProviders()
+	quoteService := service.NewQuoteService(a.logger, metric, providers...)
+	marketCalendar, err := calendar.New(a.cfg.Market.Holidays)
+	a.checkErr(err)
+	barService := service.NewBarService(a.logger, barstorage.New(a.logger, psqlClient))
+	streamInterval := time.Duration(a.cfg.Stream.Interval) * time.Second
+	quoteBus := quotebus.New(a.logger, rdClient, quotebus.Options{Lease: 3 * streamInterval})
+	chartService := service.NewChartService(a.logger, cacheService, quoteService, barService, marketCalendar)
+	chartService.Coordinate(quoteBus)
+	quoteBook := stream.NewBook()
+	indicatorService := service.NewIndicatorService(a.logger, chartService, stockStorage)
+	stockHandler := stock.NewStockHandler(metric, a.logger, chartService, barService, quoteBook, indicatorService, service.NewResampler(marketCalendar))
+	symbolStorage := symbolstorage.New(a.logger, psqlClient)
+	symbolService := service.NewSymbolService(a.logger, symbolStorage)
+	if a.cfg.Search.Directory != "" {
+		infos, err := symboldirectory.Read(a.cfg.Search.Directory)
+		a.checkErr(err)
+		count, err := symbolService.Import(context.Background(), infos)
+		if err != nil {
+			a.logger.Errorf("cannot import symbol directory due to : %v", err)
+		}
+		a.logger.Infof("%v symbols are imported from %v", count, a.cfg.Search.Directory)
+	} else if err := symbolService.Load(context.Background()); err != nil {
+		a.logger.Errorf("cannot load symbol index due to : %v", err)
+	}
+	searchHandler := stock.NewSearchHandler(metric, a.logger, symbolService)
+	profileService := service.NewProfileService(a.logger, stockStorage, chartService, symbolService, providers...)
+	profileHandler := stock.NewProfileHandler(metric, a.logger, profileService)
+	watchlistService := service.NewWatchlistService(a.logger, watchliststorage.New(a.logger, psqlClient))
+	watchlistHandler := watchlist.NewWatchlistHandler(a.logger, watchlistService)
+	portfolioStorage := portfoliostorage.New(a.logger, psqlClient)
+	portfolioService := service.NewPortfolioService(a.logger, portfolioStorage, chartService, a.cfg.Portfolio.InitialCash)
+	portfolioHandler := portfolio.NewPortfolioHandler(metric, a.logger, portfolioService)
+	bookService := service.NewOrderBookService(a.logger, portfolioStorage, marketCalendar)
+	bookHandler := portfolio.NewBookHandler(metric, a.logger, bookService)
+	matcher := service.NewMatcher(a.logger, portfolioStorage, marketCalendar, a.cfg.Portfolio.InitialCash)
+	chartService.Subscribe(matcher)
+	matcher.Start()
+	alertStorage := alertstorage.New(a.logger, psqlClient)
+	notifiers := a.initNotifiers()
+	channels := make([]entity.AlertChannel, 0, len(notifiers))
+	for channel := range notifiers {
+		channels = append(channels, channel)
+	}
+	alertService := service.NewAlertService(a.logger, alertStorage, channels, net.DefaultResolver)
+	alertHandler := alert.NewAlertHandler(a.logger, alertService)
+	alertMonitor := service.NewAlertMonitor(a.logger, alertStorage, notifiers, a.cfg.Alert.Hysteresis)
+	chartService.Subscribe(alertMonitor)
+	alertMonitor.Start()
+	hub := stream.New(a.logger, chartService, quoteBus, stream.Options{
+		Interval:   streamInterval,
+		MaxSymbols: a.cfg.Stream.MaxSymbols,
+	})
+	quoteBus.Subscribe(hub)
+	quoteBus.Subscribe(quoteBook)
+	a.checkErr(quoteBus.Start())
+	streamHandler := streamhandler.NewStreamHandler(a.logger, hub, a.cfg.Stream.Origins)
+	closers := append([]io.Closer{matcher, alertMonitor, hub, quoteBus}, reloaders...)
+	if a.cfg.Scheduler.Enabled {
+		ingestion := scheduler.New(a.logger, chartService, symbolStorage, scheduler.Options{
+			Symbols:    a.cfg.Scheduler.Symbols,
+			Interval:   time.Duration(a.cfg.Scheduler.Interval) * time.Second,
+			Jitter:     time.Duration(a.cfg.Scheduler.Jitter) * time.Second,
+			MaxBackoff: time.Duration(a.cfg.Scheduler.MaxBackoff) * time.Second,
+			Reload:     time.Duration(a.cfg.Scheduler.Reload) * time.Second,
+		})
+		ingestion.Start()
+		closers = append(closers, ingestion)
+	}
+	// the chart service is closed after its users and before the cache client
+	closers = append(closers, chartService)
 	a.server.Use(middleware.CORSMiddleware())
 	router := a.server.Group("/api")
 	authRouter := route.NewAuthRouter(authHandler, authMiddleware)
-	stockRouter := route.NewStockRouter(stockHandler, authMiddleware)
+	stockRouter := route.NewStockRouter(stockHandler, searchHandler, profileHandler, authMiddleware)
+	watchlistRouter := route.NewWatchlistRouter(watchlistHandler, authMiddleware)
+	portfolioRouter := route.NewPortfolioRouter(portfolioHandler, bookHandler, authMiddleware)
+	alertRouter := route.NewAlertRouter(alertHandler, authMiddleware)
+	streamRouter := route.NewStreamRouter(streamHandler, authMiddleware)
+	jwksRouter := route.NewJwksRouter(v1.NewJwksHandler(accessKeys))
 	metricRouter := route.NewPrometheusRouter(prometheusClient)
 
 	metricRouter.MetricRoute(router)
 	authRouter.AuthRoute(router)
+	jwksRouter.JwksRoute(router)
 	stockRouter.StockRoute(router)
+	watchlistRouter.WatchlistRoute(router)
+	portfolioRouter.PortfolioRoute(router)
+	alertRouter.AlertRoute(router)
+	streamRouter.StreamRoute(router)
 
 	a.server.NoRoute(func(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, gin.H{"status": "fail", "message": fmt.Sprintf("Route %s not found", ctx.Request.URL)})
@@ -93,12 +193,12 @@ func (a *app) startHttp() {
 	port := fmt.Sprintf(":%s", a.cfg.Port)
 	server := &http.Server{
 		Addr:         port,
-		Handler:      a.server,
+		Handler:      middleware.WithResponseController(a.server),
 		WriteTimeout: writeTimeout,
 		ReadTimeout:  readTimeout,
 	}
 
-	go shutdown.Graceful([]os.Signal{syscall.SIGABRT, syscall.SIGQUIT, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM}, rdClient, server)
+	go shutdown.Graceful([]os.Signal{syscall.SIGABRT, syscall.SIGQUIT, syscall.SIGHUP, os.Interrupt, syscall.SIGTERM}, append(closers, rdClient, server)...)
 	defer psqlClient.Close()
 	if err := server.ListenAndServe(); err != nil {
 		switch {
@@ -112,6 +212,38 @@ func (a *app) startHttp() {
 
 }
 
+// initKeys returns the key rings of the tokens. The keys are read from the
+// access and refresh subdirectories of the keys directory and reloaded, or
+// from the key pairs of the config when the directory isn't set. The
+// algorithms of the config apply to the keys whose files don't name one.
+func (a *app) initKeys() (*token.KeyRing, *token.KeyRing, []io.Closer) {
+	dir := a.cfg.Token.KeysDir
+	if dir == "" {
+		accessPair, refreshPair := a.initTokens()
+		access, err := token.NewPairRing(accessPair)
+		a.checkErr(err)
+		refresh, err := token.NewPairRing(refreshPair)
+		a.checkErr(err)
+		return access, refresh, nil
+	}
+	rings := make([]*token.KeyRing, 0, 2)
+	reloaders := make([]io.Closer, 0, 2)
+	algorithms := map[string]string{"access": a.cfg.Token.AccessAlgorithm, "refresh": a.cfg.Token.RefreshAlgorithm}
+	for _, name := range []string{"access", "refresh"} {
+		keys, active, err := token.LoadDir(filepath.Join(dir, name), algorithms[name])
+		a.checkErr(err)
+		ring, err := token.NewKeyRing(keys, active)
+		a.checkErr(err)
+		rings = append(rings, ring)
+		if a.cfg.Token.KeysReload > 0 {
+			reloader := token.NewReloader(a.logger, filepath.Join(dir, name), algorithms[name], ring, time.Duration(a.cfg.Token.KeysReload)*time.Second)
+			reloader.Start()
+			reloaders = append(reloaders, reloader)
+		}
+	}
+	return rings[0], rings[1], reloaders
+}
+
 func (a *app) initTokens() (token.TokenPair, token.TokenPair) {
 	aprk, err := base64.StdEncoding.DecodeString(a.cfg.Token.AccessPrivate)
 	a.checkErr(err)
@@ -121,11 +253,53 @@ func (a *app) initTokens() (token.TokenPair, token.TokenPair) {
 	a.checkErr(err)
 	rpbk, err := base64.StdEncoding.DecodeString(a.cfg.Token.RefreshPublic)
 	a.checkErr(err)
-	apair := token.TokenPair{PrivateKey: aprk, PublicKey: apbk}
-	rpair := token.TokenPair{PrivateKey: rprk, PublicKey: rpbk}
+	apair := token.TokenPair{PrivateKey: aprk, PublicKey: apbk, Algorithm: a.cfg.Token.AccessAlgorithm}
+	rpair := token.TokenPair{PrivateKey: rprk, PublicKey: rpbk, Algorithm: a.cfg.Token.RefreshAlgorithm}
 	return apair, rpair
 }
 
+func (a *app) initProviders() []service.QuoteProvider {
+	client := &http.Client{Timeout: time.Duration(a.cfg.Provider.Timeout) * time.Second}
+	sources := a.cfg.Provider.Sources
+	if len(sources) == 0 {
+		sources = []string{"yahoo"}
+	}
+	providers := make([]service.QuoteProvider, 0, len(sources))
+	for _, source := range sources {
+		switch source {
+		case "yahoo":
+			providers = append(providers, quoteprovider.NewYahooProvider(a.logger, client, a.cfg.Provider.YahooUrl, a.cfg.Provider.YahooProfileUrl))
+		case "alphavantage":
+			providers = append(providers, quoteprovider.NewAlphaVantageProvider(a.logger, client, a.cfg.Provider.AlphaVantageUrl, a.cfg.Provider.AlphaVantageProfileUrl, a.cfg.Provider.AlphaVantageKey))
+		case "csv":
+			providers = append(providers, quoteprovider.NewCsvProvider(a.logger, a.cfg.Provider.CsvDir))
+		default:
+			a.logger.Fatalf("unknown quote provider %v", source)
+		}
+	}
+	return providers
+}
+
+// initNotifiers returns the notifiers of the configured alert channels, email
+// is available only when the smtp server is set.
+func (a *app) initNotifiers() map[entity.AlertChannel]service.Notifier {
+	client := notifier.NewWebhookClient(time.Duration(a.cfg.Alert.WebhookTimeout) * time.Second)
+	notifiers := map[entity.AlertChannel]service.Notifier{
+		entity.Webhook: notifier.NewWebhookNotifier(a.logger, client),
+	}
+	smtp := a.cfg.Alert.Smtp
+	if smtp.Host != "" {
+		notifiers[entity.Email] = notifier.NewSmtpNotifier(a.logger, notifier.SmtpOptions{
+			Host:     smtp.Host,
+			Port:     smtp.Port,
+			Username: smtp.Username,
+			Password: smtp.Password,
+			From:     smtp.From,
+		})
+	}
+	return notifiers
+}
+
 func (a *app) checkErr(err error) {
 	if err != nil {
 		a.logger.Fatal(err)
@@ -12,12 +12,19 @@ import (
 )
 
 type Config struct {
-	Port       string  `yaml : "port"`
-	Host       string  `yaml : "host"`
-	LogLvl     string  `yaml : "loglvl"`
-	PostgreSql Postgre `yaml : "postgresql"`
-	Redis      Redis   `yaml : "redis"`
-	Token      Token   `yaml : "token"`
+	Port       string    `yaml:"port"`
+	Host       string    `yaml:"host"`
+	LogLvl     string    `yaml:"loglvl"`
+	PostgreSql Postgre   `yaml:"postgresql"`
+	Redis      Redis     `yaml:"redis"`
+	Token      Token     `yaml:"token"`
+	Provider   Provider  `yaml:"provider"`
+	Market     Market    `yaml:"market"`
+	Scheduler  Scheduler `yaml:"scheduler"`
+	Portfolio  Portfolio `yaml:"portfolio"`
+	Alert      Alert     `yaml:"alert"`
+	Stream     Stream    `yaml:"stream"`
+	Search     Search    `yaml:"search"`
 }
 
 type Redis struct {
@@ -37,12 +44,68 @@ type Postgre struct {
 }
 
 type Token struct {
-	AccessPublic   string `yaml:"access_public"`
-	AccessPrivate  string `yaml:"access_private"`
-	RefreshPublic  string `yaml:"refresh_public"`
-	RefreshPrivate string `yaml:"refresh_private"`
-	AccessTtl      int    `yaml:"access_ttl"`
-	RefreshTtl     int    `yaml:"refresh_ttl"`
+	AccessPublic     string `yaml:"access_public"`
+	AccessPrivate    string `yaml:"access_private"`
+	RefreshPublic    string `yaml:"refresh_public"`
+	RefreshPrivate   string `yaml:"refresh_private"`
+	AccessAlgorithm  string `yaml:"access_algorithm"`
+	RefreshAlgorithm string `yaml:"refresh_algorithm"`
+	AccessTtl        int    `yaml:"access_ttl"`
+	RefreshTtl       int    `yaml:"refresh_ttl"`
+	KeysDir          string `yaml:"keys_dir"`
+	KeysReload       int    `yaml:"keys_reload"`
+}
+
+type Provider struct {
+	Sources                []string `yaml:"sources"`
+	Timeout                int      `yaml:"timeout"`
+	YahooUrl               string   `yaml:"yahoo_url"`
+	YahooProfileUrl        string   `yaml:"yahoo_profile_url"`
+	AlphaVantageUrl        string   `yaml:"alpha_vantage_url"`
+	AlphaVantageProfileUrl string   `yaml:"alpha_vantage_profile_url"`
+	AlphaVantageKey        string   `yaml:"alpha_vantage_key"`
+	CsvDir                 string   `yaml:"csv_dir"`
+}
+
+type Scheduler struct {
+	Enabled    bool     `yaml:"enabled"`
+	Symbols    []string `yaml:"symbols"`
+	Interval   int      `yaml:"interval"`
+	Jitter     int      `yaml:"jitter"`
+	MaxBackoff int      `yaml:"max_backoff"`
+	Reload     int      `yaml:"reload"`
+}
+
+type Portfolio struct {
+	InitialCash float64 `yaml:"initial_cash"`
+}
+
+type Alert struct {
+	Hysteresis     float64 `yaml:"hysteresis"`
+	WebhookTimeout int     `yaml:"webhook_timeout"`
+	Smtp           Smtp    `yaml:"smtp"`
+}
+
+type Smtp struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type Stream struct {
+	Interval   int      `yaml:"interval"`
+	MaxSymbols int      `yaml:"max_symbols"`
+	Origins    []string `yaml:"origins"`
+}
+
+type Search struct {
+	Directory string `yaml:"directory"`
+}
+
+type Market struct {
+	Holidays map[string][]string `yaml:"holidays"`
 }
 
 var instance *Config
@@ -0,0 +1,81 @@
+package alert
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+type AlertService interface {
+	Create(ctx context.Context, alert entity.Alert) (entity.Alert, error)
+	GetAll(ctx context.Context, userId int) ([]entity.Alert, error)
+	Delete(ctx context.Context, userId int, id int) error
+}
+
+type alertHandler struct {
+	logger       *logging.Logger
+	alertService AlertService
+}
+
+func NewAlertHandler(logger *logging.Logger, alertService AlertService) *alertHandler {
+	return &alertHandler{logger: logger, alertService: alertService}
+}
+
+func (a *alertHandler) GetAll(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	alerts, err := a.alertService.GetAll(ctx.Request.Context(), user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	response := make([]AlertResponse, len(alerts))
+	for i, alert := range alerts {
+		response[i] = ResponseFromEntity(alert)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
+
+func (a *alertHandler) Create(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	var request AlertRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	alert, err := a.alertService.Create(ctx.Request.Context(), request.ToEntity(user.Id))
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": ResponseFromEntity(alert)})
+}
+
+func (a *alertHandler) Delete(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Validation, errs.Code("incorrect alert id"), errs.Parameter("id")))
+		return
+	}
+	err = a.alertService.Delete(ctx.Request.Context(), user.Id, id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
@@ -0,0 +1,127 @@
+package alert
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/alert/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreate(t *testing.T) {
+	cntr := gomock.NewController(t)
+	alertService := mocks.NewMockAlertService(cntr)
+	handler := NewAlertHandler(logging.GetLogger("debug"), alertService)
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		body         string
+		mockCall     mockCall
+		expectedCode int
+		expectedId   int
+	}{
+		{
+			title: "created alert and 201 response",
+			body:  `{"symbol":"AAPL","condition":"CROSS_ABOVE","threshold":200,"channel":"webhook","target":" https://example.com/hook ","rearm":true}`,
+			mockCall: func() {
+				alertService.EXPECT().Create(gomock.Any(), entity.Alert{
+					UserId: 7, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook,
+					Target: "https://example.com/hook", Rearm: true,
+				}).Return(entity.Alert{Id: 4, Symbol: "AAPL", Armed: true, Active: true}, nil)
+			},
+			expectedCode: 201,
+			expectedId:   4,
+		},
+		{
+			title: "unsupported channel and 400 response",
+			body:  `{"symbol":"AAPL","condition":"cross_above","threshold":200,"channel":"sms","target":"123"}`,
+			mockCall: func() {
+				alertService.EXPECT().Create(gomock.Any(), gomock.Any()).
+					Return(entity.Alert{}, errs.New(errs.Validation, errs.Code("unsupported alert channel")))
+			},
+			expectedCode: 400,
+		},
+		{
+			title:        "incorrect body and 400 response",
+			body:         `{"threshold":"high"}`,
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.POST("/api/alerts", func(ctx *gin.Context) { ctx.Set("user", entity.User{Id: 7}) }, handler.Create)
+			req, _ := http.NewRequest(http.MethodPost, "/api/alerts", strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedId != 0 {
+				var response struct {
+					Data AlertResponse `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.expectedId, response.Data.Id)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cntr := gomock.NewController(t)
+	alertService := mocks.NewMockAlertService(cntr)
+	handler := NewAlertHandler(logging.GetLogger("debug"), alertService)
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		id           string
+		mockCall     mockCall
+		expectedCode int
+	}{
+		{
+			title: "deleted alert and 200 response",
+			id:    "4",
+			mockCall: func() {
+				alertService.EXPECT().Delete(gomock.Any(), 7, 4).Return(nil)
+			},
+			expectedCode: 200,
+		},
+		{
+			title: "storage failure and 500 response",
+			id:    "4",
+			mockCall: func() {
+				alertService.EXPECT().Delete(gomock.Any(), 7, 4).Return(errors.New("db error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:        "incorrect id and 400 response",
+			id:           "-1",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.DELETE("/api/alerts/:id", func(ctx *gin.Context) { ctx.Set("user", entity.User{Id: 7}) }, handler.Delete)
+			req, _ := http.NewRequest(http.MethodDelete, "/api/alerts/"+test.id, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+		})
+	}
+}
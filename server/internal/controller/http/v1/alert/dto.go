@@ -0,0 +1,62 @@
+package alert
+
+import (
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type AlertRequest struct {
+	Symbol    string  `json:"symbol"`
+	Condition string  `json:"condition"`
+	Threshold float64 `json:"threshold"`
+	Channel   string  `json:"channel"`
+	Target    string  `json:"target"`
+	Rearm     bool    `json:"rearm"`
+}
+
+type AlertResponse struct {
+	Id        int     `json:"id"`
+	Symbol    string  `json:"symbol"`
+	Condition string  `json:"condition"`
+	Threshold float64 `json:"threshold"`
+	Channel   string  `json:"channel"`
+	Target    string  `json:"target"`
+	Rearm     bool    `json:"rearm"`
+	Armed     bool    `json:"armed"`
+	Active    bool    `json:"active"`
+	FiredAt   string  `json:"fired_at,omitempty"`
+	CreateAt  string  `json:"create_at"`
+}
+
+func (r AlertRequest) ToEntity(userId int) entity.Alert {
+	return entity.Alert{
+		UserId:    userId,
+		Symbol:    r.Symbol,
+		Condition: entity.AlertCondition(strings.ToLower(r.Condition)),
+		Threshold: r.Threshold,
+		Channel:   entity.AlertChannel(strings.ToLower(r.Channel)),
+		Target:    strings.TrimSpace(r.Target),
+		Rearm:     r.Rearm,
+	}
+}
+
+func ResponseFromEntity(alert entity.Alert) AlertResponse {
+	response := AlertResponse{
+		Id:        alert.Id,
+		Symbol:    alert.Symbol,
+		Condition: string(alert.Condition),
+		Threshold: alert.Threshold,
+		Channel:   string(alert.Channel),
+		Target:    alert.Target,
+		Rearm:     alert.Rearm,
+		Armed:     alert.Armed,
+		Active:    alert.Active,
+		CreateAt:  alert.CreateAt.Format(time.RFC3339),
+	}
+	if !alert.FiredAt.IsZero() {
+		response.FiredAt = alert.FiredAt.Format(time.RFC3339)
+	}
+	return response
+}
@@ -1,9 +1,12 @@
 package v1
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/hashing"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
@@ -82,22 +85,23 @@ func (a *authHandler) SignInUser(ctx *gin.Context) {
 		return
 	}
 
-	accessToken, err := a.tokenHandler.CreateAccessToken(time.Duration(a.accessTtl)*time.Minute, user.Id)
-	if err != nil {
-		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Internal, err))
-		return
-	}
 	refreshToken, err := a.tokenHandler.CreateRefreshToken(time.Duration(a.refreshTtl)*time.Minute, user.Id)
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Internal, err))
 		return
 	}
 
-	err = a.tokenService.Save(refreshToken, user.Id, time.Duration(a.refreshTtl)*time.Minute)
+	session := entity.Session{UserId: user.Id, UserAgent: ctx.Request.UserAgent(), IP: ctx.ClientIP()}
+	sessionId, err := a.tokenService.Save(refreshToken, session, time.Duration(a.refreshTtl)*time.Minute)
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, err)
 		return
 	}
+	accessToken, err := a.tokenHandler.CreateAccessToken(time.Duration(a.accessTtl)*time.Minute, user.Id, sessionId)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Internal, err))
+		return
+	}
 
 	ctx.SetCookie("access_token", accessToken, a.accessTtl*60, "/", a.host, false, true)
 	ctx.SetCookie("refresh_token", refreshToken, a.refreshTtl*60, "/", a.host, false, true)
@@ -106,34 +110,73 @@ func (a *authHandler) SignInUser(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "access_token": accessToken})
 }
 
+// RefreshAccessToken exchanges the refresh token for a new pair of tokens. The
+// old refresh token can't be used again, its reuse signs out every session of
+// the family.
 func (a *authHandler) RefreshAccessToken(ctx *gin.Context) {
 	refreshToken, err := ctx.Cookie("refresh_token")
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Unauthorized, err))
 		return
 	}
-	a.logger.Infof("get refreshToken from cookie = %v", refreshToken)
 	err = a.tokenHandler.ValidateRefreshToken(refreshToken)
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Unauthorized, err))
 		return
 	}
-	userId, err := a.tokenService.Find(refreshToken)
+	token, err := a.tokenService.Find(refreshToken)
 	if err != nil {
-		errs.HTTPErrorResponse(ctx, a.logger, err)
+		a.rejectRefresh(ctx, err)
 		return
 	}
-	accessToken, err := a.tokenHandler.CreateAccessToken(time.Duration(a.refreshTtl)*time.Minute, userId)
+	accessToken, err := a.tokenHandler.CreateAccessToken(time.Duration(a.accessTtl)*time.Minute, token.UserId, token.Family)
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Internal, err))
 		return
 	}
+	newRefreshToken, err := a.tokenHandler.CreateRefreshToken(time.Duration(a.refreshTtl)*time.Minute, token.UserId)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Internal, err))
+		return
+	}
+	err = a.tokenService.Rotate(refreshToken, newRefreshToken, time.Duration(a.refreshTtl)*time.Minute)
+	if err != nil {
+		a.rejectRefresh(ctx, err)
+		return
+	}
 	ctx.SetCookie("access_token", accessToken, a.accessTtl*60, "/", a.host, false, true)
+	ctx.SetCookie("refresh_token", newRefreshToken, a.refreshTtl*60, "/", a.host, false, true)
 	ctx.SetCookie("logged_in", "true", a.accessTtl*60, "/", a.host, false, false)
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success", "access_token": accessToken})
 }
 
+// rejectRefresh clears the cookies of a refresh token that is no longer
+// accepted, so that the client signs in again.
+func (a *authHandler) rejectRefresh(ctx *gin.Context, err error) {
+	var e *errs.Error
+	if errors.As(err, &e) && e.Kind == errs.Unauthorized {
+		a.clearCookies(ctx)
+	}
+	errs.HTTPErrorResponse(ctx, a.logger, err)
+}
+
+// revokeAccessToken denies the access token the request was authorized with,
+// so that it isn't accepted until it expires.
+func (a *authHandler) revokeAccessToken(ctx *gin.Context) error {
+	claims, ok := middleware.Claims(ctx)
+	if !ok || claims.Id == "" {
+		return nil
+	}
+	return a.tokenService.Revoke(claims.Id, claims.ExpiresAt)
+}
+
+func (a *authHandler) clearCookies(ctx *gin.Context) {
+	ctx.SetCookie("access_token", "", -1, "/", a.host, false, true)
+	ctx.SetCookie("refresh_token", "", -1, "/", a.host, false, true)
+	ctx.SetCookie("logged_in", "", -1, "/", a.host, false, true)
+}
+
 func (a *authHandler) Logout(ctx *gin.Context) {
 	refreshToken, err := ctx.Cookie("refresh_token")
 	if err != nil {
@@ -145,14 +188,17 @@ func (a *authHandler) Logout(ctx *gin.Context) {
 		errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Unauthorized, err))
 		return
 	}
+	err = a.revokeAccessToken(ctx)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
 	err = a.tokenService.Remove(refreshToken)
 	if err != nil {
 		errs.HTTPErrorResponse(ctx, a.logger, err)
 		return
 	}
-	ctx.SetCookie("access_token", "", -1, "/", a.host, false, true)
-	ctx.SetCookie("refresh_token", "", -1, "/", a.host, false, true)
-	ctx.SetCookie("logged_in", "", -1, "/", a.host, false, true)
+	a.clearCookies(ctx)
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
 
@@ -106,9 +106,9 @@ func TestSignInUser(t *testing.T) {
 			mock: func(accessToken string, refreshToken string) {
 				user := entity.User{Username: "username", Password: "$2a$10$EY89/z9fLxDtT0V18CMYje2K5.q28PPkbaQAuvLJ8pJJF.nElg.r6", CreateAt: time.Now(), Id: 1}
 				mockUserService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(user, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return(accessToken, nil)
 				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(refreshToken, nil)
-				mockTokenService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockTokenService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return("session", nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), "session").Return(accessToken, nil)
 			},
 			inputRequest: `{"username":"username","password":"my_password"}`,
 			expectedCode: 200,
@@ -132,7 +132,9 @@ func TestSignInUser(t *testing.T) {
 			mock: func(accessToken string, refreshToken string) {
 				user := entity.User{Username: "username", Password: "$2a$10$EY89/z9fLxDtT0V18CMYje2K5.q28PPkbaQAuvLJ8pJJF.nElg.r6", CreateAt: time.Now(), Id: 1}
 				mockUserService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(user, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return(accessToken, errors.New("internal token service error"))
+				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(refreshToken, nil)
+				mockTokenService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return("session", nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), "session").Return(accessToken, errors.New("internal token service error"))
 			},
 			inputRequest: `{"username":"username","password":"my_password"}`,
 			expectedCode: 500,
@@ -145,7 +147,6 @@ func TestSignInUser(t *testing.T) {
 			mock: func(accessToken string, refreshToken string) {
 				user := entity.User{Username: "username", Password: "$2a$10$EY89/z9fLxDtT0V18CMYje2K5.q28PPkbaQAuvLJ8pJJF.nElg.r6", CreateAt: time.Now(), Id: 1}
 				mockUserService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(user, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return(accessToken, nil)
 				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(refreshToken, errors.New("internal token service error"))
 			},
 			inputRequest: `{"username":"username","password":"my_password"}`,
@@ -159,9 +160,8 @@ func TestSignInUser(t *testing.T) {
 			mock: func(accessToken string, refreshToken string) {
 				user := entity.User{Username: "username", Password: "$2a$10$EY89/z9fLxDtT0V18CMYje2K5.q28PPkbaQAuvLJ8pJJF.nElg.r6", CreateAt: time.Now(), Id: 1}
 				mockUserService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(user, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return(accessToken, nil)
 				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return(refreshToken, nil)
-				mockTokenService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return(errs.New(errs.Internal))
+				mockTokenService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errs.New(errs.Internal))
 
 			},
 			inputRequest: `{"username":"username","password":"my_password"}`,
@@ -222,15 +222,61 @@ func TestRefreshAccessToken(t *testing.T) {
 			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
 				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
 				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
-				mockTokenService.EXPECT().Find(gomock.Any()).Return(userId, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return(accessToken, nil)
-
+				mockTokenService.EXPECT().Find("encodedRefreshToken").Return(entity.RefreshToken{UserId: userId, Family: "session"}, nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), userId, "session").Return(accessToken, nil)
+				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return("newRefreshToken", nil)
+				mockTokenService.EXPECT().Rotate("encodedRefreshToken", "newRefreshToken", 15*time.Minute).Return(nil)
 			},
 			expectedCode:   200,
-			wantedTokens:   []string{"newAccessToken", "encodedRefreshToken"},
+			wantedTokens:   []string{"newAccessToken", "newRefreshToken"},
 			wantedResponse: "{\"access_token\":\"newAccessToken\",\"status\":\"success\"}",
 			isError:        false,
 		},
+		{
+			title: "reused refresh token and 403 response with cleared cookies",
+			args:  args{acessToken: "newAccessToken", userId: 1},
+			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
+				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
+				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
+				mockTokenService.EXPECT().Find(gomock.Any()).Return(entity.RefreshToken{UserId: userId}, nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(accessToken, nil)
+				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return("newRefreshToken", nil)
+				mockTokenService.EXPECT().Rotate(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errs.New(errs.Unauthorized, errs.Code("refresh token is reused"), "refresh token is reused"))
+			},
+			expectedCode:   403,
+			wantedTokens:   []string{"", ""},
+			wantedResponse: "\"refresh token is reused\"",
+			isError:        true,
+		},
+		{
+			title: "revoked refresh token and 403 response with cleared cookies",
+			args:  args{acessToken: "", userId: 0},
+			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
+				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
+				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
+				mockTokenService.EXPECT().Find(gomock.Any()).Return(entity.RefreshToken{}, errs.New(errs.Unauthorized, errs.Code("refresh token is revoked"), "refresh token is revoked"))
+			},
+			expectedCode:   403,
+			wantedTokens:   []string{"", ""},
+			wantedResponse: "\"refresh token is revoked\"",
+			isError:        true,
+		},
+		{
+			title: "cannot create new refresh token and 500 response",
+			args:  args{acessToken: "newAccessToken", userId: 1},
+			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
+				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
+				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
+				mockTokenService.EXPECT().Find(gomock.Any()).Return(entity.RefreshToken{UserId: userId}, nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return(accessToken, nil)
+				mockTokenHandler.EXPECT().CreateRefreshToken(gomock.Any(), gomock.Any()).Return("", errors.New("internal token handler error"))
+			},
+			expectedCode:   500,
+			wantedTokens:   []string{},
+			wantedResponse: "\"{\\\"error\\\":{\\\"kind\\\":\\\"internal_error\\\",\\\"message\\\":\\\"internal server error - please contact support\\\"}}\"",
+			isError:        true,
+		},
 		{
 			title: "cannot validate token and 403 response",
 			args:  args{acessToken: "", userId: 0},
@@ -250,7 +296,7 @@ func TestRefreshAccessToken(t *testing.T) {
 			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
 				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
 				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
-				mockTokenService.EXPECT().Find(gomock.Any()).Return(-1, errs.New(errs.Database))
+				mockTokenService.EXPECT().Find(gomock.Any()).Return(entity.RefreshToken{}, errs.New(errs.Database))
 
 			},
 			expectedCode:   500,
@@ -264,8 +310,8 @@ func TestRefreshAccessToken(t *testing.T) {
 			mock: func(recorder *httptest.ResponseRecorder, userId int, accessToken string) {
 				http.SetCookie(recorder, &http.Cookie{Name: "refresh_token", Value: "encodedRefreshToken"})
 				mockTokenHandler.EXPECT().ValidateRefreshToken(gomock.Any()).Return(nil)
-				mockTokenService.EXPECT().Find(gomock.Any()).Return(userId, nil)
-				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any()).Return("", errors.New("internal token handler error"))
+				mockTokenService.EXPECT().Find(gomock.Any()).Return(entity.RefreshToken{UserId: userId}, nil)
+				mockTokenHandler.EXPECT().CreateAccessToken(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("internal token handler error"))
 			},
 			expectedCode:   500,
 			wantedTokens:   []string{},
@@ -293,16 +339,15 @@ func TestRefreshAccessToken(t *testing.T) {
 			test.mock(recorder, test.args.userId, test.args.acessToken)
 			req.Header = http.Header{"Cookie": recorder.Result().Header["Set-Cookie"]}
 			router.ServeHTTP(recorder, req)
-			if !test.isError {
-				coockies := recorder.Result().Cookies()
-				for _, c := range coockies {
-					if c.Name == "access_token" {
-						assert.Equal(t, test.wantedTokens[0], c.Value)
-					} else if c.Name == "refresh_token" {
-						assert.Equal(t, test.wantedTokens[1], c.Value)
-					}
+			if len(test.wantedTokens) > 0 {
+				// the result of the recorder is cached before serving, the handler
+				// cookies follow the one set for the request in the header
+				coockies := make(map[string]string)
+				for _, c := range (&http.Response{Header: recorder.Header()}).Cookies() {
+					coockies[c.Name] = c.Value
 				}
-
+				assert.Equal(t, test.wantedTokens[0], coockies["access_token"])
+				assert.Equal(t, test.wantedTokens[1], coockies["refresh_token"])
 			}
 			assert.Equal(t, test.wantedResponse, recorder.Body.String())
 			assert.Equal(t, test.expectedCode, recorder.Code)
@@ -380,16 +425,15 @@ func TestLogout(t *testing.T) {
 			router.GET("/logout", authHandler.Logout)
 			req := test.mock()
 			router.ServeHTTP(recorder, req)
-			if !test.isError {
-				coockies := recorder.Result().Cookies()
-				for _, c := range coockies {
-					if c.Name == "access_token" {
-						assert.Equal(t, test.wantedTokens[0], c.Value)
-					} else if c.Name == "refresh_token" {
-						assert.Equal(t, test.wantedTokens[1], c.Value)
-					}
+			if len(test.wantedTokens) > 0 {
+				// the result of the recorder is cached before serving, the handler
+				// cookies follow the one set for the request in the header
+				coockies := make(map[string]string)
+				for _, c := range (&http.Response{Header: recorder.Header()}).Cookies() {
+					coockies[c.Name] = c.Value
 				}
-
+				assert.Equal(t, test.wantedTokens[0], coockies["access_token"])
+				assert.Equal(t, test.wantedTokens[1], coockies["refresh_token"])
 			}
 			assert.Equal(t, test.wantedResponse, recorder.Body.String())
 			assert.Equal(t, test.expectedCode, recorder.Code)
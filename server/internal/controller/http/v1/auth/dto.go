@@ -26,3 +26,21 @@ func ResponseFromEntity(user entity.User) UserResponse {
 	dt := user.CreateAt.Format(time.RFC3339)
 	return UserResponse{Username: user.Username, Password: user.Password, CreateAt: dt}
 }
+
+type SessionView struct {
+	Id         string `json:"id"`
+	UserAgent  string `json:"user_agent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"created_at"`
+	LastUsedAt string `json:"last_used_at"`
+}
+
+func SessionViewFromEntity(session entity.Session) SessionView {
+	return SessionView{
+		Id:         session.Id,
+		UserAgent:  session.UserAgent,
+		IP:         session.IP,
+		CreatedAt:  session.CreatedAt.Format(time.RFC3339),
+		LastUsedAt: session.LastUsedAt.Format(time.RFC3339),
+	}
+}
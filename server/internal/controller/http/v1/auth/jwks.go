@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/VrMolodyakov/stock-market/pkg/token"
+	"github.com/gin-gonic/gin"
+)
+
+type KeySet interface {
+	JWKS() token.JWKSet
+}
+
+type jwksHandler struct {
+	keys KeySet
+}
+
+func NewJwksHandler(keys KeySet) *jwksHandler {
+	return &jwksHandler{keys: keys}
+}
+
+// Keys publishes the public keys of the access tokens, so that other services
+// can verify them. Clients may cache them for a short time as keys rotate.
+func (j *jwksHandler) Keys(ctx *gin.Context) {
+	ctx.Header("Cache-Control", "public, max-age=300")
+	ctx.JSON(http.StatusOK, j.keys.JWKS())
+}
@@ -0,0 +1,36 @@
+package v1
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+type staticKeys token.JWKSet
+
+func (s staticKeys) JWKS() token.JWKSet {
+	return token.JWKSet(s)
+}
+
+func TestJwksKeys(t *testing.T) {
+	keys := token.JWKSet{Keys: []token.JWK{{Kty: "RSA", Use: "sig", Alg: "RS512", Kid: "2026-02", N: "n", E: "AQAB"}}}
+	jwksHandler := NewJwksHandler(staticKeys(keys))
+	router := gin.Default()
+	router.GET("/api/.well-known/jwks.json", jwksHandler.Keys)
+	req, _ := http.NewRequest("GET", "/api/.well-known/jwks.json", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "public, max-age=300", recorder.Header().Get("Cache-Control"))
+	var got token.JWKSet
+	if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, keys, got)
+}
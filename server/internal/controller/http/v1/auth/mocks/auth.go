@@ -90,18 +90,18 @@ func (m *MockTokenHandler) EXPECT() *MockTokenHandlerMockRecorder {
 }
 
 // CreateAccessToken mocks base method.
-func (m *MockTokenHandler) CreateAccessToken(ttl time.Duration, payload interface{}) (string, error) {
+func (m *MockTokenHandler) CreateAccessToken(ttl time.Duration, payload interface{}, session string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateAccessToken", ttl, payload)
+	ret := m.ctrl.Call(m, "CreateAccessToken", ttl, payload, session)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateAccessToken indicates an expected call of CreateAccessToken.
-func (mr *MockTokenHandlerMockRecorder) CreateAccessToken(ttl, payload interface{}) *gomock.Call {
+func (mr *MockTokenHandlerMockRecorder) CreateAccessToken(ttl, payload, session interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockTokenHandler)(nil).CreateAccessToken), ttl, payload)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAccessToken", reflect.TypeOf((*MockTokenHandler)(nil).CreateAccessToken), ttl, payload, session)
 }
 
 // CreateRefreshToken mocks base method.
@@ -157,10 +157,10 @@ func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
 }
 
 // Find mocks base method.
-func (m *MockTokenService) Find(refreshToken string) (int, error) {
+func (m *MockTokenService) Find(refreshToken string) (entity.RefreshToken, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Find", refreshToken)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(entity.RefreshToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -185,16 +185,88 @@ func (mr *MockTokenServiceMockRecorder) Remove(refreshToken interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockTokenService)(nil).Remove), refreshToken)
 }
 
-// Save mocks base method.
-func (m *MockTokenService) Save(refreshToken string, userId int, expireAt time.Duration) error {
+// RemoveSession mocks base method.
+func (m *MockTokenService) RemoveSession(userId int, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSession", userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSession indicates an expected call of RemoveSession.
+func (mr *MockTokenServiceMockRecorder) RemoveSession(userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSession", reflect.TypeOf((*MockTokenService)(nil).RemoveSession), userId, id)
+}
+
+// RemoveSessions mocks base method.
+func (m *MockTokenService) RemoveSessions(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSessions", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSessions indicates an expected call of RemoveSessions.
+func (mr *MockTokenServiceMockRecorder) RemoveSessions(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSessions", reflect.TypeOf((*MockTokenService)(nil).RemoveSessions), userId)
+}
+
+// Revoke mocks base method.
+func (m *MockTokenService) Revoke(id string, expiresAt time.Time) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", refreshToken, userId, expireAt)
+	ret := m.ctrl.Call(m, "Revoke", id, expiresAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
+// Revoke indicates an expected call of Revoke.
+func (mr *MockTokenServiceMockRecorder) Revoke(id, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoke", reflect.TypeOf((*MockTokenService)(nil).Revoke), id, expiresAt)
+}
+
+// Rotate mocks base method.
+func (m *MockTokenService) Rotate(oldToken, newToken string, expireAt time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", oldToken, newToken, expireAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockTokenServiceMockRecorder) Rotate(oldToken, newToken, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockTokenService)(nil).Rotate), oldToken, newToken, expireAt)
+}
+
+// Save mocks base method.
+func (m *MockTokenService) Save(refreshToken string, session entity.Session, expireAt time.Duration) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", refreshToken, session, expireAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
 // Save indicates an expected call of Save.
-func (mr *MockTokenServiceMockRecorder) Save(refreshToken, userId, expireAt interface{}) *gomock.Call {
+func (mr *MockTokenServiceMockRecorder) Save(refreshToken, session, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTokenService)(nil).Save), refreshToken, session, expireAt)
+}
+
+// Sessions mocks base method.
+func (m *MockTokenService) Sessions(userId int) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sessions", userId)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sessions indicates an expected call of Sessions.
+func (mr *MockTokenServiceMockRecorder) Sessions(userId interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockTokenService)(nil).Save), refreshToken, userId, expireAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sessions", reflect.TypeOf((*MockTokenService)(nil).Sessions), userId)
 }
@@ -13,13 +13,18 @@ type UserService interface {
 }
 
 type TokenHandler interface {
-	CreateAccessToken(ttl time.Duration, payload interface{}) (string, error)
+	CreateAccessToken(ttl time.Duration, payload interface{}, session string) (string, error)
 	CreateRefreshToken(ttl time.Duration, payload interface{}) (string, error)
 	ValidateRefreshToken(token string) error
 }
 
 type TokenService interface {
-	Save(refreshToken string, userId int, expireAt time.Duration) error
-	Find(refreshToken string) (int, error)
+	Save(refreshToken string, session entity.Session, expireAt time.Duration) (string, error)
+	Find(refreshToken string) (entity.RefreshToken, error)
+	Rotate(oldToken string, newToken string, expireAt time.Duration) error
 	Remove(refreshToken string) error
+	Sessions(userId int) ([]entity.Session, error)
+	RemoveSession(userId int, id string) error
+	RemoveSessions(userId int) error
+	Revoke(id string, expiresAt time.Time) error
 }
@@ -0,0 +1,61 @@
+package v1
+
+import (
+	"net/http"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/gin-gonic/gin"
+)
+
+// Sessions returns the signed in devices of the current user.
+func (a *authHandler) Sessions(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	sessions, err := a.tokenService.Sessions(user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	views := make([]SessionView, len(sessions))
+	for i, session := range sessions {
+		views[i] = SessionViewFromEntity(session)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": views})
+}
+
+// RevokeSession signs out one device, its refresh token is no longer accepted.
+func (a *authHandler) RevokeSession(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	err := a.tokenService.RemoveSession(user.Id, ctx.Param("id"))
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+// RevokeSessions signs out every device of the user including this one.
+func (a *authHandler) RevokeSessions(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, a.logger)
+	if !ok {
+		return
+	}
+	err := a.revokeAccessToken(ctx)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	err = a.tokenService.RemoveSessions(user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, a.logger, err)
+		return
+	}
+	a.clearCookies(ctx)
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
@@ -0,0 +1,156 @@
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/auth/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/token"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessions(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockTokenService := mocks.NewMockTokenService(cntr)
+	authHandler := NewAuthHandler(mocks.NewMockUserService(cntr), logging.GetLogger("debug"), mocks.NewMockTokenHandler(cntr), mockTokenService, "localhost", 15, 15)
+	user := entity.User{Id: 7, Username: "user"}
+	claims := token.Claims{Subject: 7.0, Id: "token id", ExpiresAt: time.Unix(300, 0)}
+	laptop := entity.Session{Id: "laptop", UserId: 7, UserAgent: "Firefox", IP: "10.0.0.1", CreatedAt: time.Unix(100, 0).UTC(), LastUsedAt: time.Unix(200, 0).UTC()}
+	type mockCall func()
+	testCases := []struct {
+		title         string
+		method        string
+		path          string
+		noUser        bool
+		mockCall      mockCall
+		expectedCode  int
+		want          []SessionView
+		clearsCookies bool
+	}{
+		{
+			title:  "sessions of the user and 200 response",
+			method: http.MethodGet,
+			path:   "/api/auth/sessions",
+			mockCall: func() {
+				mockTokenService.EXPECT().Sessions(7).Return([]entity.Session{laptop}, nil)
+			},
+			expectedCode: 200,
+			want: []SessionView{{
+				Id:         "laptop",
+				UserAgent:  "Firefox",
+				IP:         "10.0.0.1",
+				CreatedAt:  "1970-01-01T00:01:40Z",
+				LastUsedAt: "1970-01-01T00:03:20Z",
+			}},
+		},
+		{
+			title:  "couldn't get sessions and 500 response",
+			method: http.MethodGet,
+			path:   "/api/auth/sessions",
+			mockCall: func() {
+				mockTokenService.EXPECT().Sessions(7).Return(nil, errs.New(errs.Database, "redis error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:  "revoke session and 200 response",
+			method: http.MethodDelete,
+			path:   "/api/auth/sessions/laptop",
+			mockCall: func() {
+				mockTokenService.EXPECT().RemoveSession(7, "laptop").Return(nil)
+			},
+			expectedCode: 200,
+		},
+		{
+			title:  "unknown session and 404 response",
+			method: http.MethodDelete,
+			path:   "/api/auth/sessions/phone",
+			mockCall: func() {
+				mockTokenService.EXPECT().RemoveSession(7, "phone").
+					Return(errs.New(errs.NotExist, errs.Code("session not found"), errs.Parameter("id")))
+			},
+			expectedCode: 404,
+		},
+		{
+			title:  "log out everywhere and 200 response",
+			method: http.MethodDelete,
+			path:   "/api/auth/sessions",
+			mockCall: func() {
+				mockTokenService.EXPECT().Revoke("token id", claims.ExpiresAt).Return(nil)
+				mockTokenService.EXPECT().RemoveSessions(7).Return(nil)
+			},
+			expectedCode:  200,
+			clearsCookies: true,
+		},
+		{
+			title:  "couldn't log out everywhere and 500 response",
+			method: http.MethodDelete,
+			path:   "/api/auth/sessions",
+			mockCall: func() {
+				mockTokenService.EXPECT().Revoke("token id", claims.ExpiresAt).Return(nil)
+				mockTokenService.EXPECT().RemoveSessions(7).Return(errors.New("redis error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:  "couldn't revoke access token and 500 response",
+			method: http.MethodDelete,
+			path:   "/api/auth/sessions",
+			mockCall: func() {
+				mockTokenService.EXPECT().Revoke("token id", claims.ExpiresAt).Return(errs.New(errs.Database, "redis error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:        "missing user and 403 response",
+			method:       http.MethodGet,
+			path:         "/api/auth/sessions",
+			noUser:       true,
+			mockCall:     func() {},
+			expectedCode: 403,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			group := router.Group("/api/auth", func(ctx *gin.Context) {
+				if !test.noUser {
+					ctx.Set("user", user)
+					ctx.Set("claims", claims)
+				}
+			})
+			group.GET("/sessions", authHandler.Sessions)
+			group.DELETE("/sessions", authHandler.RevokeSessions)
+			group.DELETE("/sessions/:id", authHandler.RevokeSession)
+			req, _ := http.NewRequest(test.method, test.path, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.clearsCookies {
+				for _, c := range recorder.Result().Cookies() {
+					assert.Empty(t, c.Value)
+					assert.True(t, c.MaxAge < 0)
+				}
+			}
+			if test.want != nil {
+				var response struct {
+					Data []SessionView `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.want, response.Data)
+			}
+		})
+	}
+}
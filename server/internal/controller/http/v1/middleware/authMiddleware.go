@@ -3,23 +3,31 @@ package middleware
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/token"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	userKey   = "user"
+	claimsKey = "claims"
+)
+
 type UserService interface {
 	GetById(ctx context.Context, id int) (entity.User, error)
 }
 
 type TokenHandler interface {
-	ValidateAccessToken(token string) (interface{}, error)
+	ValidateAccessToken(accessToken string) (token.Claims, error)
 }
 
 type TokenService interface {
-	Find(refreshToken string) (int, error)
+	Find(refreshToken string) (entity.RefreshToken, error)
+	Revoked(id string, session string, expiresAt time.Time) (bool, error)
 }
 
 type authMiddleware struct {
@@ -50,13 +58,24 @@ func (a *authMiddleware) Auth() gin.HandlerFunc {
 			return
 		}
 		a.logger.Debug("access token:", accessToken)
-		sub, err := a.tokenHandler.ValidateAccessToken(accessToken)
+		claims, err := a.tokenHandler.ValidateAccessToken(accessToken)
 		if err != nil {
 			ctx.Abort()
 			errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Unauthorized, err))
 			return
 		}
-		userId := sub.(float64)
+		revoked, err := a.tokenService.Revoked(claims.Id, claims.Session, claims.ExpiresAt)
+		if err != nil {
+			ctx.Abort()
+			errs.HTTPErrorResponse(ctx, a.logger, err)
+			return
+		}
+		if revoked {
+			ctx.Abort()
+			errs.HTTPErrorResponse(ctx, a.logger, errs.New(errs.Unauthorized, errs.Code("access token is revoked"), "access token is revoked"))
+			return
+		}
+		userId := claims.Subject.(float64)
 		user, err := a.userService.GetById(ctx, int(userId))
 		if err != nil {
 			ctx.Abort()
@@ -64,8 +83,29 @@ func (a *authMiddleware) Auth() gin.HandlerFunc {
 			return
 		}
 		a.logger.Debugf("set current context user %v = ", user)
-		ctx.Set("user", user)
+		ctx.Set(userKey, user)
+		ctx.Set(claimsKey, claims)
 		ctx.Next()
 	}
 
 }
+
+// User returns the user set by Auth. It responds with unauthorized when the
+// request didn't pass through the middleware.
+func User(ctx *gin.Context, logger *logging.Logger) (entity.User, bool) {
+	value, exists := ctx.Get(userKey)
+	user, ok := value.(entity.User)
+	if !exists || !ok {
+		errs.HTTPErrorResponse(ctx, logger, errs.New(errs.Unauthorized, "user not found in context"))
+		return entity.User{}, false
+	}
+	return user, true
+}
+
+// Claims returns the claims of the access token the request was authorized
+// with by Auth.
+func Claims(ctx *gin.Context) (token.Claims, bool) {
+	value, exists := ctx.Get(claimsKey)
+	claims, ok := value.(token.Claims)
+	return claims, exists && ok
+}
@@ -5,10 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware/mocks"
 	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/token"
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -21,6 +24,7 @@ func TestAuthMiddleware(t *testing.T) {
 	userService := mocks.NewMockUserService(cntr)
 	logger := logging.GetLogger("debug")
 	authMiddleware := NewAuthMiddleware(userService, tokenService, tokenHandler, logger)
+	claims := token.Claims{Subject: 1.0, Id: "token id", Session: "laptop", ExpiresAt: time.Unix(100, 0)}
 	type mockCall func(req *http.Request)
 	testCases := []struct {
 		title      string
@@ -33,20 +37,47 @@ func TestAuthMiddleware(t *testing.T) {
 			title: "find access token and succes response",
 			mockCall: func(req *http.Request) {
 				req.AddCookie(&http.Cookie{Name: "access_token", Value: "encodedAccessToken", MaxAge: 60 * 60, Path: "/", Domain: "localhost", Secure: false, HttpOnly: true})
-				var i interface{}
-				i = 1.0
 				user := entity.User{Id: 1, Username: "some-username"}
-				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(i, nil)
+				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(claims, nil)
+				tokenService.EXPECT().Revoked("token id", "laptop", claims.ExpiresAt).Return(false, nil)
 				userService.EXPECT().GetById(gomock.Any(), gomock.Any()).Return(user, nil)
 			},
 			handler: func(ctx *gin.Context) {
-				user := ctx.MustGet("user").(entity.User)
+				user, ok := User(ctx, logger)
+				assert.True(t, ok)
 				assert.Equal(t, "some-username", user.Username)
+				got, ok := Claims(ctx)
+				assert.True(t, ok)
+				assert.Equal(t, claims, got)
 				ctx.JSON(http.StatusOK, "success")
 			},
 			wantedCode: 200,
 			wantedBody: "\"success\"",
 		},
+		{
+			title: "revoked access token and 403 response",
+			mockCall: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: "encodedAccessToken", MaxAge: 60 * 60, Path: "/", Domain: "localhost", Secure: false, HttpOnly: true})
+				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(claims, nil)
+				tokenService.EXPECT().Revoked("token id", "laptop", claims.ExpiresAt).Return(true, nil)
+			},
+			handler: func(ctx *gin.Context) {
+			},
+			wantedCode: 403,
+			wantedBody: "\"access token is revoked\"",
+		},
+		{
+			title: "cannot check denylist and 500 response",
+			mockCall: func(req *http.Request) {
+				req.AddCookie(&http.Cookie{Name: "access_token", Value: "encodedAccessToken", MaxAge: 60 * 60, Path: "/", Domain: "localhost", Secure: false, HttpOnly: true})
+				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(claims, nil)
+				tokenService.EXPECT().Revoked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, errs.New(errs.Database, "redis error"))
+			},
+			handler: func(ctx *gin.Context) {
+			},
+			wantedCode: 500,
+			wantedBody: "\"{\\\"error\\\":{\\\"kind\\\":\\\"internal_error\\\",\\\"message\\\":\\\"internal server error - please contact support\\\"}}\"",
+		},
 		{
 			title: "cannot find access token and 403 response",
 			mockCall: func(req *http.Request) {
@@ -60,7 +91,7 @@ func TestAuthMiddleware(t *testing.T) {
 			title: "cannot validate access token and 403 response",
 			mockCall: func(req *http.Request) {
 				req.AddCookie(&http.Cookie{Name: "access_token", Value: "encodedAccessToken", MaxAge: 60 * 60, Path: "/", Domain: "localhost", Secure: false, HttpOnly: true})
-				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(nil, errors.New("token handler internal error"))
+				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(token.Claims{}, errors.New("token handler internal error"))
 
 			},
 			handler: func(ctx *gin.Context) {
@@ -72,9 +103,8 @@ func TestAuthMiddleware(t *testing.T) {
 			title: "cannot find user id and 403 response",
 			mockCall: func(req *http.Request) {
 				req.AddCookie(&http.Cookie{Name: "access_token", Value: "encodedAccessToken", MaxAge: 60 * 60, Path: "/", Domain: "localhost", Secure: false, HttpOnly: true})
-				var i interface{}
-				i = 1.0
-				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(i, nil)
+				tokenHandler.EXPECT().ValidateAccessToken(gomock.Any()).Return(claims, nil)
+				tokenService.EXPECT().Revoked(gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil)
 				userService.EXPECT().GetById(gomock.Any(), gomock.Any()).Return(entity.User{}, errors.New("cannnot find user with given id"))
 			},
 			handler: func(ctx *gin.Context) {
@@ -98,3 +128,17 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestUserWithoutAuth(t *testing.T) {
+	logger := logging.GetLogger("debug")
+	w := httptest.NewRecorder()
+	_, router := gin.CreateTestContext(w)
+	router.GET("/", func(ctx *gin.Context) {
+		_, ok := User(ctx, logger)
+		assert.False(t, ok)
+	})
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	assert.NoError(t, err)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
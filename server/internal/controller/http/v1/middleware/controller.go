@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type controllerKey struct{}
+
+// WithResponseController puts the controller of the response into the context
+// of the request, gin's writer doesn't give access to it. Long lived
+// responses use it to lift the write timeout of the server.
+func WithResponseController(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), controllerKey{}, http.NewResponseController(w))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ResponseController returns the controller set by WithResponseController,
+// or the controller of gin's writer when the handler isn't wrapped.
+func ResponseController(ctx *gin.Context) *http.ResponseController {
+	if controller, ok := ctx.Request.Context().Value(controllerKey{}).(*http.ResponseController); ok {
+		return controller
+	}
+	return http.NewResponseController(ctx.Writer)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSPreflight(t *testing.T) {
+	w := httptest.NewRecorder()
+	_, router := gin.CreateTestContext(w)
+	router.Use(CORSMiddleware())
+	router.DELETE("/api/watchlists/:id", func(ctx *gin.Context) {
+		t.Fatal("the preflight reached the handler")
+	})
+	req, err := http.NewRequest(http.MethodOptions, "/api/watchlists/1", nil)
+	assert.NoError(t, err)
+	req.Header.Set("Origin", "http://localhost:3001")
+	req.Header.Set("Access-Control-Request-Method", http.MethodDelete)
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "http://localhost:3001", w.Header().Get("Access-Control-Allow-Origin"))
+	methods := strings.Split(w.Header().Get("Access-Control-Allow-Methods"), ", ")
+	assert.Contains(t, methods, http.MethodDelete)
+}
@@ -7,8 +7,10 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	token "github.com/VrMolodyakov/stock-market/pkg/token"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -74,18 +76,18 @@ func (m *MockTokenHandler) EXPECT() *MockTokenHandlerMockRecorder {
 }
 
 // ValidateAccessToken mocks base method.
-func (m *MockTokenHandler) ValidateAccessToken(token string) (interface{}, error) {
+func (m *MockTokenHandler) ValidateAccessToken(accessToken string) (token.Claims, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ValidateAccessToken", token)
-	ret0, _ := ret[0].(interface{})
+	ret := m.ctrl.Call(m, "ValidateAccessToken", accessToken)
+	ret0, _ := ret[0].(token.Claims)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ValidateAccessToken indicates an expected call of ValidateAccessToken.
-func (mr *MockTokenHandlerMockRecorder) ValidateAccessToken(token interface{}) *gomock.Call {
+func (mr *MockTokenHandlerMockRecorder) ValidateAccessToken(accessToken interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAccessToken", reflect.TypeOf((*MockTokenHandler)(nil).ValidateAccessToken), token)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ValidateAccessToken", reflect.TypeOf((*MockTokenHandler)(nil).ValidateAccessToken), accessToken)
 }
 
 // MockTokenService is a mock of TokenService interface.
@@ -112,10 +114,10 @@ func (m *MockTokenService) EXPECT() *MockTokenServiceMockRecorder {
 }
 
 // Find mocks base method.
-func (m *MockTokenService) Find(refreshToken string) (int, error) {
+func (m *MockTokenService) Find(refreshToken string) (entity.RefreshToken, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Find", refreshToken)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(entity.RefreshToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -125,3 +127,18 @@ func (mr *MockTokenServiceMockRecorder) Find(refreshToken interface{}) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockTokenService)(nil).Find), refreshToken)
 }
+
+// Revoked mocks base method.
+func (m *MockTokenService) Revoked(id, session string, expiresAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Revoked", id, session, expiresAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Revoked indicates an expected call of Revoked.
+func (mr *MockTokenServiceMockRecorder) Revoked(id, session, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Revoked", reflect.TypeOf((*MockTokenService)(nil).Revoked), id, session, expiresAt)
+}
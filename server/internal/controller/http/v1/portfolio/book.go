@@ -0,0 +1,99 @@
+package portfolio
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+)
+
+type OrderBookService interface {
+	Place(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error)
+	Cancel(ctx context.Context, userId int, id int) error
+	List(ctx context.Context, userId int) ([]entity.BookOrder, error)
+}
+
+type bookHandler struct {
+	portfolioHandler
+	bookService OrderBookService
+}
+
+func NewBookHandler(metric metric.Metric, logger *logging.Logger, bookService OrderBookService) *bookHandler {
+	return &bookHandler{portfolioHandler: portfolioHandler{metric: metric, logger: logger}, bookService: bookService}
+}
+
+func (b *bookHandler) GetBookOrders(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, b.logger)
+	if !ok {
+		return
+	}
+	orders, err := b.bookService.List(ctx.Request.Context(), user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, b.logger, err)
+		return
+	}
+	response := make([]BookOrderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = BookOrderFromEntity(order)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
+
+func (b *bookHandler) PlaceBookOrder(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, b.logger)
+	if !ok {
+		return
+	}
+	var request BookOrderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		b.metric.HTTPResponseCounter.WithLabelValues("book", "400").Inc()
+		errs.HTTPErrorResponse(ctx, b.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	order, err := b.bookService.Place(ctx.Request.Context(), request.ToEntity(user.Id))
+	if err != nil {
+		b.metric.HTTPResponseCounter.WithLabelValues("book", errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, b.logger, err)
+		return
+	}
+	b.metric.HTTPResponseCounter.WithLabelValues("book", "201").Inc()
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": BookOrderFromEntity(order)})
+}
+
+func (b *bookHandler) CancelBookOrder(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, b.logger)
+	if !ok {
+		return
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, b.logger, errs.New(errs.Validation, errs.Code("incorrect order id"), errs.Parameter("id"), err))
+		return
+	}
+	err = b.bookService.Cancel(ctx.Request.Context(), user.Id, id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, b.logger, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+func (r BookOrderRequest) ToEntity(userId int) entity.BookOrder {
+	return entity.BookOrder{
+		UserId:      userId,
+		Symbol:      r.Symbol,
+		Side:        entity.OrderSide(strings.ToLower(r.Side)),
+		Type:        entity.OrderType(strings.ToLower(r.Type)),
+		Quantity:    r.Quantity,
+		LimitPrice:  r.LimitPrice,
+		StopPrice:   r.StopPrice,
+		TimeInForce: entity.TimeInForce(strings.ToUpper(r.TimeInForce)),
+	}
+}
@@ -0,0 +1,128 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/portfolio/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceBookOrder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	bookService := mocks.NewMockOrderBookService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	handler := NewBookHandler(metric.NewMetric(prometheusClient.Registry()), logging.GetLogger("debug"), bookService)
+	type mockCall func()
+	testCases := []struct {
+		title         string
+		body          string
+		mockCall      mockCall
+		expectedCode  int
+		expectedState string
+	}{
+		{
+			title: "open order and 201 response",
+			body:  `{"symbol":"AAPL","side":"BUY","type":"limit","quantity":2,"limit_price":100,"time_in_force":"gtc"}`,
+			mockCall: func() {
+				bookService.EXPECT().Place(gomock.Any(), entity.BookOrder{
+					UserId: 7, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, LimitPrice: 100, TimeInForce: entity.GTC,
+				}).Return(entity.BookOrder{Id: 5, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, State: entity.Open}, nil)
+			},
+			expectedCode:  201,
+			expectedState: "open",
+		},
+		{
+			title: "missing limit price and 400 response",
+			body:  `{"symbol":"AAPL","side":"buy","type":"limit","quantity":2}`,
+			mockCall: func() {
+				bookService.EXPECT().Place(gomock.Any(), gomock.Any()).
+					Return(entity.BookOrder{}, errs.New(errs.Validation, errs.Code("limit price is required")))
+			},
+			expectedCode: 400,
+		},
+		{
+			title:        "incorrect body and 400 response",
+			body:         `{"quantity":"two"}`,
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.POST("/api/portfolio/book", func(ctx *gin.Context) { ctx.Set("user", entity.User{Id: 7}) }, handler.PlaceBookOrder)
+			req, _ := http.NewRequest(http.MethodPost, "/api/portfolio/book", strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedState != "" {
+				var response struct {
+					Data BookOrderResponse `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.expectedState, response.Data.State)
+			}
+		})
+	}
+}
+
+func TestCancelBookOrder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	bookService := mocks.NewMockOrderBookService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	handler := NewBookHandler(metric.NewMetric(prometheusClient.Registry()), logging.GetLogger("debug"), bookService)
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		id           string
+		mockCall     mockCall
+		expectedCode int
+	}{
+		{
+			title: "cancelled order and 200 response",
+			id:    "3",
+			mockCall: func() {
+				bookService.EXPECT().Cancel(gomock.Any(), 7, 3).Return(nil)
+			},
+			expectedCode: 200,
+		},
+		{
+			title: "inactive order and 404 response",
+			id:    "4",
+			mockCall: func() {
+				bookService.EXPECT().Cancel(gomock.Any(), 7, 4).Return(errs.New(errs.NotExist, errs.Code("active order not found"), "order 4 is not active"))
+			},
+			expectedCode: 404,
+		},
+		{
+			title:        "incorrect id and 400 response",
+			id:           "abc",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.DELETE("/api/portfolio/book/:id", func(ctx *gin.Context) { ctx.Set("user", entity.User{Id: 7}) }, handler.CancelBookOrder)
+			req, _ := http.NewRequest(http.MethodDelete, "/api/portfolio/book/"+test.id, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package portfolio
+
+import (
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type OrderRequest struct {
+	Symbol   string `json:"symbol"`
+	Side     string `json:"side"`
+	Quantity int64  `json:"quantity"`
+}
+
+type OrderResponse struct {
+	Id       int     `json:"id"`
+	Symbol   string  `json:"symbol"`
+	Side     string  `json:"side"`
+	Quantity int64   `json:"quantity"`
+	Price    float64 `json:"price"`
+	Realized float64 `json:"realized"`
+	CreateAt string  `json:"create_at"`
+}
+
+type HoldingResponse struct {
+	Symbol      string  `json:"symbol"`
+	Quantity    int64   `json:"quantity"`
+	AverageCost float64 `json:"average_cost"`
+	Price       float64 `json:"price"`
+	MarketValue float64 `json:"market_value"`
+	Unrealized  float64 `json:"unrealized"`
+}
+
+type PortfolioResponse struct {
+	Cash       float64           `json:"cash"`
+	Equity     float64           `json:"equity"`
+	Realized   float64           `json:"realized"`
+	Unrealized float64           `json:"unrealized"`
+	Holdings   []HoldingResponse `json:"holdings"`
+}
+
+func OrderFromEntity(order entity.Order) OrderResponse {
+	return OrderResponse{
+		Id:       order.Id,
+		Symbol:   order.Symbol,
+		Side:     string(order.Side),
+		Quantity: order.Quantity,
+		Price:    order.Price,
+		Realized: order.Realized,
+		CreateAt: order.CreateAt.Format(time.RFC3339),
+	}
+}
+
+func PortfolioFromEntity(portfolio entity.Portfolio) PortfolioResponse {
+	holdings := make([]HoldingResponse, len(portfolio.Holdings))
+	for i, holding := range portfolio.Holdings {
+		holdings[i] = HoldingResponse{
+			Symbol:      holding.Symbol,
+			Quantity:    holding.Quantity,
+			AverageCost: holding.AverageCost,
+			Price:       holding.Price,
+			MarketValue: holding.MarketValue,
+			Unrealized:  holding.Unrealized,
+		}
+	}
+	return PortfolioResponse{
+		Cash:       portfolio.Cash,
+		Equity:     portfolio.Equity,
+		Realized:   portfolio.Realized,
+		Unrealized: portfolio.Unrealized,
+		Holdings:   holdings,
+	}
+}
+
+type BookOrderRequest struct {
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Type        string  `json:"type"`
+	Quantity    int64   `json:"quantity"`
+	LimitPrice  float64 `json:"limit_price"`
+	StopPrice   float64 `json:"stop_price"`
+	TimeInForce string  `json:"time_in_force"`
+}
+
+type BookOrderResponse struct {
+	Id          int     `json:"id"`
+	Symbol      string  `json:"symbol"`
+	Side        string  `json:"side"`
+	Type        string  `json:"type"`
+	Quantity    int64   `json:"quantity"`
+	Filled      int64   `json:"filled"`
+	LimitPrice  float64 `json:"limit_price,omitempty"`
+	StopPrice   float64 `json:"stop_price,omitempty"`
+	Triggered   bool    `json:"triggered"`
+	TimeInForce string  `json:"time_in_force"`
+	State       string  `json:"state"`
+	ExpiresAt   string  `json:"expires_at,omitempty"`
+	CreateAt    string  `json:"create_at"`
+	UpdateAt    string  `json:"update_at"`
+}
+
+func BookOrderFromEntity(order entity.BookOrder) BookOrderResponse {
+	response := BookOrderResponse{
+		Id:          order.Id,
+		Symbol:      order.Symbol,
+		Side:        string(order.Side),
+		Type:        string(order.Type),
+		Quantity:    order.Quantity,
+		Filled:      order.Filled,
+		LimitPrice:  order.LimitPrice,
+		StopPrice:   order.StopPrice,
+		Triggered:   order.Triggered,
+		TimeInForce: string(order.TimeInForce),
+		State:       string(order.State),
+		CreateAt:    order.CreateAt.Format(time.RFC3339),
+		UpdateAt:    order.UpdateAt.Format(time.RFC3339),
+	}
+	if !order.ExpiresAt.IsZero() {
+		response.ExpiresAt = order.ExpiresAt.Format(time.RFC3339)
+	}
+	return response
+}
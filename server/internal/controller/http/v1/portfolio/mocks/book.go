@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/controller/http/v1/portfolio/book.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOrderBookService is a mock of OrderBookService interface.
+type MockOrderBookService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderBookServiceMockRecorder
+}
+
+// MockOrderBookServiceMockRecorder is the mock recorder for MockOrderBookService.
+type MockOrderBookServiceMockRecorder struct {
+	mock *MockOrderBookService
+}
+
+// NewMockOrderBookService creates a new mock instance.
+func NewMockOrderBookService(ctrl *gomock.Controller) *MockOrderBookService {
+	mock := &MockOrderBookService{ctrl: ctrl}
+	mock.recorder = &MockOrderBookServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderBookService) EXPECT() *MockOrderBookServiceMockRecorder {
+	return m.recorder
+}
+
+// Cancel mocks base method.
+func (m *MockOrderBookService) Cancel(ctx context.Context, userId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cancel", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockOrderBookServiceMockRecorder) Cancel(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockOrderBookService)(nil).Cancel), ctx, userId, id)
+}
+
+// List mocks base method.
+func (m *MockOrderBookService) List(ctx context.Context, userId int) ([]entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, userId)
+	ret0, _ := ret[0].([]entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockOrderBookServiceMockRecorder) List(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockOrderBookService)(nil).List), ctx, userId)
+}
+
+// Place mocks base method.
+func (m *MockOrderBookService) Place(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Place", ctx, order)
+	ret0, _ := ret[0].(entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Place indicates an expected call of Place.
+func (mr *MockOrderBookServiceMockRecorder) Place(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Place", reflect.TypeOf((*MockOrderBookService)(nil).Place), ctx, order)
+}
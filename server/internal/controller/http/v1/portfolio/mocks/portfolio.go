@@ -0,0 +1,81 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/controller/http/v1/portfolio/portfolio.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPortfolioService is a mock of PortfolioService interface.
+type MockPortfolioService struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioServiceMockRecorder
+}
+
+// MockPortfolioServiceMockRecorder is the mock recorder for MockPortfolioService.
+type MockPortfolioServiceMockRecorder struct {
+	mock *MockPortfolioService
+}
+
+// NewMockPortfolioService creates a new mock instance.
+func NewMockPortfolioService(ctrl *gomock.Controller) *MockPortfolioService {
+	mock := &MockPortfolioService{ctrl: ctrl}
+	mock.recorder = &MockPortfolioServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortfolioService) EXPECT() *MockPortfolioServiceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockPortfolioService) Get(ctx context.Context, userId int) (entity.Portfolio, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userId)
+	ret0, _ := ret[0].(entity.Portfolio)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPortfolioServiceMockRecorder) Get(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPortfolioService)(nil).Get), ctx, userId)
+}
+
+// Orders mocks base method.
+func (m *MockPortfolioService) Orders(ctx context.Context, userId int) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orders", ctx, userId)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Orders indicates an expected call of Orders.
+func (mr *MockPortfolioServiceMockRecorder) Orders(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orders", reflect.TypeOf((*MockPortfolioService)(nil).Orders), ctx, userId)
+}
+
+// PlaceOrder mocks base method.
+func (m *MockPortfolioService) PlaceOrder(ctx context.Context, userId int, symbol string, side entity.OrderSide, quantity int64) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceOrder", ctx, userId, symbol, side, quantity)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceOrder indicates an expected call of PlaceOrder.
+func (mr *MockPortfolioServiceMockRecorder) PlaceOrder(ctx, userId, symbol, side, quantity interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceOrder", reflect.TypeOf((*MockPortfolioService)(nil).PlaceOrder), ctx, userId, symbol, side, quantity)
+}
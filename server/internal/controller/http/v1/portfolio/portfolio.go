@@ -0,0 +1,92 @@
+package portfolio
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+)
+
+type PortfolioService interface {
+	Get(ctx context.Context, userId int) (entity.Portfolio, error)
+	PlaceOrder(ctx context.Context, userId int, symbol string, side entity.OrderSide, quantity int64) (entity.Order, error)
+	Orders(ctx context.Context, userId int) ([]entity.Order, error)
+}
+
+type portfolioHandler struct {
+	metric           metric.Metric
+	logger           *logging.Logger
+	portfolioService PortfolioService
+}
+
+func NewPortfolioHandler(metric metric.Metric, logger *logging.Logger, portfolioService PortfolioService) *portfolioHandler {
+	return &portfolioHandler{metric: metric, logger: logger, portfolioService: portfolioService}
+}
+
+func (p *portfolioHandler) GetPortfolio(ctx *gin.Context) {
+	start := time.Now()
+	user, ok := middleware.User(ctx, p.logger)
+	if !ok {
+		return
+	}
+	portfolio, err := p.portfolioService.Get(ctx.Request.Context(), user.Id)
+	if err != nil {
+		p.metric.HTTPResponseCounter.WithLabelValues("portfolio", errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, p.logger, err)
+		return
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	p.metric.ResponseDurationHistogram.WithLabelValues("portfolio").Observe(dur)
+	p.metric.HTTPResponseCounter.WithLabelValues("portfolio", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": PortfolioFromEntity(portfolio)})
+}
+
+func (p *portfolioHandler) PlaceOrder(ctx *gin.Context) {
+	start := time.Now()
+	user, ok := middleware.User(ctx, p.logger)
+	if !ok {
+		return
+	}
+	var request OrderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		p.metric.HTTPResponseCounter.WithLabelValues("order", "400").Inc()
+		errs.HTTPErrorResponse(ctx, p.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	side := entity.OrderSide(strings.ToLower(request.Side))
+	order, err := p.portfolioService.PlaceOrder(ctx.Request.Context(), user.Id, request.Symbol, side, request.Quantity)
+	if err != nil {
+		p.metric.HTTPResponseCounter.WithLabelValues("order", errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, p.logger, err)
+		return
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	p.metric.ResponseDurationHistogram.WithLabelValues("order").Observe(dur)
+	p.metric.HTTPResponseCounter.WithLabelValues("order", "201").Inc()
+	p.metric.BalanceActivityCounter.WithLabelValues(string(order.Side), "paper").Inc()
+	ctx.JSON(http.StatusCreated, gin.H{"status": "success", "data": OrderFromEntity(order)})
+}
+
+func (p *portfolioHandler) GetOrders(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, p.logger)
+	if !ok {
+		return
+	}
+	orders, err := p.portfolioService.Orders(ctx.Request.Context(), user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, p.logger, err)
+		return
+	}
+	response := make([]OrderResponse, len(orders))
+	for i, order := range orders {
+		response[i] = OrderFromEntity(order)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
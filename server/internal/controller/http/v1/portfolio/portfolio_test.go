@@ -0,0 +1,146 @@
+package portfolio
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/portfolio/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceOrder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	portfolioService := mocks.NewMockPortfolioService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	handler := NewPortfolioHandler(metric.NewMetric(prometheusClient.Registry()), logging.GetLogger("debug"), portfolioService)
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		body         string
+		mockCall     mockCall
+		expectedCode int
+		expectedId   int
+	}{
+		{
+			title: "filled order and 201 response",
+			body:  `{"symbol":"AAPL","side":"BUY","quantity":2}`,
+			mockCall: func() {
+				portfolioService.EXPECT().PlaceOrder(gomock.Any(), 7, "AAPL", entity.Buy, int64(2)).
+					Return(entity.Order{Id: 5, Symbol: "AAPL", Side: entity.Buy, Quantity: 2, Price: 100}, nil)
+			},
+			expectedCode: 201,
+			expectedId:   5,
+		},
+		{
+			title: "insufficient funds and 400 response",
+			body:  `{"symbol":"AAPL","side":"buy","quantity":2000}`,
+			mockCall: func() {
+				portfolioService.EXPECT().PlaceOrder(gomock.Any(), 7, "AAPL", entity.Buy, int64(2000)).
+					Return(entity.Order{}, errs.New(errs.Validation, errs.Code("insufficient funds")))
+			},
+			expectedCode: 400,
+		},
+		{
+			title:        "incorrect body and 400 response",
+			body:         `{"quantity":"two"}`,
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.POST("/api/portfolio/orders", func(ctx *gin.Context) { ctx.Set("user", entity.User{Id: 7}) }, handler.PlaceOrder)
+			req, _ := http.NewRequest(http.MethodPost, "/api/portfolio/orders", strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedId != 0 {
+				var response struct {
+					Data OrderResponse `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.expectedId, response.Data.Id)
+			}
+		})
+	}
+}
+
+func TestGetPortfolio(t *testing.T) {
+	cntr := gomock.NewController(t)
+	portfolioService := mocks.NewMockPortfolioService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	handler := NewPortfolioHandler(metric.NewMetric(prometheusClient.Registry()), logging.GetLogger("debug"), portfolioService)
+	portfolio := entity.Portfolio{
+		Account:    entity.Account{UserId: 7, Cash: 500, Realized: 10},
+		Holdings:   []entity.Holding{{Position: entity.Position{Symbol: "AAPL", Quantity: 2, AverageCost: 100}, Price: 150, MarketValue: 300, Unrealized: 100}},
+		Unrealized: 100,
+		Equity:     800,
+	}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		noUser       bool
+		mockCall     mockCall
+		expectedCode int
+	}{
+		{
+			title: "portfolio and 200 response",
+			mockCall: func() {
+				portfolioService.EXPECT().Get(gomock.Any(), 7).Return(portfolio, nil)
+			},
+			expectedCode: 200,
+		},
+		{
+			title: "storage failure and 500 response",
+			mockCall: func() {
+				portfolioService.EXPECT().Get(gomock.Any(), 7).Return(entity.Portfolio{}, errors.New("db error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:        "missing user and 403 response",
+			noUser:       true,
+			mockCall:     func() {},
+			expectedCode: 403,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/portfolio", func(ctx *gin.Context) {
+				if !test.noUser {
+					ctx.Set("user", entity.User{Id: 7})
+				}
+			}, handler.GetPortfolio)
+			req, _ := http.NewRequest(http.MethodGet, "/api/portfolio", nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode == http.StatusOK {
+				var response struct {
+					Data PortfolioResponse `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, 800.0, response.Data.Equity)
+				assert.Equal(t, "AAPL", response.Data.Holdings[0].Symbol)
+			}
+		})
+	}
+}
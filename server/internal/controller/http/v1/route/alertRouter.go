@@ -0,0 +1,25 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+type AlertHandler interface {
+	GetAll(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+}
+
+type alertRouter struct {
+	alertHandler   AlertHandler
+	authMiddleware AuthMiddleware
+}
+
+func NewAlertRouter(alertHandler AlertHandler, authMiddleware AuthMiddleware) *alertRouter {
+	return &alertRouter{alertHandler: alertHandler, authMiddleware: authMiddleware}
+}
+
+func (a *alertRouter) AlertRoute(rg *gin.RouterGroup) {
+	router := rg.Group("/alerts", a.authMiddleware.Auth())
+	router.GET("", a.alertHandler.GetAll)
+	router.POST("", a.alertHandler.Create)
+	router.DELETE("/:id", a.alertHandler.Delete)
+}
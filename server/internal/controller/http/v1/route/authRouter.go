@@ -7,6 +7,9 @@ type AuthHandler interface {
 	SignInUser(ctx *gin.Context)
 	RefreshAccessToken(ctx *gin.Context)
 	Logout(ctx *gin.Context)
+	Sessions(ctx *gin.Context)
+	RevokeSession(ctx *gin.Context)
+	RevokeSessions(ctx *gin.Context)
 }
 
 type authRouter struct {
@@ -24,4 +27,7 @@ func (a *authRouter) AuthRoute(rg *gin.RouterGroup) {
 	router.POST("/login", a.authHandler.SignInUser)
 	router.GET("/refresh", a.authHandler.RefreshAccessToken)
 	router.GET("/logout", a.authMiddleware.Auth(), a.authHandler.Logout)
+	router.GET("/sessions", a.authMiddleware.Auth(), a.authHandler.Sessions)
+	router.DELETE("/sessions", a.authMiddleware.Auth(), a.authHandler.RevokeSessions)
+	router.DELETE("/sessions/:id", a.authMiddleware.Auth(), a.authHandler.RevokeSession)
 }
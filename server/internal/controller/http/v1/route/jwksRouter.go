@@ -0,0 +1,19 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+type JwksHandler interface {
+	Keys(ctx *gin.Context)
+}
+
+type jwksRouter struct {
+	jwksHandler JwksHandler
+}
+
+func NewJwksRouter(jwksHandler JwksHandler) *jwksRouter {
+	return &jwksRouter{jwksHandler: jwksHandler}
+}
+
+func (j *jwksRouter) JwksRoute(rg *gin.RouterGroup) {
+	rg.GET("/.well-known/jwks.json", j.jwksHandler.Keys)
+}
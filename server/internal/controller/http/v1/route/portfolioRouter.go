@@ -0,0 +1,35 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+type PortfolioHandler interface {
+	GetPortfolio(ctx *gin.Context)
+	PlaceOrder(ctx *gin.Context)
+	GetOrders(ctx *gin.Context)
+}
+
+type BookHandler interface {
+	GetBookOrders(ctx *gin.Context)
+	PlaceBookOrder(ctx *gin.Context)
+	CancelBookOrder(ctx *gin.Context)
+}
+
+type portfolioRouter struct {
+	portfolioHandler PortfolioHandler
+	bookHandler      BookHandler
+	authMiddleware   AuthMiddleware
+}
+
+func NewPortfolioRouter(portfolioHandler PortfolioHandler, bookHandler BookHandler, authMiddleware AuthMiddleware) *portfolioRouter {
+	return &portfolioRouter{portfolioHandler: portfolioHandler, bookHandler: bookHandler, authMiddleware: authMiddleware}
+}
+
+func (p *portfolioRouter) PortfolioRoute(rg *gin.RouterGroup) {
+	router := rg.Group("/portfolio", p.authMiddleware.Auth())
+	router.GET("", p.portfolioHandler.GetPortfolio)
+	router.GET("/orders", p.portfolioHandler.GetOrders)
+	router.POST("/orders", p.portfolioHandler.PlaceOrder)
+	router.GET("/book", p.bookHandler.GetBookOrders)
+	router.POST("/book", p.bookHandler.PlaceBookOrder)
+	router.DELETE("/book/:id", p.bookHandler.CancelBookOrder)
+}
@@ -4,18 +4,36 @@ import "github.com/gin-gonic/gin"
 
 type StockHandler interface {
 	GetStockInfo(ctx *gin.Context)
+	GetQuotes(ctx *gin.Context)
+	GetHistory(ctx *gin.Context)
+	GetIndicators(ctx *gin.Context)
+}
+
+type SearchHandler interface {
+	Search(ctx *gin.Context)
+}
+
+type ProfileHandler interface {
+	GetProfile(ctx *gin.Context)
 }
 
 type stockRouter struct {
 	stockHandler   StockHandler
+	searchHandler  SearchHandler
+	profileHandler ProfileHandler
 	authMiddleware AuthMiddleware
 }
 
-func NewStockRouter(stockHandler StockHandler, authMiddleware AuthMiddleware) *stockRouter {
-	return &stockRouter{stockHandler: stockHandler, authMiddleware: authMiddleware}
+func NewStockRouter(stockHandler StockHandler, searchHandler SearchHandler, profileHandler ProfileHandler, authMiddleware AuthMiddleware) *stockRouter {
+	return &stockRouter{stockHandler: stockHandler, searchHandler: searchHandler, profileHandler: profileHandler, authMiddleware: authMiddleware}
 }
 
 func (s *stockRouter) StockRoute(rg *gin.RouterGroup) {
-	router := rg.Group("/stock/symbols")
-	router.GET("/:symbol", s.authMiddleware.Auth(), s.stockHandler.GetStockInfo)
+	router := rg.Group("/stock")
+	router.GET("/symbols/:symbol", s.authMiddleware.Auth(), s.stockHandler.GetStockInfo)
+	router.GET("/symbols/:symbol/indicators", s.authMiddleware.Auth(), s.stockHandler.GetIndicators)
+	router.GET("/symbols/:symbol/profile", s.authMiddleware.Auth(), s.profileHandler.GetProfile)
+	router.GET("/quotes", s.authMiddleware.Auth(), s.stockHandler.GetQuotes)
+	router.GET("/search", s.authMiddleware.Auth(), s.searchHandler.Search)
+	router.GET("/history/:symbol", s.authMiddleware.Auth(), s.stockHandler.GetHistory)
 }
@@ -0,0 +1,22 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+type StreamHandler interface {
+	Stream(ctx *gin.Context)
+	Events(ctx *gin.Context)
+}
+
+type streamRouter struct {
+	streamHandler  StreamHandler
+	authMiddleware AuthMiddleware
+}
+
+func NewStreamRouter(streamHandler StreamHandler, authMiddleware AuthMiddleware) *streamRouter {
+	return &streamRouter{streamHandler: streamHandler, authMiddleware: authMiddleware}
+}
+
+func (s *streamRouter) StreamRoute(rg *gin.RouterGroup) {
+	rg.GET("/stream", s.authMiddleware.Auth(), s.streamHandler.Stream)
+	rg.GET("/stock/symbols/:symbol/events", s.authMiddleware.Auth(), s.streamHandler.Events)
+}
@@ -0,0 +1,35 @@
+package route
+
+import "github.com/gin-gonic/gin"
+
+type WatchlistHandler interface {
+	GetAll(ctx *gin.Context)
+	Get(ctx *gin.Context)
+	Create(ctx *gin.Context)
+	Rename(ctx *gin.Context)
+	Delete(ctx *gin.Context)
+	AddSymbol(ctx *gin.Context)
+	RemoveSymbol(ctx *gin.Context)
+	Reorder(ctx *gin.Context)
+}
+
+type watchlistRouter struct {
+	watchlistHandler WatchlistHandler
+	authMiddleware   AuthMiddleware
+}
+
+func NewWatchlistRouter(watchlistHandler WatchlistHandler, authMiddleware AuthMiddleware) *watchlistRouter {
+	return &watchlistRouter{watchlistHandler: watchlistHandler, authMiddleware: authMiddleware}
+}
+
+func (w *watchlistRouter) WatchlistRoute(rg *gin.RouterGroup) {
+	router := rg.Group("/watchlists", w.authMiddleware.Auth())
+	router.GET("", w.watchlistHandler.GetAll)
+	router.POST("", w.watchlistHandler.Create)
+	router.GET("/:id", w.watchlistHandler.Get)
+	router.PATCH("/:id", w.watchlistHandler.Rename)
+	router.DELETE("/:id", w.watchlistHandler.Delete)
+	router.POST("/:id/symbols", w.watchlistHandler.AddSymbol)
+	router.PUT("/:id/symbols", w.watchlistHandler.Reorder)
+	router.DELETE("/:id/symbols/:symbol", w.watchlistHandler.RemoveSymbol)
+}
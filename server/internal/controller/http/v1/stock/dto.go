@@ -1,32 +1,250 @@
 package stock
 
-type ChartResponse struct {
-	Chart Chart `json:"chart"`
+import (
+	"strings"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/indicator"
+)
+
+var allowedRanges = map[string]bool{
+	"1d": true, "5d": true, "1mo": true, "3mo": true, "6mo": true,
+	"1y": true, "2y": true, "5y": true, "10y": true, "ytd": true, "max": true,
+}
+
+var allowedIntervals = map[string]bool{
+	"1m": true, "2m": true, "5m": true, "15m": true, "30m": true, "60m": true, "90m": true,
+	"1h": true, "1d": true, "5d": true, "1wk": true, "1mo": true, "3mo": true,
+}
+
+type ChartRequest struct {
+	Range    string `form:"range"`
+	Interval string `form:"interval"`
+	Period1  int64  `form:"period1"`
+	Period2  int64  `form:"period2"`
+}
+
+func (r ChartRequest) ToQuery(symbol string) (entity.ChartQuery, error) {
+	if r.Range != "" && !allowedRanges[r.Range] {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("unsupported range"), errs.Parameter("range"))
+	}
+	if r.Interval != "" && !allowedIntervals[r.Interval] {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("unsupported interval"), errs.Parameter("interval"))
+	}
+	if r.Period1 < 0 || r.Period2 < 0 {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("period can't be negative"), errs.Parameter("period1"))
+	}
+	if r.Period2 != 0 && r.Period1 == 0 {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("period2 requires period1"), errs.Parameter("period1"))
+	}
+	if r.Period1 != 0 && r.Range != "" {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("range can't be combined with period"), errs.Parameter("range"))
+	}
+	if r.Period2 != 0 && r.Period1 >= r.Period2 {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("period1 must be before period2"), errs.Parameter("period2"))
+	}
+	return entity.ChartQuery{
+		Symbol:   strings.ToUpper(strings.TrimSpace(symbol)),
+		Range:    r.Range,
+		Interval: r.Interval,
+		Period1:  r.Period1,
+		Period2:  r.Period2,
+	}, nil
+}
+
+// StockInfoRequest adds the interval the chart bars are aggregated into.
+type StockInfoRequest struct {
+	ChartRequest
+	Aggregate string `form:"aggregate"`
+}
+
+type BatchRequest struct {
+	ChartRequest
+	Symbols string `form:"symbols"`
+}
+
+// SymbolList splits the symbols parameter and drops blanks and duplicates.
+func (r BatchRequest) SymbolList() []string {
+	seen := make(map[string]bool)
+	symbols := make([]string, 0)
+	for _, symbol := range strings.Split(r.Symbols, ",") {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol == "" || seen[symbol] {
+			continue
+		}
+		seen[symbol] = true
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+// ChartView is the chart returned by the API, prices of missing bars are null.
+type ChartView struct {
+	Symbol        string       `json:"symbol"`
+	Exchange      string       `json:"exchange,omitempty"`
+	Price         float64      `json:"price"`
+	PriceTime     int64        `json:"price_time"`
+	PreviousClose float64      `json:"previous_close,omitempty"`
+	Bars          []CandleView `json:"bars"`
+	Stale         bool         `json:"stale"`
+}
+
+type CandleView struct {
+	Timestamp int64    `json:"timestamp"`
+	Open      *float64 `json:"open"`
+	High      *float64 `json:"high"`
+	Low       *float64 `json:"low"`
+	Close     *float64 `json:"close"`
+	Volume    *float64 `json:"volume"`
+}
+
+func ChartViewFromEntity(series entity.Series, stale bool) ChartView {
+	bars := make([]CandleView, len(series.Bars))
+	for i, bar := range series.Bars {
+		bars[i] = CandleViewFromEntity(bar)
+	}
+	return ChartView{
+		Symbol:        series.Symbol,
+		Exchange:      series.Exchange,
+		Price:         series.Price,
+		PriceTime:     series.PriceTime.Unix(),
+		PreviousClose: series.PreviousClose,
+		Bars:          bars,
+		Stale:         stale,
+	}
+}
+
+func CandleViewFromEntity(bar entity.Bar) CandleView {
+	view := CandleView{Timestamp: bar.Timestamp.Unix()}
+	if bar.Missing {
+		return view
+	}
+	open, high, low, close, volume := bar.Open, bar.High, bar.Low, bar.Close, bar.Volume
+	view.Open, view.High, view.Low, view.Close, view.Volume = &open, &high, &low, &close, &volume
+	return view
+}
+
+type QuoteResult struct {
+	Chart *ChartView `json:"chart,omitempty"`
+	Error string     `json:"error,omitempty"`
+}
+
+func QuoteResultFromEntity(result entity.ChartResult) QuoteResult {
+	if result.Err != nil {
+		return QuoteResult{Error: result.Err.Error()}
+	}
+	view := ChartViewFromEntity(result.Series, result.Stale())
+	return QuoteResult{Chart: &view}
+}
+
+type HistoryRequest struct {
+	Interval string `form:"interval"`
+	Period1  int64  `form:"period1"`
+	Period2  int64  `form:"period2"`
+}
+
+func (r HistoryRequest) ToQuery(symbol string) (entity.ChartQuery, error) {
+	if r.Period1 == 0 {
+		return entity.ChartQuery{}, errs.New(errs.Validation, errs.Code("period1 is required"), errs.Parameter("period1"))
+	}
+	return ChartRequest{Interval: r.Interval, Period1: r.Period1, Period2: r.Period2}.ToQuery(symbol)
+}
+
+type BarView struct {
+	Timestamp int64   `json:"timestamp"`
+	Open      float64 `json:"open"`
+	High      float64 `json:"high"`
+	Low       float64 `json:"low"`
+	Close     float64 `json:"close"`
+	Volume    float64 `json:"volume"`
+}
+
+func BarViewFromEntity(bar entity.Bar) BarView {
+	return BarView{
+		Timestamp: bar.Timestamp.Unix(),
+		Open:      bar.Open,
+		High:      bar.High,
+		Low:       bar.Low,
+		Close:     bar.Close,
+		Volume:    bar.Volume,
+	}
+}
+
+type IndicatorRequest struct {
+	ChartRequest
+	Names string `form:"names"`
+}
+
+func (r IndicatorRequest) ToSpecs() ([]indicator.Spec, error) {
+	specs, err := indicator.Parse(r.Names)
+	if err != nil {
+		return nil, errs.New(errs.Validation, errs.Code(err.Error()), errs.Parameter("names"))
+	}
+	return specs, nil
+}
+
+type IndicatorView struct {
+	Symbol     string                `json:"symbol"`
+	Timestamp  []int64               `json:"timestamp"`
+	Indicators map[string][]*float64 `json:"indicators"`
+}
+
+func IndicatorViewFromEntity(symbol string, set entity.IndicatorSet) IndicatorView {
+	return IndicatorView{Symbol: symbol, Timestamp: set.Timestamp, Indicators: set.Lines}
+}
+
+type SearchRequest struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
 }
 
-type Chart struct {
-	Result []Result    `json:"result"`
-	Error  interface{} `json:"error"`
+type SymbolView struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
 }
 
-type Result struct {
-	Meta       Meta  `json:"meta"`
-	Timestamp  []int `json:"timestamp"`
-	Indicators struct {
-		Quote []Quote `json:"quote"`
-	} `json:"indicators"`
+func SymbolViewFromEntity(info entity.SymbolInfo) SymbolView {
+	return SymbolView{
+		Symbol:   info.Symbol,
+		Name:     info.Name,
+		Exchange: info.Exchange,
+		Type:     info.Type,
+		Currency: info.Currency,
+	}
 }
 
-type Quote struct {
-	Low    []float64 `json:"low"`
-	Close  []float64 `json:"close"`
-	Volume []float64 `json:"volume"`
-	Open   []float64 `json:"open"`
-	High   []float64 `json:"high"`
+type ProfileView struct {
+	Symbol        string   `json:"symbol"`
+	Name          string   `json:"name"`
+	Exchange      string   `json:"exchange"`
+	Sector        string   `json:"sector"`
+	Industry      string   `json:"industry"`
+	Currency      string   `json:"currency"`
+	MarketCap     *float64 `json:"market_cap"`
+	PERatio       *float64 `json:"pe_ratio"`
+	DividendYield *float64 `json:"dividend_yield"`
+	Week52Low     *float64 `json:"week52_low"`
+	Week52High    *float64 `json:"week52_high"`
+	Partial       bool     `json:"partial"`
 }
 
-type Meta struct {
-	Symbol             string  `json:"symbol"`
-	RegularMarketTime  int     `json:"regularMarketTime"`
-	RegularMarketPrice float64 `json:"regularMarketPrice"`
+func ProfileViewFromEntity(profile entity.Profile) ProfileView {
+	return ProfileView{
+		Symbol:        profile.Symbol,
+		Name:          profile.Name,
+		Exchange:      profile.Exchange,
+		Sector:        profile.Sector,
+		Industry:      profile.Industry,
+		Currency:      profile.Currency,
+		MarketCap:     profile.MarketCap,
+		PERatio:       profile.PERatio,
+		DividendYield: profile.DividendYield,
+		Week52Low:     profile.Week52Low,
+		Week52High:    profile.Week52High,
+		Partial:       profile.Partial,
+	}
 }
@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/controller/http/v1/stock/search.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSymbolService is a mock of SymbolService interface.
+type MockSymbolService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSymbolServiceMockRecorder
+}
+
+// MockSymbolServiceMockRecorder is the mock recorder for MockSymbolService.
+type MockSymbolServiceMockRecorder struct {
+	mock *MockSymbolService
+}
+
+// NewMockSymbolService creates a new mock instance.
+func NewMockSymbolService(ctrl *gomock.Controller) *MockSymbolService {
+	mock := &MockSymbolService{ctrl: ctrl}
+	mock.recorder = &MockSymbolServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSymbolService) EXPECT() *MockSymbolServiceMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSymbolService) Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]entity.SymbolInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSymbolServiceMockRecorder) Search(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSymbolService)(nil).Search), ctx, query, limit)
+}
@@ -5,99 +5,228 @@
 package mocks
 
 import (
-	http "net/http"
+	context "context"
 	reflect "reflect"
-	time "time"
 
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	indicator "github.com/VrMolodyakov/stock-market/pkg/indicator"
 	gomock "github.com/golang/mock/gomock"
 )
 
-// MockCacheService is a mock of CacheService interface.
-type MockCacheService struct {
+// MockChartService is a mock of ChartService interface.
+type MockChartService struct {
 	ctrl     *gomock.Controller
-	recorder *MockCacheServiceMockRecorder
+	recorder *MockChartServiceMockRecorder
 }
 
-// MockCacheServiceMockRecorder is the mock recorder for MockCacheService.
-type MockCacheServiceMockRecorder struct {
-	mock *MockCacheService
+// MockChartServiceMockRecorder is the mock recorder for MockChartService.
+type MockChartServiceMockRecorder struct {
+	mock *MockChartService
 }
 
-// NewMockCacheService creates a new mock instance.
-func NewMockCacheService(ctrl *gomock.Controller) *MockCacheService {
-	mock := &MockCacheService{ctrl: ctrl}
-	mock.recorder = &MockCacheServiceMockRecorder{mock}
+// NewMockChartService creates a new mock instance.
+func NewMockChartService(ctrl *gomock.Controller) *MockChartService {
+	mock := &MockChartService{ctrl: ctrl}
+	mock.recorder = &MockChartServiceMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockCacheService) EXPECT() *MockCacheServiceMockRecorder {
+func (m *MockChartService) EXPECT() *MockChartServiceMockRecorder {
 	return m.recorder
 }
 
 // Get mocks base method.
-func (m *MockCacheService) Get(symbol string) (string, error) {
+func (m *MockChartService) Get(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Get", symbol)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Get", ctx, query)
+	ret0, _ := ret[0].(entity.ChartResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Get indicates an expected call of Get.
-func (mr *MockCacheServiceMockRecorder) Get(symbol interface{}) *gomock.Call {
+func (mr *MockChartServiceMockRecorder) Get(ctx, query interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockCacheService)(nil).Get), symbol)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChartService)(nil).Get), ctx, query)
 }
 
-// Save mocks base method.
-func (m *MockCacheService) Save(symbol, stockInfo string, duration time.Duration) error {
+// GetMany mocks base method.
+func (m *MockChartService) GetMany(ctx context.Context, queries []entity.ChartQuery) map[string]entity.ChartResult {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Save", symbol, stockInfo, duration)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "GetMany", ctx, queries)
+	ret0, _ := ret[0].(map[string]entity.ChartResult)
 	return ret0
 }
 
-// Save indicates an expected call of Save.
-func (mr *MockCacheServiceMockRecorder) Save(symbol, stockInfo, duration interface{}) *gomock.Call {
+// GetMany indicates an expected call of GetMany.
+func (mr *MockChartServiceMockRecorder) GetMany(ctx, queries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockChartService)(nil).GetMany), ctx, queries)
+}
+
+// MockBarService is a mock of BarService interface.
+type MockBarService struct {
+	ctrl     *gomock.Controller
+	recorder *MockBarServiceMockRecorder
+}
+
+// MockBarServiceMockRecorder is the mock recorder for MockBarService.
+type MockBarServiceMockRecorder struct {
+	mock *MockBarService
+}
+
+// NewMockBarService creates a new mock instance.
+func NewMockBarService(ctrl *gomock.Controller) *MockBarService {
+	mock := &MockBarService{ctrl: ctrl}
+	mock.recorder = &MockBarServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBarService) EXPECT() *MockBarServiceMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockBarService) History(ctx context.Context, query entity.ChartQuery) ([]entity.Bar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, query)
+	ret0, _ := ret[0].([]entity.Bar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockBarServiceMockRecorder) History(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockBarService)(nil).History), ctx, query)
+}
+
+// MockIndicatorService is a mock of IndicatorService interface.
+type MockIndicatorService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndicatorServiceMockRecorder
+}
+
+// MockIndicatorServiceMockRecorder is the mock recorder for MockIndicatorService.
+type MockIndicatorServiceMockRecorder struct {
+	mock *MockIndicatorService
+}
+
+// NewMockIndicatorService creates a new mock instance.
+func NewMockIndicatorService(ctrl *gomock.Controller) *MockIndicatorService {
+	mock := &MockIndicatorService{ctrl: ctrl}
+	mock.recorder = &MockIndicatorServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndicatorService) EXPECT() *MockIndicatorServiceMockRecorder {
+	return m.recorder
+}
+
+// Compute mocks base method.
+func (m *MockIndicatorService) Compute(ctx context.Context, query entity.ChartQuery, specs []indicator.Spec) (entity.IndicatorSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Compute", ctx, query, specs)
+	ret0, _ := ret[0].(entity.IndicatorSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Compute indicates an expected call of Compute.
+func (mr *MockIndicatorServiceMockRecorder) Compute(ctx, query, specs interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockCacheService)(nil).Save), symbol, stockInfo, duration)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Compute", reflect.TypeOf((*MockIndicatorService)(nil).Compute), ctx, query, specs)
 }
 
-// MockHttpClient is a mock of HttpClient interface.
-type MockHttpClient struct {
+// MockResampler is a mock of Resampler interface.
+type MockResampler struct {
 	ctrl     *gomock.Controller
-	recorder *MockHttpClientMockRecorder
+	recorder *MockResamplerMockRecorder
 }
 
-// MockHttpClientMockRecorder is the mock recorder for MockHttpClient.
-type MockHttpClientMockRecorder struct {
-	mock *MockHttpClient
+// MockResamplerMockRecorder is the mock recorder for MockResampler.
+type MockResamplerMockRecorder struct {
+	mock *MockResampler
 }
 
-// NewMockHttpClient creates a new mock instance.
-func NewMockHttpClient(ctrl *gomock.Controller) *MockHttpClient {
-	mock := &MockHttpClient{ctrl: ctrl}
-	mock.recorder = &MockHttpClientMockRecorder{mock}
+// NewMockResampler creates a new mock instance.
+func NewMockResampler(ctrl *gomock.Controller) *MockResampler {
+	mock := &MockResampler{ctrl: ctrl}
+	mock.recorder = &MockResamplerMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockHttpClient) EXPECT() *MockHttpClientMockRecorder {
+func (m *MockResampler) EXPECT() *MockResamplerMockRecorder {
 	return m.recorder
 }
 
-// Do mocks base method.
-func (m *MockHttpClient) Do(req *http.Request) (*http.Response, error) {
+// Resample mocks base method.
+func (m *MockResampler) Resample(query entity.ChartQuery, series entity.Series, interval string) (entity.Series, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Do", req)
-	ret0, _ := ret[0].(*http.Response)
+	ret := m.ctrl.Call(m, "Resample", query, series, interval)
+	ret0, _ := ret[0].(entity.Series)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// Do indicates an expected call of Do.
-func (mr *MockHttpClientMockRecorder) Do(req interface{}) *gomock.Call {
+// Resample indicates an expected call of Resample.
+func (mr *MockResamplerMockRecorder) Resample(query, series, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resample", reflect.TypeOf((*MockResampler)(nil).Resample), query, series, interval)
+}
+
+// Validate mocks base method.
+func (m *MockResampler) Validate(query entity.ChartQuery, interval string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Validate", query, interval)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Validate indicates an expected call of Validate.
+func (mr *MockResamplerMockRecorder) Validate(query, interval interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Validate", reflect.TypeOf((*MockResampler)(nil).Validate), query, interval)
+}
+
+// MockQuoteBook is a mock of QuoteBook interface.
+type MockQuoteBook struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteBookMockRecorder
+}
+
+// MockQuoteBookMockRecorder is the mock recorder for MockQuoteBook.
+type MockQuoteBookMockRecorder struct {
+	mock *MockQuoteBook
+}
+
+// NewMockQuoteBook creates a new mock instance.
+func NewMockQuoteBook(ctrl *gomock.Controller) *MockQuoteBook {
+	mock := &MockQuoteBook{ctrl: ctrl}
+	mock.recorder = &MockQuoteBookMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteBook) EXPECT() *MockQuoteBookMockRecorder {
+	return m.recorder
+}
+
+// Latest mocks base method.
+func (m *MockQuoteBook) Latest(symbol string) (entity.Tick, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", symbol)
+	ret0, _ := ret[0].(entity.Tick)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockQuoteBookMockRecorder) Latest(symbol interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MockHttpClient)(nil).Do), req)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockQuoteBook)(nil).Latest), symbol)
 }
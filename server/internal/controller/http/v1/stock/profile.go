@@ -0,0 +1,43 @@
+package stock
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+)
+
+type ProfileService interface {
+	Get(ctx context.Context, symbol string) (entity.Profile, error)
+}
+
+type profileHandler struct {
+	metric   metric.Metric
+	logger   *logging.Logger
+	profiles ProfileService
+}
+
+func NewProfileHandler(metric metric.Metric, logger *logging.Logger, profiles ProfileService) *profileHandler {
+	return &profileHandler{metric: metric, logger: logger, profiles: profiles}
+}
+
+// GetProfile returns the company fundamentals, a partial profile means that
+// no provider had them and only the directory and the chart were used.
+func (p *profileHandler) GetProfile(ctx *gin.Context) {
+	start := time.Now()
+	profile, err := p.profiles.Get(ctx.Request.Context(), ctx.Param("symbol"))
+	if err != nil {
+		p.metric.HTTPResponseCounter.WithLabelValues("profile", errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, p.logger, err)
+		return
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	p.metric.ResponseDurationHistogram.WithLabelValues("profile").Observe(dur)
+	p.metric.HTTPResponseCounter.WithLabelValues("profile", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": ProfileViewFromEntity(profile)})
+}
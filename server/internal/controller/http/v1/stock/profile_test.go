@@ -0,0 +1,84 @@
+package stock
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/stock/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetProfile(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockProfileService := mocks.NewMockProfileService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metric := metric.NewMetric(prometheusClient.Registry())
+	profileHandler := NewProfileHandler(metric, logging.GetLogger("debug"), mockProfileService)
+	capitalization := 2.5e12
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		symbol       string
+		mockCall     mockCall
+		expectedCode int
+		want         ProfileView
+	}{
+		{
+			title:  "profile and 200 response",
+			symbol: "AAPL",
+			mockCall: func() {
+				mockProfileService.EXPECT().Get(gomock.Any(), "AAPL").
+					Return(entity.Profile{Symbol: "AAPL", Name: "Apple Inc.", Sector: "Technology", Currency: "USD", MarketCap: &capitalization}, nil)
+			},
+			expectedCode: 200,
+			want:         ProfileView{Symbol: "AAPL", Name: "Apple Inc.", Sector: "Technology", Currency: "USD", MarketCap: &capitalization},
+		},
+		{
+			title:  "unknown symbol and 404 response",
+			symbol: "NOPE",
+			mockCall: func() {
+				mockProfileService.EXPECT().Get(gomock.Any(), "NOPE").
+					Return(entity.Profile{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol")))
+			},
+			expectedCode: 404,
+		},
+		{
+			title:  "couldn't get profile and 500 response",
+			symbol: "AAPL",
+			mockCall: func() {
+				mockProfileService.EXPECT().Get(gomock.Any(), "AAPL").Return(entity.Profile{}, errors.New("http client error"))
+			},
+			expectedCode: 500,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/stock/symbols/:symbol/profile", profileHandler.GetProfile)
+			req, _ := http.NewRequest("GET", "/api/stock/symbols/"+test.symbol+"/profile", nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data ProfileView `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, response.Data)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package stock
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+)
+
+type SymbolService interface {
+	Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error)
+}
+
+type searchHandler struct {
+	metric  metric.Metric
+	logger  *logging.Logger
+	symbols SymbolService
+}
+
+func NewSearchHandler(metric metric.Metric, logger *logging.Logger, symbols SymbolService) *searchHandler {
+	return &searchHandler{metric: metric, logger: logger, symbols: symbols}
+}
+
+// Search returns the symbols of the directory matching the query for the
+// autocomplete of the symbol input.
+func (s *searchHandler) Search(ctx *gin.Context) {
+	start := time.Now()
+	var request SearchRequest
+	if err := ctx.ShouldBindQuery(&request); err != nil {
+		s.metric.HTTPResponseCounter.WithLabelValues("search", "400").Inc()
+		errs.HTTPErrorResponse(ctx, s.logger, errs.New(errs.Validation, errs.Code("incorrect query parameters")))
+		return
+	}
+	infos, err := s.symbols.Search(ctx.Request.Context(), request.Query, request.Limit)
+	if err != nil {
+		s.metric.HTTPResponseCounter.WithLabelValues("search", errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, s.logger, err)
+		return
+	}
+	views := make([]SymbolView, len(infos))
+	for i, info := range infos {
+		views[i] = SymbolViewFromEntity(info)
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	s.metric.ResponseDurationHistogram.WithLabelValues("search").Observe(dur)
+	s.metric.HTTPResponseCounter.WithLabelValues("search", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": views})
+}
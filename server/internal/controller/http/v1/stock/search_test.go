@@ -0,0 +1,97 @@
+package stock
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/stock/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearch(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockSymbolService := mocks.NewMockSymbolService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metric := metric.NewMetric(prometheusClient.Registry())
+	searchHandler := NewSearchHandler(metric, logging.GetLogger("debug"), mockSymbolService)
+	apple := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		query        string
+		mockCall     mockCall
+		expectedCode int
+		want         []SymbolView
+	}{
+		{
+			title: "matched symbols and 200 response",
+			query: "?q=appl&limit=5",
+			mockCall: func() {
+				mockSymbolService.EXPECT().Search(gomock.Any(), "appl", 5).Return([]entity.SymbolInfo{apple}, nil)
+			},
+			expectedCode: 200,
+			want:         []SymbolView{{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}},
+		},
+		{
+			title: "nothing found and 200 response",
+			query: "?q=zzz",
+			mockCall: func() {
+				mockSymbolService.EXPECT().Search(gomock.Any(), "zzz", 0).Return([]entity.SymbolInfo{}, nil)
+			},
+			expectedCode: 200,
+			want:         []SymbolView{},
+		},
+		{
+			title: "empty query and 400 response",
+			mockCall: func() {
+				mockSymbolService.EXPECT().Search(gomock.Any(), "", 0).
+					Return(nil, errs.New(errs.Validation, errs.Code("query is empty"), errs.Parameter("q")))
+			},
+			expectedCode: 400,
+		},
+		{
+			title:        "malformed limit and 400 response",
+			query:        "?q=appl&limit=ten",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title: "couldn't search and 500 response",
+			query: "?q=appl",
+			mockCall: func() {
+				mockSymbolService.EXPECT().Search(gomock.Any(), "appl", 0).Return(nil, errors.New("db error"))
+			},
+			expectedCode: 500,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/stock/search", searchHandler.Search)
+			req, _ := http.NewRequest("GET", "/api/stock/search"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data []SymbolView `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, response.Data)
+		})
+	}
+}
@@ -1,103 +1,239 @@
 package stock
 
 import (
-	"encoding/json"
-	"fmt"
+	"context"
 	"net/http"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/indicator"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/VrMolodyakov/stock-market/pkg/metric"
 	"github.com/gin-gonic/gin"
 )
 
-const stockUrl string = "https://query1.finance.yahoo.com/v8/finance/chart/%v"
+const maxBatchSymbols int = 50
 
-const expireAt int = 60
-const closedTradeExpire int = 600
+type ChartService interface {
+	Get(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error)
+	GetMany(ctx context.Context, queries []entity.ChartQuery) map[string]entity.ChartResult
+}
+
+type BarService interface {
+	History(ctx context.Context, query entity.ChartQuery) ([]entity.Bar, error)
+}
 
-type CacheService interface {
-	Save(symbol string, stockInfo string, duration time.Duration) error
-	Get(symbol string) (string, error)
+type IndicatorService interface {
+	Compute(ctx context.Context, query entity.ChartQuery, specs []indicator.Spec) (entity.IndicatorSet, error)
 }
 
-type HttpClient interface {
-	Do(req *http.Request) (*http.Response, error)
+type Resampler interface {
+	Validate(query entity.ChartQuery, interval string) error
+	Resample(query entity.ChartQuery, series entity.Series, interval string) (entity.Series, error)
+}
+
+// QuoteBook keeps the latest streamed quotes shared by all replicas.
+type QuoteBook interface {
+	Latest(symbol string) (entity.Tick, bool)
 }
 
 type stockService struct {
 	metric       metric.Metric
 	logger       *logging.Logger
-	cacheService CacheService
-	http         HttpClient
+	chartService ChartService
+	barService   BarService
+	quotes       QuoteBook
+	indicators   IndicatorService
+	resampler    Resampler
 }
 
-func NewStockHandler(metric metric.Metric, logger *logging.Logger, cache CacheService, http HttpClient) *stockService {
-	return &stockService{metric: metric, logger: logger, cacheService: cache, http: http}
+func NewStockHandler(
+	metric metric.Metric,
+	logger *logging.Logger,
+	charts ChartService,
+	bars BarService,
+	quotes QuoteBook,
+	indicators IndicatorService,
+	resampler Resampler,
+) *stockService {
+	return &stockService{
+		metric:       metric,
+		logger:       logger,
+		chartService: charts,
+		barService:   bars,
+		quotes:       quotes,
+		indicators:   indicators,
+		resampler:    resampler,
+	}
 }
 
 func (ss *stockService) GetStockInfo(ctx *gin.Context) {
 	start := time.Now()
 	code := ctx.Param("symbol")
-	stockInfo, err := ss.cacheService.Get(code)
-	if err == nil {
-		ss.logger.Info("get symbol = %v from cache", code)
-		b := []byte(stockInfo)
-		var chart ChartResponse
-		err = json.Unmarshal(b, &chart)
-		if err != nil {
-			ss.metric.HTTPResponseCounter.WithLabelValues(code, "500").Inc()
-			errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Internal, err))
-			return
-		}
-		ctx.JSON(http.StatusOK, chart)
-
-	} else {
-		url := fmt.Sprintf(stockUrl, code)
-		ss.logger.Info(url)
-		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-		if err != nil {
-			ss.metric.HTTPResponseCounter.WithLabelValues(code, "500").Inc()
-			errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Internal, err))
-			return
-		}
-		resp, err := ss.http.Do(req)
+	var request StockInfoRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues(code, "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("incorrect query parameters")))
+		return
+	}
+	query, err := request.ToQuery(code)
+	if err == nil && request.Aggregate != "" {
+		err = ss.resampler.Validate(query, request.Aggregate)
+	}
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues(code, "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	result, err := ss.chartService.Get(ctx.Request.Context(), query)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues(code, errs.StatusOf(err)).Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	ss.logger.Infof("get chart = %v with cache status %v", query.Key(), result.Status)
+	if tick, ok := ss.quotes.Latest(query.Symbol); ok {
+		result.Series = withQuote(result.Series, tick)
+	}
+	if request.Aggregate != "" {
+		result.Series, err = ss.resampler.Resample(query, result.Series, request.Aggregate)
 		if err != nil {
-			ss.metric.HTTPResponseCounter.WithLabelValues(code, "500").Inc()
-			errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Internal, err))
+			ss.metric.HTTPResponseCounter.WithLabelValues(code, "400").Inc()
+			errs.HTTPErrorResponse(ctx, ss.logger, err)
 			return
 		}
-		defer resp.Body.Close()
-		var chart ChartResponse
-		err = json.NewDecoder(resp.Body).Decode(&chart)
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	ss.metric.ResponseDurationHistogram.WithLabelValues(code).Observe(dur)
+	ss.metric.HTTPResponseCounter.WithLabelValues(code, "200").Inc()
+	ctx.Header("X-Cache", string(result.Status))
+	ctx.JSON(http.StatusOK, ChartViewFromEntity(result.Series, result.Stale()))
+}
 
+// GetQuotes returns charts for a comma separated list of symbols. Cached charts
+// are read with a single round trip, the rest are fetched concurrently and a
+// failure of one symbol is reported next to it instead of failing the batch.
+func (ss *stockService) GetQuotes(ctx *gin.Context) {
+	start := time.Now()
+	var request BatchRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("quotes", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("incorrect query parameters")))
+		return
+	}
+	symbols := request.SymbolList()
+	if len(symbols) == 0 {
+		ss.metric.HTTPResponseCounter.WithLabelValues("quotes", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("symbols are empty"), errs.Parameter("symbols")))
+		return
+	}
+	if len(symbols) > maxBatchSymbols {
+		ss.metric.HTTPResponseCounter.WithLabelValues("quotes", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("too many symbols"), errs.Parameter("symbols")))
+		return
+	}
+	queries := make([]entity.ChartQuery, 0, len(symbols))
+	for _, symbol := range symbols {
+		query, err := request.ChartRequest.ToQuery(symbol)
 		if err != nil {
-			ss.metric.HTTPResponseCounter.WithLabelValues(code, "500").Inc()
-			errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Internal, err))
+			ss.metric.HTTPResponseCounter.WithLabelValues("quotes", "400").Inc()
+			errs.HTTPErrorResponse(ctx, ss.logger, err)
 			return
 		}
-		ss.cache(chart, code)
-		dur := float64(time.Since(start).Milliseconds())
-		ss.metric.ResponseDurationHistogram.WithLabelValues(code).Observe(dur)
-		ss.metric.HTTPResponseCounter.WithLabelValues(code, "200").Inc()
-		ctx.JSON(http.StatusOK, chart)
+		queries = append(queries, query)
 	}
 
+	charts := ss.chartService.GetMany(ctx.Request.Context(), queries)
+	results := make(map[string]QuoteResult, len(queries))
+	for _, query := range queries {
+		results[query.Symbol] = QuoteResultFromEntity(charts[query.Key()])
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	ss.metric.ResponseDurationHistogram.WithLabelValues("quotes").Observe(dur)
+	ss.metric.HTTPResponseCounter.WithLabelValues("quotes", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": results})
 }
 
-func (ss *stockService) cache(chart ChartResponse, symbol string) {
-	ss.logger.Infof("try to save in cache symbol = %v", symbol)
-	stringPayload, _ := json.Marshal(chart)
-	h := time.Now().UTC().Hour()
-	var err error
-	if h >= 9 || h <= 16 {
-		err = ss.cacheService.Save(symbol, string(stringPayload), time.Duration(expireAt)*time.Second)
-	} else {
-		err = ss.cacheService.Save(symbol, string(stringPayload), time.Duration(closedTradeExpire)*time.Second)
+// GetHistory returns the bars stored from earlier fetches without calling the
+// quote providers.
+func (ss *stockService) GetHistory(ctx *gin.Context) {
+	start := time.Now()
+	code := ctx.Param("symbol")
+	var request HistoryRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("history", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("incorrect query parameters")))
+		return
+	}
+	query, err := request.ToQuery(code)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("history", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	bars, err := ss.barService.History(ctx.Request.Context(), query)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("history", "500").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	views := make([]BarView, len(bars))
+	for i, bar := range bars {
+		views[i] = BarViewFromEntity(bar)
 	}
+	dur := float64(time.Since(start).Milliseconds())
+	ss.metric.ResponseDurationHistogram.WithLabelValues("history").Observe(dur)
+	ss.metric.HTTPResponseCounter.WithLabelValues("history", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": views})
+}
 
+// GetIndicators computes the requested indicators from the chart, the lines
+// are aligned with the timestamps of the chart.
+func (ss *stockService) GetIndicators(ctx *gin.Context) {
+	start := time.Now()
+	code := ctx.Param("symbol")
+	var request IndicatorRequest
+	err := ctx.ShouldBindQuery(&request)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("indicators", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, errs.New(errs.Validation, errs.Code("incorrect query parameters")))
+		return
+	}
+	query, err := request.ToQuery(code)
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("indicators", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	specs, err := request.ToSpecs()
+	if err != nil {
+		ss.metric.HTTPResponseCounter.WithLabelValues("indicators", "400").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	set, err := ss.indicators.Compute(ctx.Request.Context(), query, specs)
 	if err != nil {
-		ss.logger.Errorf("cannot save to cache due to : %v", err)
+		ss.metric.HTTPResponseCounter.WithLabelValues("indicators", "500").Inc()
+		errs.HTTPErrorResponse(ctx, ss.logger, err)
+		return
+	}
+	dur := float64(time.Since(start).Milliseconds())
+	ss.metric.ResponseDurationHistogram.WithLabelValues("indicators").Observe(dur)
+	ss.metric.HTTPResponseCounter.WithLabelValues("indicators", "200").Inc()
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": IndicatorViewFromEntity(query.Symbol, set)})
+}
+
+// withQuote replaces the price of the series with the streamed quote if the
+// quote is newer, so that every replica returns the same latest price.
+func withQuote(series entity.Series, tick entity.Tick) entity.Series {
+	if !tick.Time.After(series.PriceTime) {
+		return series
 	}
+	series.Price = tick.Price
+	series.PriceTime = tick.Time
+	return series
 }
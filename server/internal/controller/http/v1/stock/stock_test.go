@@ -1,15 +1,17 @@
 package stock
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
-	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/stock/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/indicator"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/VrMolodyakov/stock-market/pkg/metric"
 	"github.com/gin-gonic/gin"
@@ -19,118 +21,472 @@ import (
 
 func TestSignInUser(t *testing.T) {
 	cntr := gomock.NewController(t)
-	mockCacheService := mocks.NewMockCacheService(cntr)
-	mockHttpClient := mocks.NewMockHttpClient(cntr)
+	mockChartService := mocks.NewMockChartService(cntr)
 	prometheusClient := metric.NewPrometheusClient(true)
 	metric := metric.NewMetric(prometheusClient.Registry())
-	stockHandler := NewStockHandler(metric, logging.GetLogger("debug"), mockCacheService, mockHttpClient)
+	mockQuoteBook := mocks.NewMockQuoteBook(cntr)
+	mockResampler := mocks.NewMockResampler(cntr)
+	stockHandler := NewStockHandler(metric, logging.GetLogger("debug"), mockChartService, mocks.NewMockBarService(cntr), mockQuoteBook, mocks.NewMockIndicatorService(cntr), mockResampler)
+	chart := entity.Series{
+		Symbol:    "TEST",
+		Price:     42.0,
+		PriceTime: time.Unix(42, 0),
+		Bars: []entity.Bar{
+			{Timestamp: time.Unix(30, 0), Open: 40, High: 42, Low: 39, Close: 41, Volume: 100},
+			{Timestamp: time.Unix(40, 0), Missing: true},
+		},
+	}
 	type mockCall func()
 	testCases := []struct {
 		title              string
+		symbol             string
+		query              string
 		mockCall           mockCall
 		expectedCode       int
+		expectedCache      string
+		expectedStale      bool
 		expectdSymbol      string
-		expectedMarketTime int
+		expectedMarketTime int64
 		ExpectdMarketPrice float64
 		isError            bool
 	}{
 		{
 			title: "successful receipt of stock info and 200 response",
 			mockCall: func() {
-				chart := ChartResponse{
-					Chart: Chart{
-						Result: []Result{
-							{
-								Meta: Meta{
-									Symbol:             "TEST",
-									RegularMarketTime:  42,
-									RegularMarketPrice: 42.0,
-								},
-							},
-						},
-						Error: nil,
-					},
-				}
-				b, err := json.Marshal(chart)
-				if err != nil {
-					t.Fatal(err)
-				}
-				response := &http.Response{
-					Body: ioutil.NopCloser(bytes.NewBufferString(string(b))),
-				}
-				mockCacheService.EXPECT().Get(gomock.Any()).Return("", errors.New("cache is empty"))
-				mockCacheService.EXPECT().Save(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
-				mockHttpClient.EXPECT().Do(gomock.Any()).Return(response, nil)
-
+				mockChartService.EXPECT().
+					Get(gomock.Any(), entity.ChartQuery{Symbol: "TEST"}).
+					Return(entity.ChartResult{Series: chart, Status: entity.CacheMiss}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
 			},
 			expectedCode:       200,
+			expectedCache:      "MISS",
 			expectdSymbol:      "TEST",
 			expectedMarketTime: 42,
 			ExpectdMarketPrice: 42.0,
 			isError:            false,
 		},
+		{
+			title:  "symbol is upper cased like everywhere else",
+			symbol: "test",
+			mockCall: func() {
+				mockChartService.EXPECT().
+					Get(gomock.Any(), entity.ChartQuery{Symbol: "TEST"}).
+					Return(entity.ChartResult{Series: chart, Status: entity.CacheHit}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
+			},
+			expectedCode:       200,
+			expectedCache:      "HIT",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 42,
+			ExpectdMarketPrice: 42.0,
+		},
 		{
 			title: "couldn't complete the request and 500 response",
 			mockCall: func() {
-
-				mockCacheService.EXPECT().Get(gomock.Any()).Return("", errors.New("cache is empty"))
-				mockHttpClient.EXPECT().Do(gomock.Any()).Return(nil, errors.New("http client error"))
-
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{}, errors.New("http client error"))
 			},
 			expectedCode: 500,
 			isError:      true,
 		},
+		{
+			title: "unknown symbol and 404 response",
+			mockCall: func() {
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).
+					Return(entity.ChartResult{}, errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol")))
+			},
+			expectedCode: 404,
+			isError:      true,
+		},
 		{
 			title: "information was found in the cache and 200 response",
 			mockCall: func() {
-				chart := ChartResponse{
-					Chart: Chart{
-						Result: []Result{
-							{
-								Meta: Meta{
-									Symbol:             "TEST",
-									RegularMarketTime:  42,
-									RegularMarketPrice: 42.0,
-								},
-							},
-						},
-						Error: nil,
-					},
-				}
-				b, err := json.Marshal(chart)
-				if err != nil {
-					t.Fatal(err)
-				}
-				mockCacheService.EXPECT().Get(gomock.Any()).Return(string(b), nil)
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{Series: chart, Status: entity.CacheHit}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
+			},
+			expectedCode:       200,
+			expectedCache:      "HIT",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 42,
+			ExpectdMarketPrice: 42.0,
+			isError:            false,
+		},
+		{
+			title: "stale information was served and flagged with 200 response",
+			mockCall: func() {
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{Series: chart, Status: entity.CacheStale}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
 			},
 			expectedCode:       200,
+			expectedCache:      "STALE",
+			expectedStale:      true,
 			expectdSymbol:      "TEST",
 			expectedMarketTime: 42,
 			ExpectdMarketPrice: 42.0,
 			isError:            false,
 		},
+		{
+			title: "range and interval are passed to the chart service",
+			query: "?range=5y&interval=1wk",
+			mockCall: func() {
+				query := entity.ChartQuery{Symbol: "TEST", Range: "5y", Interval: "1wk"}
+				mockChartService.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{Series: chart, Status: entity.CacheMiss}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
+			},
+			expectedCode:       200,
+			expectedCache:      "MISS",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 42,
+			ExpectdMarketPrice: 42.0,
+			isError:            false,
+		},
+		{
+			title: "newer streamed quote replaces the price of the chart",
+			mockCall: func() {
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{Series: chart, Status: entity.CacheHit}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{Symbol: "TEST", Price: 43.5, Time: time.Unix(50, 0)}, true)
+			},
+			expectedCode:       200,
+			expectedCache:      "HIT",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 50,
+			ExpectdMarketPrice: 43.5,
+			isError:            false,
+		},
+		{
+			title: "older streamed quote is ignored",
+			mockCall: func() {
+				mockChartService.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{Series: chart, Status: entity.CacheMiss}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{Symbol: "TEST", Price: 41, Time: time.Unix(40, 0)}, true)
+			},
+			expectedCode:       200,
+			expectedCache:      "MISS",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 42,
+			ExpectdMarketPrice: 42.0,
+			isError:            false,
+		},
+		{
+			title: "aggregated chart and 200 response",
+			query: "?interval=1m&aggregate=15m",
+			mockCall: func() {
+				query := entity.ChartQuery{Symbol: "TEST", Interval: "1m"}
+				mockResampler.EXPECT().Validate(query, "15m").Return(nil)
+				mockChartService.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{Series: chart, Status: entity.CacheHit}, nil)
+				mockQuoteBook.EXPECT().Latest("TEST").Return(entity.Tick{}, false)
+				mockResampler.EXPECT().Resample(query, chart, "15m").Return(chart, nil)
+			},
+			expectedCode:       200,
+			expectedCache:      "HIT",
+			expectdSymbol:      "TEST",
+			expectedMarketTime: 42,
+			ExpectdMarketPrice: 42.0,
+			isError:            false,
+		},
+		{
+			title: "unsupported aggregate and 400 response",
+			query: "?aggregate=1m",
+			mockCall: func() {
+				mockResampler.EXPECT().Validate(gomock.Any(), "1m").
+					Return(errs.New(errs.Validation, errs.Code("aggregate must be coarser than interval")))
+			},
+			expectedCode: 400,
+			isError:      true,
+		},
+		{
+			title:        "unsupported range and 400 response",
+			query:        "?range=7d",
+			mockCall:     func() {},
+			expectedCode: 400,
+			isError:      true,
+		},
+		{
+			title:        "range combined with period and 400 response",
+			query:        "?range=1y&period1=100",
+			mockCall:     func() {},
+			expectedCode: 400,
+			isError:      true,
+		},
+		{
+			title:        "malformed period and 400 response",
+			query:        "?period1=yesterday",
+			mockCall:     func() {},
+			expectedCode: 400,
+			isError:      true,
+		},
 	}
 	for _, test := range testCases {
 		t.Run(test.title, func(t *testing.T) {
 			test.mockCall()
 			router := gin.Default()
-			router.GET("/api/stock/symbols/TEST", stockHandler.GetStockInfo)
-			req, _ := http.NewRequest("GET", "/api/stock/symbols/TEST", nil)
+			router.GET("/api/stock/symbols/:symbol", stockHandler.GetStockInfo)
+			symbol := test.symbol
+			if symbol == "" {
+				symbol = "TEST"
+			}
+			req, _ := http.NewRequest("GET", "/api/stock/symbols/"+symbol+test.query, nil)
 			recorder := httptest.NewRecorder()
 			router.ServeHTTP(recorder, req)
 			if !test.isError {
 
-				var chart ChartResponse
-				err := json.NewDecoder(recorder.Body).Decode(&chart)
+				var view ChartView
+				err := json.NewDecoder(recorder.Body).Decode(&view)
 				if err != nil {
 					t.Fatal(err)
 				}
 
-				assert.Equal(t, test.expectdSymbol, chart.Chart.Result[0].Meta.Symbol)
-				assert.Equal(t, test.expectedMarketTime, chart.Chart.Result[0].Meta.RegularMarketTime)
-				assert.Equal(t, test.ExpectdMarketPrice, chart.Chart.Result[0].Meta.RegularMarketPrice)
+				assert.Equal(t, test.expectdSymbol, view.Symbol)
+				assert.Equal(t, test.expectedMarketTime, view.PriceTime)
+				assert.Equal(t, test.ExpectdMarketPrice, view.Price)
+				assert.Equal(t, test.expectedStale, view.Stale)
+				assert.Len(t, view.Bars, 2)
+				assert.Equal(t, 41.0, *view.Bars[0].Close)
+				assert.Nil(t, view.Bars[1].Close, "missing bar has null prices")
+				assert.Equal(t, test.expectedCache, recorder.Header().Get("X-Cache"))
+			}
+			assert.Equal(t, test.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestGetQuotes(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockChartService := mocks.NewMockChartService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metric := metric.NewMetric(prometheusClient.Registry())
+	stockHandler := NewStockHandler(metric, logging.GetLogger("debug"), mockChartService, mocks.NewMockBarService(cntr), mocks.NewMockQuoteBook(cntr), mocks.NewMockIndicatorService(cntr), mocks.NewMockResampler(cntr))
+	chartOf := func(symbol string, price float64) entity.Series {
+		return entity.Series{Symbol: symbol, Price: price}
+	}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		query        string
+		mockCall     mockCall
+		expectedCode int
+		wantPrices   map[string]float64
+		wantStale    []string
+		wantErrors   []string
+	}{
+		{
+			title: "charts with inline failure and 200 response",
+			query: "?symbols=aapl,MSFT,,BAD,AAPL",
+			mockCall: func() {
+				aapl := entity.ChartQuery{Symbol: "AAPL"}
+				msft := entity.ChartQuery{Symbol: "MSFT"}
+				bad := entity.ChartQuery{Symbol: "BAD"}
+				mockChartService.EXPECT().
+					GetMany(gomock.Any(), []entity.ChartQuery{aapl, msft, bad}).
+					Return(map[string]entity.ChartResult{
+						aapl.Key(): {Series: chartOf("AAPL", 150), Status: entity.CacheStale},
+						msft.Key(): {Series: chartOf("MSFT", 250), Status: entity.CacheMiss},
+						bad.Key():  {Err: errors.New("unknown symbol")},
+					})
+			},
+			expectedCode: 200,
+			wantPrices:   map[string]float64{"AAPL": 150, "MSFT": 250},
+			wantStale:    []string{"AAPL"},
+			wantErrors:   []string{"BAD"},
+		},
+		{
+			title: "chart parameters are applied to every symbol and 200 response",
+			query: "?symbols=MSFT&range=1d",
+			mockCall: func() {
+				msft := entity.ChartQuery{Symbol: "MSFT", Range: "1d"}
+				mockChartService.EXPECT().
+					GetMany(gomock.Any(), []entity.ChartQuery{msft}).
+					Return(map[string]entity.ChartResult{msft.Key(): {Series: chartOf("MSFT", 250), Status: entity.CacheHit}})
+			},
+			expectedCode: 200,
+			wantPrices:   map[string]float64{"MSFT": 250},
+		},
+		{
+			title:        "empty symbols and 400 response",
+			query:        "?symbols=,",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title:        "unsupported interval and 400 response",
+			query:        "?symbols=AAPL&interval=2h",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/stock/quotes", stockHandler.GetQuotes)
+			req, _ := http.NewRequest("GET", "/api/stock/quotes"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data map[string]QuoteResult `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, len(test.wantPrices)+len(test.wantErrors), len(response.Data))
+			for symbol, price := range test.wantPrices {
+				assert.Equal(t, price, response.Data[symbol].Chart.Price)
+			}
+			for _, symbol := range test.wantStale {
+				assert.True(t, response.Data[symbol].Chart.Stale)
+			}
+			for _, symbol := range test.wantErrors {
+				assert.Nil(t, response.Data[symbol].Chart)
+				assert.NotEmpty(t, response.Data[symbol].Error)
+			}
+		})
+	}
+}
+
+func TestGetHistory(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockBarService := mocks.NewMockBarService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metric := metric.NewMetric(prometheusClient.Registry())
+	stockHandler := NewStockHandler(metric, logging.GetLogger("debug"), mocks.NewMockChartService(cntr), mockBarService, mocks.NewMockQuoteBook(cntr), mocks.NewMockIndicatorService(cntr), mocks.NewMockResampler(cntr))
+	bar := entity.Bar{Symbol: "AAPL", Interval: "1d", Timestamp: time.Unix(1665388800, 0), Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 100}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		query        string
+		mockCall     mockCall
+		expectedCode int
+		want         []BarView
+	}{
+		{
+			title: "stored bars and 200 response",
+			query: "?period1=1665000000&period2=1666000000",
+			mockCall: func() {
+				mockBarService.EXPECT().
+					History(gomock.Any(), entity.ChartQuery{Symbol: "AAPL", Period1: 1665000000, Period2: 1666000000}).
+					Return([]entity.Bar{bar}, nil)
+			},
+			expectedCode: 200,
+			want:         []BarView{{Timestamp: 1665388800, Open: 1, High: 3, Low: 0.5, Close: 2, Volume: 100}},
+		},
+		{
+			title:        "missing period1 and 400 response",
+			query:        "?interval=1d",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title:        "unsupported interval and 400 response",
+			query:        "?period1=1665000000&interval=2h",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title: "storage failure and 500 response",
+			query: "?period1=1665000000&interval=1h",
+			mockCall: func() {
+				mockBarService.EXPECT().
+					History(gomock.Any(), entity.ChartQuery{Symbol: "AAPL", Interval: "1h", Period1: 1665000000}).
+					Return(nil, errors.New("db error"))
+			},
+			expectedCode: 500,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/stock/history/:symbol", stockHandler.GetHistory)
+			req, _ := http.NewRequest("GET", "/api/stock/history/aapl"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
 			}
+			var response struct {
+				Data []BarView `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, response.Data)
+		})
+	}
+}
+
+func TestGetIndicators(t *testing.T) {
+	cntr := gomock.NewController(t)
+	mockIndicatorService := mocks.NewMockIndicatorService(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metric := metric.NewMetric(prometheusClient.Registry())
+	stockHandler := NewStockHandler(metric, logging.GetLogger("debug"), mocks.NewMockChartService(cntr), mocks.NewMockBarService(cntr), mocks.NewMockQuoteBook(cntr), mockIndicatorService, mocks.NewMockResampler(cntr))
+	value := 2.0
+	set := entity.IndicatorSet{Timestamp: []int64{100, 200}, Lines: map[string][]*float64{"sma:2": {nil, &value}}}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		query        string
+		mockCall     mockCall
+		expectedCode int
+		want         IndicatorView
+	}{
+		{
+			title: "indicators and 200 response",
+			query: "?names=sma:2,rsi&range=1y",
+			mockCall: func() {
+				mockIndicatorService.EXPECT().
+					Compute(gomock.Any(), entity.ChartQuery{Symbol: "AAPL", Range: "1y"}, []indicator.Spec{
+						{Name: "sma", Params: []float64{2}},
+						{Name: "rsi", Params: []float64{14}},
+					}).
+					Return(set, nil)
+			},
+			expectedCode: 200,
+			want:         IndicatorView{Symbol: "AAPL", Timestamp: []int64{100, 200}, Indicators: map[string][]*float64{"sma:2": {nil, &value}}},
+		},
+		{
+			title:        "unknown indicator and 400 response",
+			query:        "?names=vwap",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title:        "missing names and 400 response",
+			query:        "",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title: "chart failure and 500 response",
+			query: "?names=ema",
+			mockCall: func() {
+				mockIndicatorService.EXPECT().Compute(gomock.Any(), gomock.Any(), gomock.Any()).Return(entity.IndicatorSet{}, errors.New("http client error"))
+			},
+			expectedCode: 500,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			router.GET("/api/stock/symbols/:symbol/indicators", stockHandler.GetIndicators)
+			req, _ := http.NewRequest("GET", "/api/stock/symbols/AAPL/indicators"+test.query, nil)
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
 			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedCode != http.StatusOK {
+				return
+			}
+			var response struct {
+				Data IndicatorView `json:"data"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, test.want, response.Data)
 		})
 	}
 }
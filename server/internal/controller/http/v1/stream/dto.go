@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"errors"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+)
+
+const (
+	subscribeAction   = "subscribe"
+	unsubscribeAction = "unsubscribe"
+
+	tickType          = "tick"
+	subscriptionsType = "subscriptions"
+	errorType         = "error"
+)
+
+type Command struct {
+	Action  string   `json:"action"`
+	Symbols []string `json:"symbols"`
+}
+
+type TickView struct {
+	Symbol string  `json:"symbol"`
+	Price  float64 `json:"price"`
+	Change float64 `json:"change"`
+	Time   string  `json:"time"`
+}
+
+type Message struct {
+	Type    string    `json:"type"`
+	Tick    *TickView `json:"tick,omitempty"`
+	Symbols []string  `json:"symbols,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+type BarView struct {
+	Time   string  `json:"time"`
+	Open   float64 `json:"open"`
+	High   float64 `json:"high"`
+	Low    float64 `json:"low"`
+	Close  float64 `json:"close"`
+	Volume float64 `json:"volume"`
+}
+
+type QuoteEvent struct {
+	Symbol string   `json:"symbol"`
+	Price  float64  `json:"price"`
+	Change float64  `json:"change"`
+	Time   string   `json:"time"`
+	Bar    *BarView `json:"bar,omitempty"`
+}
+
+func QuoteFromTick(tick entity.Tick) QuoteEvent {
+	quote := QuoteEvent{
+		Symbol: tick.Symbol,
+		Price:  tick.Price,
+		Change: tick.Change,
+		Time:   tick.Time.Format(time.RFC3339),
+	}
+	if !tick.Bar.Timestamp.IsZero() {
+		quote.Bar = &BarView{
+			Time:   tick.Bar.Timestamp.Format(time.RFC3339),
+			Open:   tick.Bar.Open,
+			High:   tick.Bar.High,
+			Low:    tick.Bar.Low,
+			Close:  tick.Bar.Close,
+			Volume: tick.Bar.Volume,
+		}
+	}
+	return quote
+}
+
+func TickMessage(tick entity.Tick) Message {
+	return Message{Type: tickType, Tick: &TickView{
+		Symbol: tick.Symbol,
+		Price:  tick.Price,
+		Change: tick.Change,
+		Time:   tick.Time.Format(time.RFC3339),
+	}}
+}
+
+func ErrorMessage(err error) Message {
+	var e *errs.Error
+	if errors.As(err, &e) && e.Code != "" {
+		return Message{Type: errorType, Error: string(e.Code)}
+	}
+	return Message{Type: errorType, Error: err.Error()}
+}
@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	heartbeatPeriod = 15 * time.Second
+	retryAfter      = 3 * time.Second
+)
+
+// Events streams the quote of the symbol as server-sent events for clients
+// behind proxies that drop WebSockets. A quote is sent whenever the price or
+// the last bar changes, a client resuming with Last-Event-ID doesn't get the
+// quote it has already seen again.
+//
+// Every event gets its own write deadline in place of the write timeout of
+// the server, which would cut the stream otherwise.
+func (s *streamHandler) Events(ctx *gin.Context) {
+	if _, ok := middleware.User(ctx, s.logger); !ok {
+		return
+	}
+	subscriber := s.hub.Connect()
+	defer subscriber.Close()
+	if _, err := subscriber.Subscribe(ctx.Param("symbol")); err != nil {
+		errs.HTTPErrorResponse(ctx, s.logger, err)
+		return
+	}
+	lastId, _ := strconv.ParseUint(ctx.GetHeader("Last-Event-ID"), 10, 64)
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	if err := s.event(ctx, fmt.Sprintf("retry: %v\n\n", retryAfter.Milliseconds())); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(s.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-subscriber.Done():
+			return
+		case <-subscriber.Ready():
+			for _, tick := range subscriber.Ticks() {
+				if tick.Id <= lastId {
+					continue
+				}
+				if err := s.event(ctx, quoteEvent(tick)); err != nil {
+					return
+				}
+			}
+		case <-heartbeat.C:
+			if err := s.event(ctx, ": ping\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *streamHandler) event(ctx *gin.Context, event string) error {
+	err := middleware.ResponseController(ctx).SetWriteDeadline(time.Now().Add(writeWait))
+	if err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+	if _, err := io.WriteString(ctx.Writer, event); err != nil {
+		return err
+	}
+	ctx.Writer.Flush()
+	return nil
+}
+
+func quoteEvent(tick entity.Tick) string {
+	data, _ := json.Marshal(QuoteFromTick(tick))
+	return fmt.Sprintf("id: %v\nevent: quote\ndata: %s\n\n", tick.Id, data)
+}
@@ -0,0 +1,163 @@
+package stream
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/stream"
+	"github.com/VrMolodyakov/stock-market/internal/stream/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func eventsRouter(t *testing.T) *gin.Engine {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	source.EXPECT().Latest(gomock.Any(), entity.ChartQuery{Symbol: "AAPL"}).Return(entity.ChartResult{
+		Series: entity.Series{
+			Price:     150,
+			PriceTime: time.Unix(1700000000, 0),
+			Bars:      []entity.Bar{{Timestamp: time.Unix(1699999940, 0), Open: 149, High: 151, Low: 148, Close: 150, Volume: 10}},
+		},
+	}, nil).AnyTimes()
+	logger := logging.GetLogger("debug")
+	hub := stream.New(logger, source, nil, stream.Options{Interval: time.Hour})
+	t.Cleanup(func() { hub.Close() })
+	handler := NewStreamHandler(logger, hub, nil)
+	handler.heartbeat = 50 * time.Millisecond
+	router := gin.New()
+	auth := func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") != "" {
+			ctx.Set("user", entity.User{Id: 7})
+		}
+	}
+	router.GET("/api/stock/symbols/:symbol", auth, func(ctx *gin.Context) { ctx.Status(http.StatusOK) })
+	router.GET("/api/stock/symbols/:symbol/events", auth, handler.Events)
+	return router
+}
+
+// eventsServer serves the events with a write timeout shorter than the
+// tests read the stream for.
+func eventsServer(t *testing.T, http2 bool) *httptest.Server {
+	server := httptest.NewUnstartedServer(middleware.WithResponseController(eventsRouter(t)))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	if http2 {
+		server.EnableHTTP2 = true
+		server.StartTLS()
+	} else {
+		server.Start()
+	}
+	t.Cleanup(server.Close)
+	return server
+}
+
+// readEvent returns the next event or comment of the stream without the
+// trailing blank line.
+func readEvent(t *testing.T, reader *bufio.Reader) string {
+	lines := make([]string, 0)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func openEvents(t *testing.T, server *httptest.Server, lastEventId string) (*http.Response, *bufio.Reader) {
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/stock/symbols/aapl/events", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	if lastEventId != "" {
+		req.Header.Set("Last-Event-ID", lastEventId)
+	}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestEvents(t *testing.T) {
+	server := eventsServer(t, false)
+	resp, reader := openEvents(t, server, "")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	assert.Equal(t, "retry: 3000", readEvent(t, reader))
+	event := readEvent(t, reader)
+	lines := strings.Split(event, "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[0], "id: "))
+		assert.Equal(t, "event: quote", lines[1])
+		assert.Equal(t, `data: {"symbol":"AAPL","price":150,"change":0,"time":"2023-11-14T22:13:20Z",`+
+			`"bar":{"time":"2023-11-14T22:12:20Z","open":149,"high":151,"low":148,"close":150,"volume":10}}`, lines[2])
+	}
+
+	resumed, reader := openEvents(t, server, strings.TrimPrefix(lines[0], "id: "))
+	assert.Equal(t, http.StatusOK, resumed.StatusCode)
+	assert.Equal(t, "retry: 3000", readEvent(t, reader))
+	assert.Equal(t, ": ping", readEvent(t, reader), "the quote seen before the resume isn't sent again")
+}
+
+func TestEventsOutliveWriteTimeout(t *testing.T) {
+	for _, major := range []int{1, 2} {
+		server := eventsServer(t, major == 2)
+		resp, reader := openEvents(t, server, "")
+		assert.Equal(t, major, resp.ProtoMajor)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "retry: 3000", readEvent(t, reader))
+		assert.True(t, strings.HasPrefix(readEvent(t, reader), "id: "))
+		// the heartbeats keep coming after the write timeout has passed
+		for i := 0; i < 5; i++ {
+			assert.Equal(t, ": ping", readEvent(t, reader))
+		}
+	}
+}
+
+func TestEventsRejected(t *testing.T) {
+	server := eventsServer(t, false)
+	testCases := []struct {
+		title        string
+		path         string
+		authorized   bool
+		expectedCode int
+	}{
+		{
+			title:        "missing user and 403 response",
+			path:         "/api/stock/symbols/AAPL/events",
+			expectedCode: 403,
+		},
+		{
+			title:        "incorrect symbol and 400 response",
+			path:         "/api/stock/symbols/A$PL/events",
+			authorized:   true,
+			expectedCode: 400,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, server.URL+test.path, nil)
+			if test.authorized {
+				req.Header.Set("Authorization", "Bearer token")
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			assert.Equal(t, test.expectedCode, resp.StatusCode)
+		})
+	}
+}
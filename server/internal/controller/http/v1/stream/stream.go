@@ -0,0 +1,158 @@
+package stream
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/internal/stream"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = pongWait * 9 / 10
+	maxMessageSize = 4096
+	repliesBuffer  = 16
+)
+
+type Hub interface {
+	Connect() *stream.Subscriber
+}
+
+type streamHandler struct {
+	logger    *logging.Logger
+	hub       Hub
+	upgrader  websocket.Upgrader
+	heartbeat time.Duration
+}
+
+// NewStreamHandler returns the handler of the price stream. Browsers send the
+// auth cookie with the handshake of any origin, so only the allowed origins
+// and the origin of the server itself may connect.
+func NewStreamHandler(logger *logging.Logger, hub Hub, origins []string) *streamHandler {
+	allowed := make(map[string]bool, len(origins))
+	for _, origin := range origins {
+		allowed[origin] = true
+	}
+	return &streamHandler{
+		logger:    logger,
+		hub:       hub,
+		heartbeat: heartbeatPeriod,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:  1024,
+			WriteBufferSize: 1024,
+			CheckOrigin: func(r *http.Request) bool {
+				origin := r.Header.Get("Origin")
+				if origin == "" || allowed[origin] {
+					return true
+				}
+				u, err := url.Parse(origin)
+				return err == nil && u.Host == r.Host
+			},
+		},
+	}
+}
+
+// Stream upgrades the connection and serves the subscription commands of the
+// client until either side closes it.
+func (s *streamHandler) Stream(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, s.logger)
+	if !ok {
+		return
+	}
+	conn, err := s.upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		s.logger.Errorf("cannot upgrade stream connection of user %v due to : %v", user.Id, err)
+		return
+	}
+	subscriber := s.hub.Connect()
+	replies := make(chan Message, repliesBuffer)
+	go s.read(conn, subscriber, replies)
+	s.write(conn, subscriber, replies)
+}
+
+func (s *streamHandler) read(conn *websocket.Conn, subscriber *stream.Subscriber, replies chan<- Message) {
+	defer subscriber.Close()
+	conn.SetReadLimit(maxMessageSize)
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+	for {
+		var command Command
+		if err := conn.ReadJSON(&command); err != nil {
+			var closeErr *websocket.CloseError
+			if !errors.As(err, &closeErr) {
+				s.logger.Debugf("stream connection closed due to : %v", err)
+			}
+			return
+		}
+		reply := s.execute(subscriber, command)
+		select {
+		case replies <- reply:
+		case <-subscriber.Done():
+			return
+		}
+	}
+}
+
+func (s *streamHandler) execute(subscriber *stream.Subscriber, command Command) Message {
+	var symbols []string
+	var err error
+	switch command.Action {
+	case subscribeAction:
+		symbols, err = subscriber.Subscribe(command.Symbols...)
+	case unsubscribeAction:
+		symbols, err = subscriber.Unsubscribe(command.Symbols...)
+	default:
+		err = errs.New(errs.Validation, errs.Code("unsupported action"), errs.Parameter("action"))
+	}
+	if err != nil {
+		return ErrorMessage(err)
+	}
+	return Message{Type: subscriptionsType, Symbols: symbols}
+}
+
+// write is the only writer of the connection. It sends the replies, the
+// pending ticks and the pings and closes the connection when done.
+func (s *streamHandler) write(conn *websocket.Conn, subscriber *stream.Subscriber, replies <-chan Message) {
+	ping := time.NewTicker(pingPeriod)
+	defer func() {
+		ping.Stop()
+		subscriber.Close()
+		conn.Close()
+	}()
+	for {
+		select {
+		case <-subscriber.Done():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeWait))
+			return
+		case reply := <-replies:
+			if err := s.send(conn, reply); err != nil {
+				return
+			}
+		case <-subscriber.Ready():
+			for _, tick := range subscriber.Ticks() {
+				if err := s.send(conn, TickMessage(tick)); err != nil {
+					return
+				}
+			}
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (s *streamHandler) send(conn *websocket.Conn, message Message) error {
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return conn.WriteJSON(message)
+}
@@ -0,0 +1,127 @@
+package stream
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/stream"
+	"github.com/VrMolodyakov/stock-market/internal/stream/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func streamServer(t *testing.T, authorized bool) *httptest.Server {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	source.EXPECT().Latest(gomock.Any(), entity.ChartQuery{Symbol: "AAPL"}).Return(entity.ChartResult{
+		Series: entity.Series{Price: 150, PriceTime: time.Unix(1700000000, 0)},
+	}, nil).AnyTimes()
+	logger := logging.GetLogger("debug")
+	hub := stream.New(logger, source, nil, stream.Options{Interval: time.Hour, MaxSymbols: 10})
+	t.Cleanup(func() { hub.Close() })
+	handler := NewStreamHandler(logger, hub, []string{"http://localhost:3001"})
+	router := gin.New()
+	router.GET("/api/stream", func(ctx *gin.Context) {
+		if authorized {
+			ctx.Set("user", entity.User{Id: 7})
+		}
+	}, handler.Stream)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dial(server *httptest.Server, origin string) (*websocket.Conn, *http.Response, error) {
+	header := http.Header{}
+	if origin != "" {
+		header.Set("Origin", origin)
+	}
+	return websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/stream", header)
+}
+
+func TestStream(t *testing.T) {
+	server := streamServer(t, true)
+	conn, _, err := dial(server, "http://localhost:3001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	testCases := []struct {
+		title   string
+		command Command
+		want    []Message
+	}{
+		{
+			title:   "subscription is acknowledged and the price is pushed",
+			command: Command{Action: "subscribe", Symbols: []string{"aapl"}},
+			want: []Message{
+				{Type: subscriptionsType, Symbols: []string{"AAPL"}},
+				{Type: tickType, Tick: &TickView{Symbol: "AAPL", Price: 150, Time: "2023-11-14T22:13:20Z"}},
+			},
+		},
+		{
+			title:   "incorrect symbol is reported",
+			command: Command{Action: "subscribe", Symbols: []string{"$$$"}},
+			want:    []Message{{Type: errorType, Error: "incorrect symbol"}},
+		},
+		{
+			title:   "unsupported action is reported",
+			command: Command{Action: "buy"},
+			want:    []Message{{Type: errorType, Error: "unsupported action"}},
+		},
+		{
+			title:   "unsubscription is acknowledged",
+			command: Command{Action: "unsubscribe", Symbols: []string{"AAPL"}},
+			want:    []Message{{Type: subscriptionsType}},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			assert.NoError(t, conn.WriteJSON(test.command))
+			got := make([]Message, len(test.want))
+			for i := range got {
+				assert.NoError(t, conn.ReadJSON(&got[i]))
+			}
+			assert.ElementsMatch(t, test.want, got, "the tick may outrun the acknowledgement")
+		})
+	}
+}
+
+func TestStreamHandshake(t *testing.T) {
+	testCases := []struct {
+		title        string
+		authorized   bool
+		origin       string
+		expectedCode int
+	}{
+		{
+			title:        "foreign origin and 403 response",
+			authorized:   true,
+			origin:       "http://evil.example.com",
+			expectedCode: 403,
+		},
+		{
+			title:        "missing user and 403 response",
+			origin:       "http://localhost:3001",
+			expectedCode: 403,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			server := streamServer(t, test.authorized)
+			_, resp, err := dial(server, test.origin)
+			assert.Error(t, err)
+			if assert.NotNil(t, resp) {
+				assert.Equal(t, test.expectedCode, resp.StatusCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package watchlist
+
+import (
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type NameRequest struct {
+	Name string `json:"name"`
+}
+
+type SymbolRequest struct {
+	Symbol string `json:"symbol"`
+}
+
+type OrderRequest struct {
+	Symbols []string `json:"symbols"`
+}
+
+type WatchlistResponse struct {
+	Id       int      `json:"id"`
+	Name     string   `json:"name"`
+	Symbols  []string `json:"symbols"`
+	CreateAt string   `json:"create_at"`
+}
+
+func ResponseFromEntity(watchlist entity.Watchlist) WatchlistResponse {
+	symbols := watchlist.Symbols
+	if symbols == nil {
+		symbols = []string{}
+	}
+	return WatchlistResponse{
+		Id:       watchlist.Id,
+		Name:     watchlist.Name,
+		Symbols:  symbols,
+		CreateAt: watchlist.CreateAt.Format(time.RFC3339),
+	}
+}
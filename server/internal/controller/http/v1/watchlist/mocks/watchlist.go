@@ -0,0 +1,155 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/controller/http/v1/watchlist/watchlist.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWatchlistService is a mock of WatchlistService interface.
+type MockWatchlistService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistServiceMockRecorder
+}
+
+// MockWatchlistServiceMockRecorder is the mock recorder for MockWatchlistService.
+type MockWatchlistServiceMockRecorder struct {
+	mock *MockWatchlistService
+}
+
+// NewMockWatchlistService creates a new mock instance.
+func NewMockWatchlistService(ctrl *gomock.Controller) *MockWatchlistService {
+	mock := &MockWatchlistService{ctrl: ctrl}
+	mock.recorder = &MockWatchlistServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlistService) EXPECT() *MockWatchlistServiceMockRecorder {
+	return m.recorder
+}
+
+// AddSymbol mocks base method.
+func (m *MockWatchlistService) AddSymbol(ctx context.Context, userId, id int, symbol string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSymbol", ctx, userId, id, symbol)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddSymbol indicates an expected call of AddSymbol.
+func (mr *MockWatchlistServiceMockRecorder) AddSymbol(ctx, userId, id, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSymbol", reflect.TypeOf((*MockWatchlistService)(nil).AddSymbol), ctx, userId, id, symbol)
+}
+
+// Create mocks base method.
+func (m *MockWatchlistService) Create(ctx context.Context, userId int, name string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, userId, name)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockWatchlistServiceMockRecorder) Create(ctx, userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockWatchlistService)(nil).Create), ctx, userId, name)
+}
+
+// Delete mocks base method.
+func (m *MockWatchlistService) Delete(ctx context.Context, userId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWatchlistServiceMockRecorder) Delete(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWatchlistService)(nil).Delete), ctx, userId, id)
+}
+
+// Get mocks base method.
+func (m *MockWatchlistService) Get(ctx context.Context, userId, id int) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, userId, id)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockWatchlistServiceMockRecorder) Get(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockWatchlistService)(nil).Get), ctx, userId, id)
+}
+
+// GetAll mocks base method.
+func (m *MockWatchlistService) GetAll(ctx context.Context, userId int) ([]entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx, userId)
+	ret0, _ := ret[0].([]entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockWatchlistServiceMockRecorder) GetAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockWatchlistService)(nil).GetAll), ctx, userId)
+}
+
+// RemoveSymbol mocks base method.
+func (m *MockWatchlistService) RemoveSymbol(ctx context.Context, userId, id int, symbol string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSymbol", ctx, userId, id, symbol)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveSymbol indicates an expected call of RemoveSymbol.
+func (mr *MockWatchlistServiceMockRecorder) RemoveSymbol(ctx, userId, id, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSymbol", reflect.TypeOf((*MockWatchlistService)(nil).RemoveSymbol), ctx, userId, id, symbol)
+}
+
+// Rename mocks base method.
+func (m *MockWatchlistService) Rename(ctx context.Context, userId, id int, name string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, userId, id, name)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockWatchlistServiceMockRecorder) Rename(ctx, userId, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockWatchlistService)(nil).Rename), ctx, userId, id, name)
+}
+
+// Reorder mocks base method.
+func (m *MockWatchlistService) Reorder(ctx context.Context, userId, id int, symbols []string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, userId, id, symbols)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockWatchlistServiceMockRecorder) Reorder(ctx, userId, id, symbols interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockWatchlistService)(nil).Reorder), ctx, userId, id, symbols)
+}
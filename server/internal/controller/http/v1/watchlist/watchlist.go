@@ -0,0 +1,158 @@
+package watchlist
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/middleware"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+)
+
+type WatchlistService interface {
+	Create(ctx context.Context, userId int, name string) (entity.Watchlist, error)
+	GetAll(ctx context.Context, userId int) ([]entity.Watchlist, error)
+	Get(ctx context.Context, userId int, id int) (entity.Watchlist, error)
+	Rename(ctx context.Context, userId int, id int, name string) (entity.Watchlist, error)
+	Delete(ctx context.Context, userId int, id int) error
+	AddSymbol(ctx context.Context, userId int, id int, symbol string) (entity.Watchlist, error)
+	RemoveSymbol(ctx context.Context, userId int, id int, symbol string) (entity.Watchlist, error)
+	Reorder(ctx context.Context, userId int, id int, symbols []string) (entity.Watchlist, error)
+}
+
+type watchlistHandler struct {
+	logger           *logging.Logger
+	watchlistService WatchlistService
+}
+
+func NewWatchlistHandler(logger *logging.Logger, watchlistService WatchlistService) *watchlistHandler {
+	return &watchlistHandler{logger: logger, watchlistService: watchlistService}
+}
+
+func (w *watchlistHandler) GetAll(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, w.logger)
+	if !ok {
+		return
+	}
+	watchlists, err := w.watchlistService.GetAll(ctx.Request.Context(), user.Id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, err)
+		return
+	}
+	response := make([]WatchlistResponse, len(watchlists))
+	for i, watchlist := range watchlists {
+		response[i] = ResponseFromEntity(watchlist)
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success", "data": response})
+}
+
+func (w *watchlistHandler) Get(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	watchlist, err := w.watchlistService.Get(ctx.Request.Context(), user.Id, id)
+	w.respond(ctx, http.StatusOK, watchlist, err)
+}
+
+func (w *watchlistHandler) Create(ctx *gin.Context) {
+	user, ok := middleware.User(ctx, w.logger)
+	if !ok {
+		return
+	}
+	var request NameRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	watchlist, err := w.watchlistService.Create(ctx.Request.Context(), user.Id, request.Name)
+	w.respond(ctx, http.StatusCreated, watchlist, err)
+}
+
+func (w *watchlistHandler) Rename(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	var request NameRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	watchlist, err := w.watchlistService.Rename(ctx.Request.Context(), user.Id, id, request.Name)
+	w.respond(ctx, http.StatusOK, watchlist, err)
+}
+
+func (w *watchlistHandler) Delete(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	err := w.watchlistService.Delete(ctx.Request.Context(), user.Id, id)
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"status": "success"})
+}
+
+func (w *watchlistHandler) AddSymbol(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	var request SymbolRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	watchlist, err := w.watchlistService.AddSymbol(ctx.Request.Context(), user.Id, id, request.Symbol)
+	w.respond(ctx, http.StatusOK, watchlist, err)
+}
+
+func (w *watchlistHandler) RemoveSymbol(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	watchlist, err := w.watchlistService.RemoveSymbol(ctx.Request.Context(), user.Id, id, ctx.Param("symbol"))
+	w.respond(ctx, http.StatusOK, watchlist, err)
+}
+
+func (w *watchlistHandler) Reorder(ctx *gin.Context) {
+	user, id, ok := w.userAndId(ctx)
+	if !ok {
+		return
+	}
+	var request OrderRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, errs.New(errs.Validation, errs.Code("incorrect request body"), err))
+		return
+	}
+	watchlist, err := w.watchlistService.Reorder(ctx.Request.Context(), user.Id, id, request.Symbols)
+	w.respond(ctx, http.StatusOK, watchlist, err)
+}
+
+func (w *watchlistHandler) respond(ctx *gin.Context, code int, watchlist entity.Watchlist, err error) {
+	if err != nil {
+		errs.HTTPErrorResponse(ctx, w.logger, err)
+		return
+	}
+	ctx.JSON(code, gin.H{"status": "success", "data": ResponseFromEntity(watchlist)})
+}
+
+func (w *watchlistHandler) userAndId(ctx *gin.Context) (entity.User, int, bool) {
+	user, ok := middleware.User(ctx, w.logger)
+	if !ok {
+		return entity.User{}, 0, false
+	}
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil || id <= 0 {
+		errs.HTTPErrorResponse(ctx, w.logger, errs.New(errs.Validation, errs.Code("incorrect watchlist id"), errs.Parameter("id")))
+		return entity.User{}, 0, false
+	}
+	return user, id, true
+}
@@ -0,0 +1,170 @@
+package watchlist
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/controller/http/v1/watchlist/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchlistHandler(t *testing.T) {
+	cntr := gomock.NewController(t)
+	watchlistService := mocks.NewMockWatchlistService(cntr)
+	handler := NewWatchlistHandler(logging.GetLogger("debug"), watchlistService)
+	user := entity.User{Id: 7, Username: "user"}
+	tech := entity.Watchlist{Id: 3, UserId: 7, Name: "Tech", Symbols: []string{"AAPL"}, CreateAt: time.Now()}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		method       string
+		path         string
+		body         string
+		noUser       bool
+		mockCall     mockCall
+		expectedCode int
+		expectedName string
+	}{
+		{
+			title:  "create watchlist and 201 response",
+			method: http.MethodPost,
+			path:   "/api/watchlists",
+			body:   `{"name":"Tech"}`,
+			mockCall: func() {
+				watchlistService.EXPECT().Create(gomock.Any(), 7, "Tech").Return(tech, nil)
+			},
+			expectedCode: 201,
+			expectedName: "Tech",
+		},
+		{
+			title:  "duplicated watchlist and 400 response",
+			method: http.MethodPost,
+			path:   "/api/watchlists",
+			body:   `{"name":"Tech"}`,
+			mockCall: func() {
+				watchlistService.EXPECT().Create(gomock.Any(), 7, "Tech").
+					Return(entity.Watchlist{}, errs.New(errs.Validation, errs.Code("watchlist already exists")))
+			},
+			expectedCode: 400,
+		},
+		{
+			title:  "rename watchlist and 200 response",
+			method: http.MethodPatch,
+			path:   "/api/watchlists/3",
+			body:   `{"name":"Tech"}`,
+			mockCall: func() {
+				watchlistService.EXPECT().Rename(gomock.Any(), 7, 3, "Tech").Return(tech, nil)
+			},
+			expectedCode: 200,
+			expectedName: "Tech",
+		},
+		{
+			title:        "incorrect id and 400 response",
+			method:       http.MethodGet,
+			path:         "/api/watchlists/abc",
+			mockCall:     func() {},
+			expectedCode: 400,
+		},
+		{
+			title:  "add symbol and 200 response",
+			method: http.MethodPost,
+			path:   "/api/watchlists/3/symbols",
+			body:   `{"symbol":"aapl"}`,
+			mockCall: func() {
+				watchlistService.EXPECT().AddSymbol(gomock.Any(), 7, 3, "aapl").Return(tech, nil)
+			},
+			expectedCode: 200,
+			expectedName: "Tech",
+		},
+		{
+			title:  "remove symbol and 200 response",
+			method: http.MethodDelete,
+			path:   "/api/watchlists/3/symbols/AAPL",
+			mockCall: func() {
+				watchlistService.EXPECT().RemoveSymbol(gomock.Any(), 7, 3, "AAPL").Return(tech, nil)
+			},
+			expectedCode: 200,
+			expectedName: "Tech",
+		},
+		{
+			title:  "reorder symbols and 200 response",
+			method: http.MethodPut,
+			path:   "/api/watchlists/3/symbols",
+			body:   `{"symbols":["AAPL"]}`,
+			mockCall: func() {
+				watchlistService.EXPECT().Reorder(gomock.Any(), 7, 3, []string{"AAPL"}).Return(tech, nil)
+			},
+			expectedCode: 200,
+			expectedName: "Tech",
+		},
+		{
+			title:  "delete watchlist and 200 response",
+			method: http.MethodDelete,
+			path:   "/api/watchlists/3",
+			mockCall: func() {
+				watchlistService.EXPECT().Delete(gomock.Any(), 7, 3).Return(nil)
+			},
+			expectedCode: 200,
+		},
+		{
+			title:  "storage failure and 500 response",
+			method: http.MethodGet,
+			path:   "/api/watchlists",
+			mockCall: func() {
+				watchlistService.EXPECT().GetAll(gomock.Any(), 7).Return(nil, errors.New("db error"))
+			},
+			expectedCode: 500,
+		},
+		{
+			title:        "missing user and 403 response",
+			method:       http.MethodGet,
+			path:         "/api/watchlists",
+			noUser:       true,
+			mockCall:     func() {},
+			expectedCode: 403,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			router := gin.Default()
+			group := router.Group("/api/watchlists", func(ctx *gin.Context) {
+				if !test.noUser {
+					ctx.Set("user", user)
+				}
+			})
+			group.GET("", handler.GetAll)
+			group.POST("", handler.Create)
+			group.GET("/:id", handler.Get)
+			group.PATCH("/:id", handler.Rename)
+			group.DELETE("/:id", handler.Delete)
+			group.POST("/:id/symbols", handler.AddSymbol)
+			group.PUT("/:id/symbols", handler.Reorder)
+			group.DELETE("/:id/symbols/:symbol", handler.RemoveSymbol)
+			req, _ := http.NewRequest(test.method, test.path, strings.NewReader(test.body))
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+			assert.Equal(t, test.expectedCode, recorder.Code)
+			if test.expectedName != "" {
+				var response struct {
+					Data WatchlistResponse `json:"data"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatal(err)
+				}
+				assert.Equal(t, test.expectedName, response.Data.Name)
+				assert.Equal(t, tech.Symbols, response.Data.Symbols)
+			}
+		})
+	}
+}
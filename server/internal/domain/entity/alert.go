@@ -0,0 +1,46 @@
+package entity
+
+import "time"
+
+type AlertCondition string
+
+const (
+	CrossAbove  AlertCondition = "cross_above"
+	CrossBelow  AlertCondition = "cross_below"
+	RisePercent AlertCondition = "rise_percent"
+	DropPercent AlertCondition = "drop_percent"
+)
+
+type AlertChannel string
+
+const (
+	Webhook AlertChannel = "webhook"
+	Email   AlertChannel = "email"
+)
+
+// Alert fires when its condition is met while it's armed. A one-shot alert is
+// deactivated after firing, a re-arming alert is armed again once the price
+// moves back out of the hysteresis band.
+type Alert struct {
+	Id        int
+	UserId    int
+	Symbol    string
+	Condition AlertCondition
+	Threshold float64
+	Channel   AlertChannel
+	Target    string
+	Rearm     bool
+	Armed     bool
+	Active    bool
+	FiredAt   time.Time
+	CreateAt  time.Time
+}
+
+// AlertEvent is what a notifier delivers. Change is the intraday change of
+// the price in percent.
+type AlertEvent struct {
+	Alert   Alert
+	Price   float64
+	Change  float64
+	FiredAt time.Time
+}
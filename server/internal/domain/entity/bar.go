@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+// Bar is a candle of a symbol. A Missing bar marks a point the provider
+// returned without prices, its values are zero.
+type Bar struct {
+	Symbol    string    `json:"symbol,omitempty"`
+	Interval  string    `json:"interval,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`
+	Missing   bool      `json:"missing,omitempty"`
+}
@@ -0,0 +1,79 @@
+package entity
+
+import (
+	"fmt"
+	"time"
+)
+
+// Series is the chart of a symbol normalized from the provider response.
+// Points the provider returned without prices are kept as missing bars, so
+// the bars stay aligned with the timestamps of the requested range. Price is
+// the latest market price and PreviousClose is zero when it's unknown.
+type Series struct {
+	Symbol        string    `json:"symbol"`
+	Exchange      string    `json:"exchange,omitempty"`
+	Price         float64   `json:"price"`
+	PriceTime     time.Time `json:"price_time"`
+	PreviousClose float64   `json:"previous_close,omitempty"`
+	Bars          []Bar     `json:"bars"`
+}
+
+// LastBar returns the latest bar that isn't missing.
+func (s Series) LastBar() (Bar, bool) {
+	for i := len(s.Bars) - 1; i >= 0; i-- {
+		if !s.Bars[i].Missing {
+			return s.Bars[i], true
+		}
+	}
+	return Bar{}, false
+}
+
+type ChartQuery struct {
+	Symbol   string
+	Range    string
+	Interval string
+	Period1  int64
+	Period2  int64
+}
+
+// Key identifies the chart in caches, so that different ranges and intervals
+// of the same symbol don't collide.
+func (q ChartQuery) Key() string {
+	return fmt.Sprintf("%v:%v:%v:%v:%v", q.Symbol, q.Range, q.Interval, q.Period1, q.Period2)
+}
+
+// Daily reports whether the chart covers the current session. The previous
+// close of other charts is the close before their range and not the close of
+// the previous day.
+func (q ChartQuery) Daily() bool {
+	return q.Period1 == 0 && (q.Range == "" || q.Range == "1d")
+}
+
+// CachedChart is the cache envelope of a chart. The chart is fresh until
+// FreshUntil and may be served as stale until the cache entry expires.
+// Entries of another Version are ignored, so changes of the model don't
+// serve garbled charts.
+type CachedChart struct {
+	Version    int    `json:"version"`
+	Series     Series `json:"series"`
+	FetchedAt  int64  `json:"fetched_at"`
+	FreshUntil int64  `json:"fresh_until"`
+}
+
+type CacheStatus string
+
+const (
+	CacheHit   CacheStatus = "HIT"
+	CacheMiss  CacheStatus = "MISS"
+	CacheStale CacheStatus = "STALE"
+)
+
+type ChartResult struct {
+	Series Series
+	Status CacheStatus
+	Err    error
+}
+
+func (r ChartResult) Stale() bool {
+	return r.Status == CacheStale
+}
@@ -0,0 +1,9 @@
+package entity
+
+// IndicatorSet holds the indicator lines computed from the closes of a chart.
+// Every line is aligned with Timestamp, nil marks a point where the indicator
+// is undefined or the bar is missing.
+type IndicatorSet struct {
+	Timestamp []int64               `json:"timestamp"`
+	Lines     map[string][]*float64 `json:"lines"`
+}
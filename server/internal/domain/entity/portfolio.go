@@ -0,0 +1,100 @@
+package entity
+
+import "time"
+
+type OrderSide string
+
+const (
+	Buy  OrderSide = "buy"
+	Sell OrderSide = "sell"
+)
+
+type Account struct {
+	UserId   int
+	Cash     float64
+	Realized float64
+}
+
+type Position struct {
+	Symbol      string
+	Quantity    int64
+	AverageCost float64
+}
+
+type Order struct {
+	Id       int
+	UserId   int
+	Symbol   string
+	Side     OrderSide
+	Quantity int64
+	Price    float64
+	Realized float64
+	CreateAt time.Time
+}
+
+type Holding struct {
+	Position
+	Price       float64
+	MarketValue float64
+	Unrealized  float64
+}
+
+type Portfolio struct {
+	Account
+	Holdings   []Holding
+	Unrealized float64
+	Equity     float64
+}
+
+type OrderType string
+
+const (
+	Limit     OrderType = "limit"
+	Stop      OrderType = "stop"
+	StopLimit OrderType = "stop_limit"
+)
+
+type OrderState string
+
+const (
+	Open            OrderState = "open"
+	PartiallyFilled OrderState = "partially_filled"
+	Filled          OrderState = "filled"
+	Cancelled       OrderState = "cancelled"
+	Expired         OrderState = "expired"
+)
+
+type TimeInForce string
+
+const (
+	Day TimeInForce = "DAY"
+	GTC TimeInForce = "GTC"
+)
+
+// BookOrder is a resting order that is filled by the matcher once the market
+// price reaches its limit or stop price. ExpiresAt is zero for GTC orders.
+type BookOrder struct {
+	Id          int
+	UserId      int
+	Symbol      string
+	Side        OrderSide
+	Type        OrderType
+	Quantity    int64
+	Filled      int64
+	LimitPrice  float64
+	StopPrice   float64
+	Triggered   bool
+	TimeInForce TimeInForce
+	State       OrderState
+	ExpiresAt   time.Time
+	CreateAt    time.Time
+	UpdateAt    time.Time
+}
+
+func (o BookOrder) Remaining() int64 {
+	return o.Quantity - o.Filled
+}
+
+func (o BookOrder) Active() bool {
+	return o.State == Open || o.State == PartiallyFilled
+}
@@ -0,0 +1,19 @@
+package entity
+
+// Profile holds the company fundamentals, the values the provider doesn't
+// have are nil. A Partial profile is built from the chart and the symbol
+// directory when no provider returned the fundamentals.
+type Profile struct {
+	Symbol        string   `json:"symbol"`
+	Name          string   `json:"name"`
+	Exchange      string   `json:"exchange"`
+	Sector        string   `json:"sector"`
+	Industry      string   `json:"industry"`
+	Currency      string   `json:"currency"`
+	MarketCap     *float64 `json:"market_cap"`
+	PERatio       *float64 `json:"pe_ratio"`
+	DividendYield *float64 `json:"dividend_yield"`
+	Week52Low     *float64 `json:"week52_low"`
+	Week52High    *float64 `json:"week52_high"`
+	Partial       bool     `json:"partial"`
+}
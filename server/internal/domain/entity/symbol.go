@@ -0,0 +1,10 @@
+package entity
+
+// SymbolInfo is an entry of the symbol directory used by the search.
+type SymbolInfo struct {
+	Symbol   string `json:"symbol"`
+	Name     string `json:"name"`
+	Exchange string `json:"exchange"`
+	Type     string `json:"type"`
+	Currency string `json:"currency"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Tick is the latest price of a symbol pushed to the streaming clients.
+// Change is the intraday change in percent, zero if the previous close is
+// unknown. Id grows with every change, so clients can resume from the last
+// tick they have seen.
+type Tick struct {
+	Id     uint64
+	Symbol string
+	Price  float64
+	Change float64
+	Time   time.Time
+	Bar    Bar
+}
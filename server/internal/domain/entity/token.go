@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// RefreshToken is the stored state of an issued refresh token. Every token
+// rotated from the same sign-in belongs to one family, a Rotated token was
+// already exchanged and a token of a Revoked family is no longer accepted.
+type RefreshToken struct {
+	UserId  int
+	Family  string
+	Rotated bool
+	Revoked bool
+}
+
+// Session is a sign-in of the user, its id is the family of the refresh
+// tokens issued for it.
+type Session struct {
+	Id         string
+	UserId     int
+	UserAgent  string
+	IP         string
+	CreatedAt  time.Time
+	LastUsedAt time.Time
+}
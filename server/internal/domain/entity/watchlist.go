@@ -0,0 +1,11 @@
+package entity
+
+import "time"
+
+type Watchlist struct {
+	Id       int
+	UserId   int
+	Name     string
+	Symbols  []string
+	CreateAt time.Time
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/mail"
+	"net/url"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/netguard"
+)
+
+const (
+	maxUserAlerts  = 100
+	maxAlertTarget = 320
+)
+
+type AlertStorage interface {
+	Insert(ctx context.Context, alert entity.Alert) (entity.Alert, error)
+	FindAll(ctx context.Context, userId int) ([]entity.Alert, error)
+	Active(ctx context.Context, symbol string) ([]entity.Alert, error)
+	Delete(ctx context.Context, userId int, id int) error
+	Fire(ctx context.Context, id int, firedAt time.Time) (bool, error)
+	Rearm(ctx context.Context, id int) error
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, event entity.AlertEvent) error
+}
+
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+type alertService struct {
+	logger   *logging.Logger
+	storage  AlertStorage
+	channels []entity.AlertChannel
+	resolver Resolver
+}
+
+// NewAlertService returns the service managing the alerts of the users.
+// Alerts can be delivered only through the configured channels and webhooks
+// only to public addresses.
+func NewAlertService(logger *logging.Logger, storage AlertStorage, channels []entity.AlertChannel, resolver Resolver) *alertService {
+	return &alertService{logger: logger, storage: storage, channels: channels, resolver: resolver}
+}
+
+func (a *alertService) Create(ctx context.Context, alert entity.Alert) (entity.Alert, error) {
+	symbol, err := watchlistSymbol(alert.Symbol)
+	if err != nil {
+		return entity.Alert{}, err
+	}
+	alert.Symbol = symbol
+	switch alert.Condition {
+	case entity.CrossAbove, entity.CrossBelow, entity.RisePercent, entity.DropPercent:
+	default:
+		return entity.Alert{}, errs.New(errs.Validation, errs.Code("unsupported alert condition"), errs.Parameter("condition"))
+	}
+	if alert.Threshold <= 0 {
+		return entity.Alert{}, errs.New(errs.Validation, errs.Code("threshold must be positive"), errs.Parameter("threshold"))
+	}
+	if err := a.validateTarget(ctx, alert.Channel, alert.Target); err != nil {
+		return entity.Alert{}, err
+	}
+	alerts, err := a.storage.FindAll(ctx, alert.UserId)
+	if err != nil {
+		return entity.Alert{}, err
+	}
+	if len(alerts) >= maxUserAlerts {
+		return entity.Alert{}, errs.New(errs.Validation, errs.Code("too many alerts"), errs.Parameter("symbol"))
+	}
+	return a.storage.Insert(ctx, alert)
+}
+
+func (a *alertService) GetAll(ctx context.Context, userId int) ([]entity.Alert, error) {
+	return a.storage.FindAll(ctx, userId)
+}
+
+func (a *alertService) Delete(ctx context.Context, userId int, id int) error {
+	return a.storage.Delete(ctx, userId, id)
+}
+
+func (a *alertService) validateTarget(ctx context.Context, channel entity.AlertChannel, target string) error {
+	supported := false
+	for _, c := range a.channels {
+		supported = supported || c == channel
+	}
+	if !supported {
+		return errs.New(errs.Validation, errs.Code("unsupported alert channel"), errs.Parameter("channel"))
+	}
+	if target == "" || len(target) > maxAlertTarget {
+		return errs.New(errs.Validation, errs.Code("incorrect alert target"), errs.Parameter("target"))
+	}
+	switch channel {
+	case entity.Webhook:
+		u, err := url.Parse(target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errs.New(errs.Validation, errs.Code("webhook target must be an http url"), errs.Parameter("target"))
+		}
+		return a.validateHost(ctx, u.Hostname())
+	case entity.Email:
+		if _, err := mail.ParseAddress(target); err != nil {
+			return errs.New(errs.Validation, errs.Code("incorrect email address"), errs.Parameter("target"))
+		}
+	}
+	return nil
+}
+
+// validateHost rejects the webhook hosts that resolve to an address inside
+// the network. The notifier checks the address again when it connects.
+func (a *alertService) validateHost(ctx context.Context, host string) error {
+	addrs := []net.IPAddr{{IP: net.ParseIP(host)}}
+	if addrs[0].IP == nil {
+		var err error
+		addrs, err = a.resolver.LookupIPAddr(ctx, host)
+		if err != nil || len(addrs) == 0 {
+			return errs.New(errs.Validation, errs.Code("webhook host cannot be resolved"), errs.Parameter("target"))
+		}
+	}
+	for _, addr := range addrs {
+		if !netguard.Public(addr.IP) {
+			return errs.New(errs.Validation, errs.Code("webhook target must be a public address"), errs.Parameter("target"))
+		}
+	}
+	return nil
+}
+
+// evaluate reports whether the armed alert fires at the price and whether a
+// disarmed one must be armed again. The alert is armed again only after the
+// price has moved back past the threshold by the hysteresis share of it, so
+// a price oscillating around the threshold doesn't flood the user.
+func evaluate(alert entity.Alert, q quote, hysteresis float64) (fire bool, rearm bool) {
+	var distance float64
+	switch alert.Condition {
+	case entity.CrossAbove:
+		distance = q.price - alert.Threshold
+	case entity.CrossBelow:
+		distance = alert.Threshold - q.price
+	case entity.RisePercent, entity.DropPercent:
+		if q.previousClose <= 0 {
+			return false, false
+		}
+		change := intradayChange(q)
+		if alert.Condition == entity.DropPercent {
+			change = -change
+		}
+		distance = change - alert.Threshold
+	default:
+		return false, false
+	}
+	if alert.Armed {
+		return distance >= 0, false
+	}
+	return false, alert.Rearm && distance <= -alert.Threshold*hysteresis
+}
+
+func intradayChange(q quote) float64 {
+	if q.previousClose <= 0 {
+		return 0
+	}
+	return (q.price - q.previousClose) / q.previousClose * 100
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const notifyTimeout = 30 * time.Second
+
+type alertMonitor struct {
+	logger     *logging.Logger
+	storage    AlertStorage
+	notifiers  map[entity.AlertChannel]Notifier
+	hysteresis float64
+	now        func() time.Time
+
+	quotes *quoteQueue
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewAlertMonitor returns the evaluator of the alerts. It receives fresh
+// charts as a ChartListener and delivers the fired alerts through the
+// notifier of their channel.
+func NewAlertMonitor(logger *logging.Logger, storage AlertStorage, notifiers map[entity.AlertChannel]Notifier, hysteresis float64) *alertMonitor {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &alertMonitor{
+		logger:     logger,
+		storage:    storage,
+		notifiers:  notifiers,
+		hysteresis: hysteresis,
+		now:        time.Now,
+		quotes:     newQuoteQueue(),
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// OnChart queues the price of the chart. The percent alerts compare it with
+// the previous close, which is taken only from the daily charts.
+func (a *alertMonitor) OnChart(query entity.ChartQuery, series entity.Series) {
+	if !query.Daily() {
+		series.PreviousClose = 0
+	}
+	a.quotes.push(query.Symbol, series)
+}
+
+func (a *alertMonitor) Start() {
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		for {
+			select {
+			case <-a.ctx.Done():
+				return
+			case <-a.quotes.wake:
+				a.round()
+			}
+		}
+	}()
+}
+
+// Close stops the monitor after the current round.
+func (a *alertMonitor) Close() error {
+	a.cancel()
+	a.wg.Wait()
+	return nil
+}
+
+func (a *alertMonitor) round() {
+	for symbol, q := range a.quotes.drain() {
+		if a.ctx.Err() != nil {
+			return
+		}
+		a.evaluateSymbol(symbol, q)
+	}
+}
+
+func (a *alertMonitor) evaluateSymbol(symbol string, q quote) {
+	alerts, err := a.storage.Active(a.ctx, symbol)
+	if err != nil {
+		a.logger.Errorf("cannot load alerts of %v due to : %v", symbol, err)
+		return
+	}
+	for _, alert := range alerts {
+		fire, rearm := evaluate(alert, q, a.hysteresis)
+		if rearm {
+			if err := a.storage.Rearm(a.ctx, alert.Id); err != nil {
+				a.logger.Errorf("cannot rearm alert %v due to : %v", alert.Id, err)
+			}
+			continue
+		}
+		if fire {
+			a.fire(alert, q)
+		}
+	}
+}
+
+func (a *alertMonitor) fire(alert entity.Alert, q quote) {
+	firedAt := a.now()
+	fired, err := a.storage.Fire(a.ctx, alert.Id, firedAt)
+	if err != nil {
+		a.logger.Errorf("cannot fire alert %v due to : %v", alert.Id, err)
+		return
+	}
+	if !fired {
+		return
+	}
+	notifier, ok := a.notifiers[alert.Channel]
+	if !ok {
+		a.logger.Errorf("alert %v fired but channel %v is not configured", alert.Id, alert.Channel)
+		return
+	}
+	ctx, cancel := context.WithTimeout(a.ctx, notifyTimeout)
+	defer cancel()
+	event := entity.AlertEvent{Alert: alert, Price: q.price, Change: intradayChange(q), FiredAt: firedAt}
+	if err := notifier.Notify(ctx, event); err != nil {
+		a.logger.Errorf("cannot deliver alert %v through %v due to : %v", alert.Id, alert.Channel, err)
+		return
+	}
+	a.logger.Infof("alert %v of %v fired at %v", alert.Id, alert.Symbol, q.price)
+}
@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluate(t *testing.T) {
+	testCases := []struct {
+		title     string
+		alert     entity.Alert
+		quote     quote
+		wantFire  bool
+		wantRearm bool
+	}{
+		{
+			title:    "armed cross above fires over the threshold",
+			alert:    entity.Alert{Condition: entity.CrossAbove, Threshold: 200, Armed: true},
+			quote:    quote{price: 201},
+			wantFire: true,
+		},
+		{
+			title: "armed cross above waits under the threshold",
+			alert: entity.Alert{Condition: entity.CrossAbove, Threshold: 200, Armed: true},
+			quote: quote{price: 199},
+		},
+		{
+			title:    "armed cross below fires under the threshold",
+			alert:    entity.Alert{Condition: entity.CrossBelow, Threshold: 100, Armed: true},
+			quote:    quote{price: 99.5},
+			wantFire: true,
+		},
+		{
+			title: "fired alert isn't rearmed inside the hysteresis band",
+			alert: entity.Alert{Condition: entity.CrossAbove, Threshold: 200, Rearm: true},
+			quote: quote{price: 199},
+		},
+		{
+			title:     "fired alert is rearmed out of the hysteresis band",
+			alert:     entity.Alert{Condition: entity.CrossAbove, Threshold: 200, Rearm: true},
+			quote:     quote{price: 197},
+			wantRearm: true,
+		},
+		{
+			title: "one shot alert is never rearmed",
+			alert: entity.Alert{Condition: entity.CrossAbove, Threshold: 200},
+			quote: quote{price: 150},
+		},
+		{
+			title:    "armed drop percent fires on intraday drop",
+			alert:    entity.Alert{Condition: entity.DropPercent, Threshold: 5, Armed: true},
+			quote:    quote{price: 94, previousClose: 100},
+			wantFire: true,
+		},
+		{
+			title: "armed drop percent ignores intraday rise",
+			alert: entity.Alert{Condition: entity.DropPercent, Threshold: 5, Armed: true},
+			quote: quote{price: 106, previousClose: 100},
+		},
+		{
+			title:    "armed rise percent fires on intraday rise",
+			alert:    entity.Alert{Condition: entity.RisePercent, Threshold: 5, Armed: true},
+			quote:    quote{price: 106, previousClose: 100},
+			wantFire: true,
+		},
+		{
+			title: "percent alert waits for the previous close",
+			alert: entity.Alert{Condition: entity.DropPercent, Threshold: 5, Armed: true},
+			quote: quote{price: 10},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			fire, rearm := evaluate(test.alert, test.quote, 0.01)
+			assert.Equal(t, test.wantFire, fire)
+			assert.Equal(t, test.wantRearm, rearm)
+		})
+	}
+}
+
+func TestCreateAlert(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockAlertStorage(cntr)
+	resolver := mocks.NewMockResolver(cntr)
+	alertService := NewAlertService(logging.GetLogger("debug"), storage, []entity.AlertChannel{entity.Webhook}, resolver)
+	public := []net.IPAddr{{IP: net.ParseIP("93.184.216.34")}}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		alert    entity.Alert
+		mockCall mockCall
+		isError  bool
+	}{
+		{
+			title: "webhook alert is created",
+			alert: entity.Alert{UserId: 1, Symbol: " aapl ", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "https://example.com/hook"},
+			mockCall: func() {
+				resolver.EXPECT().LookupIPAddr(gomock.Any(), "example.com").Return(public, nil)
+				storage.EXPECT().FindAll(gomock.Any(), 1).Return([]entity.Alert{}, nil)
+				storage.EXPECT().Insert(gomock.Any(), entity.Alert{
+					UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "https://example.com/hook",
+				}).Return(entity.Alert{Id: 1}, nil)
+			},
+		},
+		{
+			title:    "unconfigured channel",
+			alert:    entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Email, Target: "user@example.com"},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "webhook target isn't an http url",
+			alert:    entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "file:///etc/passwd"},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "webhook target is the metadata address",
+			alert:    entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "http://169.254.169.254/latest/meta-data"},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title: "webhook host resolves to a private address",
+			alert: entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "https://internal.example.com/hook"},
+			mockCall: func() {
+				resolver.EXPECT().LookupIPAddr(gomock.Any(), "internal.example.com").Return(append(public, net.IPAddr{IP: net.ParseIP("10.0.0.5")}), nil)
+			},
+			isError: true,
+		},
+		{
+			title: "webhook host cannot be resolved",
+			alert: entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "https://missing.example.com/hook"},
+			mockCall: func() {
+				resolver.EXPECT().LookupIPAddr(gomock.Any(), "missing.example.com").Return(nil, errors.New("no such host"))
+			},
+			isError: true,
+		},
+		{
+			title:    "unsupported condition",
+			alert:    entity.Alert{UserId: 1, Symbol: "AAPL", Condition: "equals", Threshold: 200, Channel: entity.Webhook, Target: "https://example.com"},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "negative threshold",
+			alert:    entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.DropPercent, Threshold: -5, Channel: entity.Webhook, Target: "https://example.com"},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title: "too many alerts",
+			alert: entity.Alert{UserId: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Target: "https://example.com"},
+			mockCall: func() {
+				resolver.EXPECT().LookupIPAddr(gomock.Any(), "example.com").Return(public, nil)
+				storage.EXPECT().FindAll(gomock.Any(), 1).Return(make([]entity.Alert, maxUserAlerts), nil)
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			_, err := alertService.Create(context.Background(), test.alert)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAlertMonitorRound(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockAlertStorage(cntr)
+	notifier := mocks.NewMockNotifier(cntr)
+	monitor := NewAlertMonitor(logging.GetLogger("debug"), storage, map[entity.AlertChannel]Notifier{entity.Webhook: notifier}, 0.01)
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+	above := entity.Alert{Id: 1, Symbol: "AAPL", Condition: entity.CrossAbove, Threshold: 200, Channel: entity.Webhook, Armed: true, Active: true}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		price    float64
+		mockCall mockCall
+	}{
+		{
+			title: "fired alert is delivered",
+			price: 210,
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{above}, nil)
+				storage.EXPECT().Fire(gomock.Any(), 1, now).Return(true, nil)
+				notifier.EXPECT().Notify(gomock.Any(), entity.AlertEvent{Alert: above, Price: 210, FiredAt: now}).Return(nil)
+			},
+		},
+		{
+			title: "alert fired by another instance isn't delivered again",
+			price: 210,
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{above}, nil)
+				storage.EXPECT().Fire(gomock.Any(), 1, now).Return(false, nil)
+			},
+		},
+		{
+			title: "disarmed alert is rearmed",
+			price: 190,
+			mockCall: func() {
+				disarmed := above
+				disarmed.Armed, disarmed.Rearm = false, true
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{disarmed}, nil)
+				storage.EXPECT().Rearm(gomock.Any(), 1).Return(nil)
+			},
+		},
+		{
+			title: "delivery failure is logged",
+			price: 210,
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{above}, nil)
+				storage.EXPECT().Fire(gomock.Any(), 1, now).Return(true, nil)
+				notifier.EXPECT().Notify(gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			monitor.OnChart(entity.ChartQuery{Symbol: "AAPL"}, priceChart(test.price).Series)
+			monitor.round()
+		})
+	}
+}
+
+func TestAlertMonitorPercent(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockAlertStorage(cntr)
+	notifier := mocks.NewMockNotifier(cntr)
+	monitor := NewAlertMonitor(logging.GetLogger("debug"), storage, map[entity.AlertChannel]Notifier{entity.Webhook: notifier}, 0.01)
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+	rise := entity.Alert{Id: 1, Symbol: "AAPL", Condition: entity.RisePercent, Threshold: 5, Channel: entity.Webhook, Armed: true, Active: true}
+	daily := entity.Series{Symbol: "AAPL", Price: 110, PreviousClose: 108}
+	yearly := entity.Series{Symbol: "AAPL", Price: 110, PreviousClose: 80}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		charts   map[entity.ChartQuery]entity.Series
+		mockCall mockCall
+	}{
+		{
+			title:  "close before the range of a yearly chart doesn't fire",
+			charts: map[entity.ChartQuery]entity.Series{{Symbol: "AAPL", Range: "1y"}: yearly},
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{rise}, nil)
+			},
+		},
+		{
+			title:  "daily chart below the threshold doesn't fire",
+			charts: map[entity.ChartQuery]entity.Series{{Symbol: "AAPL"}: daily, {Symbol: "AAPL", Range: "1y"}: yearly},
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{rise}, nil)
+			},
+		},
+		{
+			title:  "daily chart above the threshold fires",
+			charts: map[entity.ChartQuery]entity.Series{{Symbol: "AAPL", Range: "1d"}: {Symbol: "AAPL", Price: 110, PreviousClose: 100}},
+			mockCall: func() {
+				storage.EXPECT().Active(gomock.Any(), "AAPL").Return([]entity.Alert{rise}, nil)
+				storage.EXPECT().Fire(gomock.Any(), 1, now).Return(true, nil)
+				notifier.EXPECT().Notify(gomock.Any(), entity.AlertEvent{Alert: rise, Price: 110, Change: 10, FiredAt: now}).Return(nil)
+			},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			for query, series := range test.charts {
+				monitor.OnChart(query, series)
+			}
+			monitor.round()
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+// defaultInterval is the interval the providers use when none is requested.
+const defaultInterval = "1d"
+
+type BarStorage interface {
+	Upsert(ctx context.Context, symbol string, interval string, bars []entity.Bar) error
+	Find(ctx context.Context, symbol string, interval string, from time.Time, to time.Time) ([]entity.Bar, error)
+}
+
+type barService struct {
+	logger  *logging.Logger
+	storage BarStorage
+}
+
+func NewBarService(logger *logging.Logger, storage BarStorage) *barService {
+	return &barService{logger: logger, storage: storage}
+}
+
+// Record stores the bars of a fetched chart. Missing bars are skipped.
+func (b *barService) Record(ctx context.Context, query entity.ChartQuery, series entity.Series) error {
+	if len(query.Symbol) == 0 {
+		return errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	interval := intervalOf(query)
+	bars := make([]entity.Bar, 0, len(series.Bars))
+	for _, bar := range series.Bars {
+		if bar.Missing {
+			continue
+		}
+		bar.Symbol = query.Symbol
+		bar.Interval = interval
+		bars = append(bars, bar)
+	}
+	b.logger.Debugf("record %v bars of chart = %v", len(bars), query.Key())
+	return b.storage.Upsert(ctx, query.Symbol, interval, bars)
+}
+
+// History returns the stored bars between period1 and period2 of the query,
+// period2 defaults to now.
+func (b *barService) History(ctx context.Context, query entity.ChartQuery) ([]entity.Bar, error) {
+	if len(query.Symbol) == 0 {
+		return nil, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	to := time.Now()
+	if query.Period2 != 0 {
+		to = time.Unix(query.Period2, 0)
+	}
+	from := time.Unix(query.Period1, 0)
+	if !from.Before(to) {
+		return nil, errs.New(errs.Validation, errs.Code("period1 must be before period2"), errs.Parameter("period1"))
+	}
+	return b.storage.Find(ctx, query.Symbol, intervalOf(query), from, to)
+}
+
+func intervalOf(query entity.ChartQuery) string {
+	if query.Interval == "" {
+		return defaultInterval
+	}
+	return query.Interval
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBarRecord(t *testing.T) {
+	cntr := gomock.NewController(t)
+	barStorage := mocks.NewMockBarStorage(cntr)
+	barService := NewBarService(logging.GetLogger("debug"), barStorage)
+	chart := entity.Series{Symbol: "AAPL", Bars: []entity.Bar{
+		{Timestamp: time.Unix(100, 0).UTC(), Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+		{Timestamp: time.Unix(200, 0).UTC(), Missing: true},
+		{Timestamp: time.Unix(300, 0).UTC(), Open: 3, High: 4, Low: 2.5, Close: 3.5},
+	}}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    entity.ChartQuery
+		isError  bool
+	}{
+		{
+			title: "missing bars are skipped and default interval is used",
+			mockCall: func() {
+				barStorage.EXPECT().Upsert(gomock.Any(), "AAPL", "1d", []entity.Bar{
+					{Symbol: "AAPL", Interval: "1d", Timestamp: time.Unix(100, 0).UTC(), Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+					{Symbol: "AAPL", Interval: "1d", Timestamp: time.Unix(300, 0).UTC(), Open: 3, High: 4, Low: 2.5, Close: 3.5},
+				}).Return(nil)
+			},
+			input: entity.ChartQuery{Symbol: "AAPL"},
+		},
+		{
+			title: "requested interval is stored",
+			mockCall: func() {
+				barStorage.EXPECT().Upsert(gomock.Any(), "AAPL", "5m", gomock.Len(2)).Return(nil)
+			},
+			input: entity.ChartQuery{Symbol: "AAPL", Interval: "5m"},
+		},
+		{
+			title: "storage error is returned",
+			mockCall: func() {
+				barStorage.EXPECT().Upsert(gomock.Any(), "AAPL", "1d", gomock.Any()).Return(errors.New("db error"))
+			},
+			input:   entity.ChartQuery{Symbol: "AAPL"},
+			isError: true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    entity.ChartQuery{},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			err := barService.Record(context.Background(), test.input, chart)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestBarHistory(t *testing.T) {
+	cntr := gomock.NewController(t)
+	barStorage := mocks.NewMockBarStorage(cntr)
+	barService := NewBarService(logging.GetLogger("debug"), barStorage)
+	bars := []entity.Bar{{Symbol: "AAPL", Interval: "1d", Timestamp: time.Unix(150, 0), Close: 1}}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    entity.ChartQuery
+		want     []entity.Bar
+		isError  bool
+	}{
+		{
+			title: "bars of the period are returned",
+			mockCall: func() {
+				barStorage.EXPECT().Find(gomock.Any(), "AAPL", "1d", time.Unix(100, 0), time.Unix(200, 0)).Return(bars, nil)
+			},
+			input: entity.ChartQuery{Symbol: "AAPL", Period1: 100, Period2: 200},
+			want:  bars,
+		},
+		{
+			title:    "inverted period and return error",
+			mockCall: func() {},
+			input:    entity.ChartQuery{Symbol: "AAPL", Period1: 200, Period2: 100},
+			isError:  true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    entity.ChartQuery{Period1: 100},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := barService.History(context.Background(), test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
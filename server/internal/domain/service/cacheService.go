@@ -3,6 +3,7 @@ package service
 import (
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 )
@@ -10,6 +11,7 @@ import (
 type StockStorage interface {
 	Set(symbol string, stockInfo string, expireAt time.Duration) error
 	Get(symbol string) (string, error)
+	GetMany(keys []string) (map[string]string, error)
 }
 
 type cacheService struct {
@@ -21,20 +23,33 @@ func NewCacheService(logger *logging.Logger, storage StockStorage) *cacheService
 	return &cacheService{logger: logger, storage: storage}
 }
 
-func (cs *cacheService) Save(symbol string, stockInfo string, duration time.Duration) error {
-	if len(symbol) == 0 {
+func (cs *cacheService) Save(query entity.ChartQuery, stockInfo string, duration time.Duration) error {
+	if len(query.Symbol) == 0 {
 		return errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
 	}
 	if len(stockInfo) == 0 {
 		return errs.New(errs.Validation, errs.Code("stock info is empty"), errs.Parameter("stockInfo"))
 	}
-	return cs.storage.Set(symbol, stockInfo, duration)
+	return cs.storage.Set(query.Key(), stockInfo, duration)
 }
 
-func (cs *cacheService) Get(symbol string) (string, error) {
-	cs.logger.Infof("try to get from cache symbol = %v", symbol)
-	if len(symbol) == 0 {
+func (cs *cacheService) Get(query entity.ChartQuery) (string, error) {
+	cs.logger.Infof("try to get from cache chart = %v", query.Key())
+	if len(query.Symbol) == 0 {
 		return "", errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
 	}
-	return cs.storage.Get(symbol)
+	return cs.storage.Get(query.Key())
+}
+
+// GetMany returns the cached charts keyed by ChartQuery.Key, queries without
+// a cached chart are absent from the result.
+func (cs *cacheService) GetMany(queries []entity.ChartQuery) (map[string]string, error) {
+	keys := make([]string, 0, len(queries))
+	for _, query := range queries {
+		if len(query.Symbol) == 0 {
+			return nil, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+		}
+		keys = append(keys, query.Key())
+	}
+	return cs.storage.GetMany(keys)
 }
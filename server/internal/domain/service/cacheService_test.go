@@ -4,6 +4,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/golang/mock/gomock"
@@ -16,7 +17,7 @@ func TestCahceSave(t *testing.T) {
 	cacheService := NewCacheService(logging.GetLogger("debug"), stockStorage)
 	type mockCall func()
 	type input struct {
-		symbol    string
+		query     entity.ChartQuery
 		stockInfo string
 		duration  time.Duration
 	}
@@ -29,30 +30,30 @@ func TestCahceSave(t *testing.T) {
 		{
 			title: "success cache save",
 			mockCall: func() {
-				stockStorage.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				stockStorage.EXPECT().Set("test:1d:1m:0:0", gomock.Any(), gomock.Any()).Return(nil)
 			},
-			input:   input{symbol: "test", stockInfo: "testInfo", duration: 1 * time.Minute},
+			input:   input{query: entity.ChartQuery{Symbol: "test", Range: "1d", Interval: "1m"}, stockInfo: "testInfo", duration: 1 * time.Minute},
 			isError: false,
 		},
 		{
 			title: "empty symbol and return error",
 			mockCall: func() {
 			},
-			input:   input{symbol: "", stockInfo: "testInfo", duration: 1 * time.Minute},
+			input:   input{query: entity.ChartQuery{}, stockInfo: "testInfo", duration: 1 * time.Minute},
 			isError: true,
 		},
 		{
 			title: "empty stock info and return error",
 			mockCall: func() {
 			},
-			input:   input{symbol: "test", stockInfo: "", duration: 1 * time.Minute},
+			input:   input{query: entity.ChartQuery{Symbol: "test"}, stockInfo: "", duration: 1 * time.Minute},
 			isError: true,
 		},
 	}
 	for _, test := range testCases {
 		t.Run(test.title, func(t *testing.T) {
 			test.mockCall()
-			err := cacheService.Save(test.input.symbol, test.input.stockInfo, test.input.duration)
+			err := cacheService.Save(test.input.query, test.input.stockInfo, test.input.duration)
 			if test.isError {
 				assert.Error(t, err)
 			} else {
@@ -72,16 +73,16 @@ func TestCahceGet(t *testing.T) {
 	testCases := []struct {
 		title    string
 		mockCall mockCall
-		input    string
+		input    entity.ChartQuery
 		isError  bool
 		want     string
 	}{
 		{
 			title: "success get from cache",
 			mockCall: func() {
-				stockStorage.EXPECT().Get(gomock.Any()).Return("test info", nil)
+				stockStorage.EXPECT().Get("test:5y:1wk:0:0").Return("test info", nil)
 			},
-			input:   "test symbol",
+			input:   entity.ChartQuery{Symbol: "test", Range: "5y", Interval: "1wk"},
 			want:    "test info",
 			isError: false,
 		},
@@ -89,7 +90,7 @@ func TestCahceGet(t *testing.T) {
 			title: "empty symbol and return error",
 			mockCall: func() {
 			},
-			input:   "",
+			input:   entity.ChartQuery{},
 			isError: true,
 		},
 	}
@@ -107,3 +108,46 @@ func TestCahceGet(t *testing.T) {
 		})
 	}
 }
+
+func TestCahceGetMany(t *testing.T) {
+	cntr := gomock.NewController(t)
+	stockStorage := mocks.NewMockStockStorage(cntr)
+	cacheService := NewCacheService(logging.GetLogger("debug"), stockStorage)
+	type mockCall func()
+
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    []entity.ChartQuery
+		isError  bool
+		want     map[string]string
+	}{
+		{
+			title: "success get many from cache",
+			mockCall: func() {
+				stockStorage.EXPECT().GetMany([]string{"a:1d::0:0", "b:1d::0:0"}).Return(map[string]string{"a:1d::0:0": "a info"}, nil)
+			},
+			input:   []entity.ChartQuery{{Symbol: "a", Range: "1d"}, {Symbol: "b", Range: "1d"}},
+			want:    map[string]string{"a:1d::0:0": "a info"},
+			isError: false,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    []entity.ChartQuery{{Symbol: "a"}, {}},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := cacheService.GetMany(test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.Equal(t, test.want, got)
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
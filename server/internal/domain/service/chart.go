@@ -0,0 +1,427 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+// cacheVersion changes with the model of the cached chart.
+const cacheVersion = 2
+
+const (
+	freshFor       = 60 * time.Second
+	closedFreshFor = 600 * time.Second
+	staleFor       = time.Hour
+	batchWorkers   = 8
+	recordQueue    = 256
+	recordTimeout  = 10 * time.Second
+	// historyGap is the longest gap between recorded daily bars, a weekend
+	// followed by a holiday.
+	historyGap  = 4 * 24 * time.Hour
+	leaseWait   = 2 * time.Second
+	leasePoll   = 100 * time.Millisecond
+	leasePrefix = "chart:"
+)
+
+type ChartCache interface {
+	Save(query entity.ChartQuery, stockInfo string, duration time.Duration) error
+	Get(query entity.ChartQuery) (string, error)
+	GetMany(queries []entity.ChartQuery) (map[string]string, error)
+}
+
+type ChartFetcher interface {
+	GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error)
+}
+
+// ChartRecorder keeps the bars of the fetched charts.
+type ChartRecorder interface {
+	Record(ctx context.Context, query entity.ChartQuery, chart entity.Series) error
+	History(ctx context.Context, query entity.ChartQuery) ([]entity.Bar, error)
+}
+
+// ChartLease elects the replica that fetches a chart, so that the replicas
+// sharing the cache don't fetch the same chart from the providers at once.
+type ChartLease interface {
+	Campaign(ctx context.Context, key string) bool
+	Resign(key string)
+}
+
+// ChartListener is notified about every chart fetched from the providers.
+// OnChart is called synchronously and must not block.
+type ChartListener interface {
+	OnChart(query entity.ChartQuery, chart entity.Series)
+}
+
+type MarketCalendar interface {
+	Status(symbol string, exchangeName string, t time.Time) calendar.Status
+}
+
+type chartService struct {
+	logger     *logging.Logger
+	cache      ChartCache
+	fetcher    ChartFetcher
+	recorder   ChartRecorder
+	listeners  []ChartListener
+	lease      ChartLease
+	calendar   MarketCalendar
+	now        func() time.Time
+	refreshing sync.Map
+	mu         sync.Mutex
+	closing    bool
+	closed     bool
+	wg         sync.WaitGroup
+	records    chan record
+	pending    sync.WaitGroup
+	recording  sync.WaitGroup
+}
+
+type record struct {
+	query entity.ChartQuery
+	chart entity.Series
+}
+
+// NewChartService returns a stale-while-revalidate chart service. A chart is
+// served from the cache while it is fresh, after that it is served as stale
+// while a background refresh runs, until the cache entry itself expires.
+// Charts of closed markets stay fresh until the next session opens. Every
+// fetched chart is passed to the recorder in the background to keep its
+// history, and daily charts of past periods are served from it.
+func NewChartService(logger *logging.Logger, cache ChartCache, fetcher ChartFetcher, recorder ChartRecorder, calendar MarketCalendar) *chartService {
+	c := &chartService{
+		logger:   logger,
+		cache:    cache,
+		fetcher:  fetcher,
+		recorder: recorder,
+		calendar: calendar,
+		now:      time.Now,
+		records:  make(chan record, recordQueue),
+	}
+	c.recording.Add(1)
+	go c.record()
+	return c
+}
+
+func (c *chartService) Get(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	if len(query.Symbol) == 0 {
+		return entity.ChartResult{}, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	stockInfo, err := c.cache.Get(query)
+	if err == nil {
+		if result, ok := c.fromCache(query, stockInfo); ok {
+			return result, nil
+		}
+	}
+	return c.load(ctx, query)
+}
+
+// GetMany reads all cached charts with a single cache call and fetches the
+// missing ones concurrently. Failures are reported per query in ChartResult.Err.
+func (c *chartService) GetMany(ctx context.Context, queries []entity.ChartQuery) map[string]entity.ChartResult {
+	results := make(map[string]entity.ChartResult, len(queries))
+	cached, err := c.cache.GetMany(queries)
+	if err != nil {
+		c.logger.Errorf("cannot read batch from cache due to : %v", err)
+		cached = map[string]string{}
+	}
+	missed := make([]entity.ChartQuery, 0, len(queries))
+	for _, query := range queries {
+		stockInfo, ok := cached[query.Key()]
+		if ok {
+			if result, ok := c.fromCache(query, stockInfo); ok {
+				results[query.Key()] = result
+				continue
+			}
+		}
+		missed = append(missed, query)
+	}
+
+	jobs := make(chan entity.ChartQuery)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	workers := batchWorkers
+	if len(missed) < workers {
+		workers = len(missed)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for query := range jobs {
+				result, err := c.load(ctx, query)
+				if err != nil {
+					c.logger.Errorf("cannot get chart = %v due to : %v", query.Key(), err)
+					result.Err = err
+				}
+				mu.Lock()
+				results[query.Key()] = result
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, query := range missed {
+		jobs <- query
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// Subscribe adds a listener of fresh charts, it must be called before the
+// service is used.
+func (c *chartService) Subscribe(listener ChartListener) {
+	c.listeners = append(c.listeners, listener)
+}
+
+// Coordinate makes the replicas take turns fetching charts, it must be called
+// before the service is used. On a cache miss the replica that doesn't hold
+// the lease of the chart waits for the holder to put it in the cache, and
+// stale charts are refreshed only by the holder.
+func (c *chartService) Coordinate(lease ChartLease) {
+	c.lease = lease
+}
+
+// Warm fetches the chart from the providers and stores it in the cache even
+// if the cached copy is still fresh.
+func (c *chartService) Warm(ctx context.Context, query entity.ChartQuery) error {
+	_, err := c.Latest(ctx, query)
+	return err
+}
+
+// Latest returns the chart fetched from the providers even if the cached copy
+// is still fresh, so that the price streams aren't held back by the cache.
+func (c *chartService) Latest(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	if len(query.Symbol) == 0 {
+		return entity.ChartResult{}, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	return c.fetch(ctx, query)
+}
+
+// Wait blocks until the background refreshes are finished and their charts
+// are recorded.
+func (c *chartService) Wait() {
+	c.wg.Wait()
+	c.pending.Wait()
+}
+
+// Close stops starting background refreshes and waits for the running ones
+// and for the queued charts to be recorded, so that they don't outlive the
+// cache and the database clients. The queue is closed only after the running
+// refreshes are done, so that their charts are recorded too.
+func (c *chartService) Close() error {
+	c.mu.Lock()
+	if c.closing {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closing = true
+	c.mu.Unlock()
+	c.wg.Wait()
+	c.mu.Lock()
+	c.closed = true
+	c.mu.Unlock()
+	close(c.records)
+	c.recording.Wait()
+	return nil
+}
+
+func (c *chartService) fromCache(query entity.ChartQuery, stockInfo string) (entity.ChartResult, bool) {
+	var cached entity.CachedChart
+	err := json.Unmarshal([]byte(stockInfo), &cached)
+	if err != nil {
+		c.logger.Errorf("cannot decode cached chart = %v due to : %v", query.Key(), err)
+		return entity.ChartResult{}, false
+	}
+	if cached.Version != cacheVersion {
+		return entity.ChartResult{}, false
+	}
+	if c.now().Unix() < cached.FreshUntil {
+		return entity.ChartResult{Series: cached.Series, Status: entity.CacheHit}, true
+	}
+	c.refresh(query)
+	return entity.ChartResult{Series: cached.Series, Status: entity.CacheStale}, true
+}
+
+// load serves the chart from the recorded history if it's complete and
+// fetches it from the providers otherwise.
+func (c *chartService) load(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	if chart, ok := c.fromHistory(ctx, query); ok {
+		return entity.ChartResult{Series: chart, Status: entity.CacheHit}, nil
+	}
+	return c.fetch(ctx, query)
+}
+
+// fromHistory returns the chart of a past period of daily bars from the
+// recorder. The recorded bars must cover the whole period without gaps longer
+// than a market holiday, so the chart isn't missing any sessions.
+func (c *chartService) fromHistory(ctx context.Context, query entity.ChartQuery) (entity.Series, bool) {
+	if query.Period1 == 0 || query.Period2 == 0 || intervalOf(query) != defaultInterval {
+		return entity.Series{}, false
+	}
+	from, to := time.Unix(query.Period1, 0), time.Unix(query.Period2, 0)
+	if !to.Before(c.now().Add(-24 * time.Hour)) {
+		return entity.Series{}, false
+	}
+	bars, err := c.recorder.History(ctx, query)
+	if err != nil {
+		c.logger.Errorf("cannot read history of chart = %v due to : %v", query.Key(), err)
+		return entity.Series{}, false
+	}
+	if len(bars) == 0 || bars[0].Timestamp.Sub(from) > historyGap || to.Sub(bars[len(bars)-1].Timestamp) > historyGap {
+		return entity.Series{}, false
+	}
+	for i := 1; i < len(bars); i++ {
+		if bars[i].Timestamp.Sub(bars[i-1].Timestamp) > historyGap {
+			return entity.Series{}, false
+		}
+	}
+	last := bars[len(bars)-1]
+	return entity.Series{Symbol: query.Symbol, Price: last.Close, PriceTime: last.Timestamp, Bars: bars}, true
+}
+
+func (c *chartService) fetch(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	if c.lease != nil {
+		if c.lease.Campaign(ctx, leasePrefix+query.Key()) {
+			defer c.lease.Resign(leasePrefix + query.Key())
+		} else if result, ok := c.await(ctx, query); ok {
+			return result, nil
+		}
+	}
+	chart, err := c.fetcher.GetChart(ctx, query)
+	if err != nil {
+		return entity.ChartResult{}, err
+	}
+	c.save(query, chart)
+	return entity.ChartResult{Series: chart, Status: entity.CacheMiss}, nil
+}
+
+// await waits for the replica that holds the lease to put the chart in the
+// cache. It gives up after leaseWait and the chart is fetched anyway.
+func (c *chartService) await(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, bool) {
+	timeout := time.NewTimer(leaseWait)
+	defer timeout.Stop()
+	poll := time.NewTicker(leasePoll)
+	defer poll.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return entity.ChartResult{}, false
+		case <-timeout.C:
+			c.logger.Infof("stop waiting for chart = %v from the lease holder", query.Key())
+			return entity.ChartResult{}, false
+		case <-poll.C:
+			stockInfo, err := c.cache.Get(query)
+			if err != nil {
+				continue
+			}
+			if result, ok := c.fromCache(query, stockInfo); ok {
+				return result, true
+			}
+		}
+	}
+}
+
+// refresh updates a stale chart in the background, at most one refresh per
+// chart is running. If the provider fails the stale chart stays in the cache.
+// With a lease only its holder refreshes the chart for all replicas.
+func (c *chartService) refresh(query entity.ChartQuery) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closing {
+		return
+	}
+	if _, running := c.refreshing.LoadOrStore(query.Key(), struct{}{}); running {
+		return
+	}
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		defer c.refreshing.Delete(query.Key())
+		if c.lease != nil {
+			if !c.lease.Campaign(context.Background(), leasePrefix+query.Key()) {
+				return
+			}
+			defer c.lease.Resign(leasePrefix + query.Key())
+		}
+		c.logger.Infof("refresh stale chart = %v", query.Key())
+		chart, err := c.fetcher.GetChart(context.Background(), query)
+		if err != nil {
+			c.logger.Errorf("cannot refresh chart = %v due to : %v", query.Key(), err)
+			return
+		}
+		c.save(query, chart)
+	}()
+}
+
+func (c *chartService) save(query entity.ChartQuery, chart entity.Series) {
+	for _, listener := range c.listeners {
+		listener.OnChart(query, chart)
+	}
+	c.logger.Infof("try to save in cache chart = %v", query.Key())
+	now := c.now()
+	fresh := c.freshFor(query, chart, now)
+	payload, err := json.Marshal(entity.CachedChart{
+		Version:    cacheVersion,
+		Series:     chart,
+		FetchedAt:  now.Unix(),
+		FreshUntil: now.Add(fresh).Unix(),
+	})
+	if err != nil {
+		c.logger.Errorf("cannot encode chart = %v due to : %v", query.Key(), err)
+		return
+	}
+	err = c.cache.Save(query, string(payload), fresh+staleFor)
+	if err != nil {
+		c.logger.Errorf("cannot save to cache due to : %v", err)
+	}
+	c.enqueue(query, chart)
+}
+
+// enqueue hands the chart to the recorder without waiting for the database,
+// the chart is dropped when the queue is full.
+func (c *chartService) enqueue(query entity.ChartQuery, chart entity.Series) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return
+	}
+	c.pending.Add(1)
+	select {
+	case c.records <- record{query: query, chart: chart}:
+	default:
+		c.pending.Done()
+		c.logger.Errorf("cannot record bars of chart = %v, the queue is full", query.Key())
+	}
+}
+
+func (c *chartService) record() {
+	defer c.recording.Done()
+	for r := range c.records {
+		ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+		if err := c.recorder.Record(ctx, r.query, r.chart); err != nil {
+			c.logger.Errorf("cannot record bars of chart = %v due to : %v", r.query.Key(), err)
+		}
+		cancel()
+		c.pending.Done()
+	}
+}
+
+func (c *chartService) freshFor(query entity.ChartQuery, chart entity.Series, now time.Time) time.Duration {
+	status := c.calendar.Status(query.Symbol, chart.Exchange, now)
+	if status.Open {
+		return freshFor
+	}
+	if status.NextOpen.IsZero() {
+		return closedFreshFor
+	}
+	if untilOpen := status.NextOpen.Sub(now); untilOpen > freshFor {
+		return untilOpen
+	}
+	return freshFor
+}
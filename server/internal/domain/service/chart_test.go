@@ -0,0 +1,583 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func cachedChart(t *testing.T, chart entity.Series, freshUntil time.Time) string {
+	b, err := json.Marshal(entity.CachedChart{Version: cacheVersion, Series: chart, FreshUntil: freshUntil.Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func newCalendar(t *testing.T) *calendar.Calendar {
+	c, err := calendar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestChartGet(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	// Monday, 11:00 in New York
+	now := time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC)
+	chartService.now = func() time.Time { return now }
+	query := entity.ChartQuery{Symbol: "TEST", Range: "1d"}
+	oldChart := entity.Series{Symbol: "TEST", Price: 1}
+	newChart := entity.Series{Symbol: "TEST", Price: 2}
+	type mockCall func()
+	testCases := []struct {
+		title      string
+		mockCall   mockCall
+		input      entity.ChartQuery
+		isError    bool
+		want       entity.Series
+		wantStatus entity.CacheStatus
+	}{
+		{
+			title: "fresh chart is served from cache",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return(cachedChart(t, oldChart, now.Add(time.Second)), nil)
+			},
+			input:      query,
+			want:       oldChart,
+			wantStatus: entity.CacheHit,
+		},
+		{
+			title: "stale chart is served and refreshed in background",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return(cachedChart(t, oldChart, now.Add(-time.Second)), nil)
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(newChart, nil)
+				chartCache.EXPECT().Save(query, gomock.Any(), freshFor+staleFor).DoAndReturn(
+					func(query entity.ChartQuery, stockInfo string, duration time.Duration) error {
+						var cached entity.CachedChart
+						assert.NoError(t, json.Unmarshal([]byte(stockInfo), &cached))
+						assert.Equal(t, newChart, cached.Series)
+						assert.Equal(t, now.Add(freshFor).Unix(), cached.FreshUntil)
+						return nil
+					})
+				chartRecorder.EXPECT().Record(gomock.Any(), query, newChart).Return(nil)
+			},
+			input:      query,
+			want:       oldChart,
+			wantStatus: entity.CacheStale,
+		},
+		{
+			title: "stale chart is served when the provider fails",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return(cachedChart(t, oldChart, now.Add(-time.Minute)), nil)
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+			},
+			input:      query,
+			want:       oldChart,
+			wantStatus: entity.CacheStale,
+		},
+		{
+			title: "missing chart is fetched and saved",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(newChart, nil)
+				chartCache.EXPECT().Save(query, gomock.Any(), freshFor+staleFor).Return(nil)
+				chartRecorder.EXPECT().Record(gomock.Any(), query, newChart).Return(nil)
+			},
+			input:      query,
+			want:       newChart,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "undecodable cache entry is refetched",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("{", nil)
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(newChart, nil)
+				chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+				chartRecorder.EXPECT().Record(gomock.Any(), query, newChart).Return(errors.New("db error"))
+			},
+			input:      query,
+			want:       newChart,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "cache entry of an older model is refetched",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return(`{"chart":{"result":[]},"fresh_until":99999999999}`, nil)
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(newChart, nil)
+				chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+				chartRecorder.EXPECT().Record(gomock.Any(), query, newChart).Return(nil)
+			},
+			input:      query,
+			want:       newChart,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "missing chart and provider failure return error",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+			},
+			input:   query,
+			isError: true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    entity.ChartQuery{},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := chartService.Get(context.Background(), test.input)
+			chartService.Wait()
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got.Series)
+				assert.Equal(t, test.wantStatus, got.Status)
+			}
+		})
+	}
+}
+
+func TestChartClose(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	now := time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC)
+	chartService.now = func() time.Time { return now }
+	query := entity.ChartQuery{Symbol: "TEST"}
+	stale := cachedChart(t, entity.Series{Symbol: "TEST", Price: 1}, now.Add(-time.Second))
+	chart := entity.Series{Symbol: "TEST", Price: 2}
+	started, release := make(chan struct{}), make(chan struct{})
+	chartCache.EXPECT().Get(query).Return(stale, nil).Times(2)
+	chartFetcher.EXPECT().GetChart(gomock.Any(), query).DoAndReturn(
+		func(context.Context, entity.ChartQuery) (entity.Series, error) {
+			close(started)
+			<-release
+			return chart, nil
+		})
+	chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+	// the chart of the refresh running at close is still recorded
+	recorded := make(chan struct{})
+	chartRecorder.EXPECT().Record(gomock.Any(), query, chart).DoAndReturn(
+		func(context.Context, entity.ChartQuery, entity.Series) error {
+			close(recorded)
+			return nil
+		})
+
+	_, err := chartService.Get(context.Background(), query)
+	assert.NoError(t, err)
+	<-started
+	closed := make(chan struct{})
+	go func() {
+		assert.NoError(t, chartService.Close())
+		close(closed)
+	}()
+	select {
+	case <-closed:
+		t.Fatal("close didn't wait for the refresh")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	<-closed
+	select {
+	case <-recorded:
+	default:
+		t.Fatal("close didn't wait for the chart of the refresh to be recorded")
+	}
+
+	// no refresh is started after close
+	got, err := chartService.Get(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CacheStale, got.Status)
+}
+
+func TestChartRecordInBackground(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	query := entity.ChartQuery{Symbol: "TEST"}
+	chart := entity.Series{Symbol: "TEST", Price: 2}
+	release := make(chan struct{})
+	chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+	chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+	chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+	chartRecorder.EXPECT().Record(gomock.Any(), query, chart).DoAndReturn(
+		func(ctx context.Context, _ entity.ChartQuery, _ entity.Series) error {
+			_, bounded := ctx.Deadline()
+			assert.True(t, bounded)
+			<-release
+			return nil
+		})
+
+	got, err := chartService.Get(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, chart, got.Series)
+	close(release)
+	assert.NoError(t, chartService.Close())
+}
+
+func TestChartLease(t *testing.T) {
+	query := entity.ChartQuery{Symbol: "TEST"}
+	key := "chart:" + query.Key()
+	chart := entity.Series{Symbol: "TEST", Price: 2}
+	testCases := []struct {
+		title    string
+		mockCall func(cache *mocks.MockChartCache, fetcher *mocks.MockChartFetcher, recorder *mocks.MockChartRecorder, lease *mocks.MockChartLease)
+		status   entity.CacheStatus
+	}{
+		{
+			title: "the holder fetches the chart",
+			mockCall: func(cache *mocks.MockChartCache, fetcher *mocks.MockChartFetcher, recorder *mocks.MockChartRecorder, lease *mocks.MockChartLease) {
+				cache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				lease.EXPECT().Campaign(gomock.Any(), key).Return(true)
+				fetcher.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+				cache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+				recorder.EXPECT().Record(gomock.Any(), query, chart).Return(nil)
+				lease.EXPECT().Resign(key)
+			},
+			status: entity.CacheMiss,
+		},
+		{
+			title: "other replicas wait for the holder",
+			mockCall: func(cache *mocks.MockChartCache, fetcher *mocks.MockChartFetcher, recorder *mocks.MockChartRecorder, lease *mocks.MockChartLease) {
+				gomock.InOrder(
+					cache.EXPECT().Get(query).Return("", errors.New("cache is empty")),
+					lease.EXPECT().Campaign(gomock.Any(), key).Return(false),
+					cache.EXPECT().Get(query).Return("", errors.New("cache is empty")),
+					cache.EXPECT().Get(query).Return(cachedChart(t, chart, time.Now().Add(time.Minute)), nil),
+				)
+			},
+			status: entity.CacheHit,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			cntr := gomock.NewController(t)
+			chartCache := mocks.NewMockChartCache(cntr)
+			chartFetcher := mocks.NewMockChartFetcher(cntr)
+			chartRecorder := mocks.NewMockChartRecorder(cntr)
+			chartLease := mocks.NewMockChartLease(cntr)
+			chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+			chartService.Coordinate(chartLease)
+			test.mockCall(chartCache, chartFetcher, chartRecorder, chartLease)
+			got, err := chartService.Get(context.Background(), query)
+			assert.NoError(t, err)
+			assert.Equal(t, chart, got.Series)
+			assert.Equal(t, test.status, got.Status)
+			assert.NoError(t, chartService.Close())
+		})
+	}
+}
+
+func TestChartRefreshByHolder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartLease := mocks.NewMockChartLease(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	chartService.Coordinate(chartLease)
+	query := entity.ChartQuery{Symbol: "TEST"}
+	chart := entity.Series{Symbol: "TEST", Price: 2}
+	chartCache.EXPECT().Get(query).Return(cachedChart(t, chart, time.Now().Add(-time.Minute)), nil)
+	chartLease.EXPECT().Campaign(gomock.Any(), "chart:"+query.Key()).Return(false)
+
+	got, err := chartService.Get(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, entity.CacheStale, got.Status)
+	chartService.Wait()
+	assert.NoError(t, chartService.Close())
+}
+
+func TestChartHistory(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	now := time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC)
+	chartService.now = func() time.Time { return now }
+	// from Monday to Monday, the bars of the weekend are missing
+	from, to := time.Date(2022, time.September, 26, 0, 0, 0, 0, time.UTC), time.Date(2022, time.October, 3, 0, 0, 0, 0, time.UTC)
+	query := entity.ChartQuery{Symbol: "TEST", Interval: "1d", Period1: from.Unix(), Period2: to.Unix()}
+	day := func(d int, close float64) entity.Bar {
+		return entity.Bar{Timestamp: time.Date(2022, time.September, d, 13, 30, 0, 0, time.UTC), Close: close}
+	}
+	week := []entity.Bar{day(26, 1), day(27, 2), day(28, 3), day(29, 4), day(30, 5)}
+	fetched := entity.Series{Symbol: "TEST", Price: 9}
+	fetch := func(query entity.ChartQuery) {
+		chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(fetched, nil)
+		chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+		chartRecorder.EXPECT().Record(gomock.Any(), query, fetched).Return(nil)
+	}
+	type mockCall func()
+	testCases := []struct {
+		title      string
+		mockCall   mockCall
+		input      entity.ChartQuery
+		want       entity.Series
+		wantStatus entity.CacheStatus
+	}{
+		{
+			title: "past period is served from the recorded bars",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartRecorder.EXPECT().History(gomock.Any(), query).Return(week, nil)
+			},
+			input:      query,
+			want:       entity.Series{Symbol: "TEST", Price: 5, PriceTime: week[4].Timestamp, Bars: week},
+			wantStatus: entity.CacheHit,
+		},
+		{
+			title: "recorded bars with a gap are fetched again",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartRecorder.EXPECT().History(gomock.Any(), query).Return([]entity.Bar{day(26, 1), day(27, 2), {Timestamp: time.Date(2022, time.October, 2, 13, 30, 0, 0, time.UTC), Close: 5}}, nil)
+				fetch(query)
+			},
+			input:      query,
+			want:       fetched,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "recorded bars that end early are fetched again",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartRecorder.EXPECT().History(gomock.Any(), query).Return(week[:2], nil)
+				fetch(query)
+			},
+			input:      query,
+			want:       fetched,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "history failure falls back to the providers",
+			mockCall: func() {
+				chartCache.EXPECT().Get(query).Return("", errors.New("cache is empty"))
+				chartRecorder.EXPECT().History(gomock.Any(), query).Return(nil, errors.New("db error"))
+				fetch(query)
+			},
+			input:      query,
+			want:       fetched,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "period ending today is fetched",
+			mockCall: func() {
+				today := entity.ChartQuery{Symbol: "TEST", Period1: from.Unix(), Period2: now.Unix()}
+				chartCache.EXPECT().Get(today).Return("", errors.New("cache is empty"))
+				fetch(today)
+			},
+			input:      entity.ChartQuery{Symbol: "TEST", Period1: from.Unix(), Period2: now.Unix()},
+			want:       fetched,
+			wantStatus: entity.CacheMiss,
+		},
+		{
+			title: "intraday bars are fetched",
+			mockCall: func() {
+				hourly := entity.ChartQuery{Symbol: "TEST", Interval: "1h", Period1: from.Unix(), Period2: to.Unix()}
+				chartCache.EXPECT().Get(hourly).Return("", errors.New("cache is empty"))
+				fetch(hourly)
+			},
+			input:      entity.ChartQuery{Symbol: "TEST", Interval: "1h", Period1: from.Unix(), Period2: to.Unix()},
+			want:       fetched,
+			wantStatus: entity.CacheMiss,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := chartService.Get(context.Background(), test.input)
+			chartService.Wait()
+			assert.NoError(t, err)
+			assert.Equal(t, test.want, got.Series)
+			assert.Equal(t, test.wantStatus, got.Status)
+		})
+	}
+}
+
+func TestChartGetMany(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	now := time.Now()
+	chartOf := func(symbol string) entity.Series {
+		return entity.Series{Symbol: symbol}
+	}
+	aapl := entity.ChartQuery{Symbol: "AAPL"}
+	msft := entity.ChartQuery{Symbol: "MSFT"}
+	bad := entity.ChartQuery{Symbol: "BAD"}
+	queries := []entity.ChartQuery{aapl, msft, bad}
+
+	chartCache.EXPECT().GetMany(queries).Return(map[string]string{aapl.Key(): cachedChart(t, chartOf("AAPL"), now.Add(time.Minute))}, nil)
+	chartFetcher.EXPECT().GetChart(gomock.Any(), msft).Return(chartOf("MSFT"), nil)
+	chartFetcher.EXPECT().GetChart(gomock.Any(), bad).Return(entity.Series{}, errors.New("unknown symbol"))
+	chartCache.EXPECT().Save(msft, gomock.Any(), gomock.Any()).Return(nil)
+	chartRecorder.EXPECT().Record(gomock.Any(), msft, chartOf("MSFT")).Return(nil)
+
+	got := chartService.GetMany(context.Background(), queries)
+	chartService.Wait()
+	assert.Len(t, got, 3)
+	assert.Equal(t, entity.CacheHit, got[aapl.Key()].Status)
+	assert.Equal(t, chartOf("AAPL"), got[aapl.Key()].Series)
+	assert.Equal(t, entity.CacheMiss, got[msft.Key()].Status)
+	assert.Equal(t, chartOf("MSFT"), got[msft.Key()].Series)
+	assert.Error(t, got[bad.Key()].Err)
+}
+
+func TestChartWarm(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	query := entity.ChartQuery{Symbol: "AAPL"}
+	chart := entity.Series{Symbol: "AAPL"}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    entity.ChartQuery
+		isError  bool
+	}{
+		{
+			title: "chart is fetched and saved without reading the cache",
+			mockCall: func() {
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+				chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+				chartRecorder.EXPECT().Record(gomock.Any(), query, chart).Return(nil)
+			},
+			input: query,
+		},
+		{
+			title: "provider failure and return error",
+			mockCall: func() {
+				chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+			},
+			input:   query,
+			isError: true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    entity.ChartQuery{},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			err := chartService.Warm(context.Background(), test.input)
+			chartService.Wait()
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestChartLatest(t *testing.T) {
+	cntr := gomock.NewController(t)
+	chartCache := mocks.NewMockChartCache(cntr)
+	chartFetcher := mocks.NewMockChartFetcher(cntr)
+	chartRecorder := mocks.NewMockChartRecorder(cntr)
+	chartService := NewChartService(logging.GetLogger("debug"), chartCache, chartFetcher, chartRecorder, newCalendar(t))
+	query := entity.ChartQuery{Symbol: "AAPL"}
+	chart := entity.Series{Symbol: "AAPL", Price: 2}
+
+	// the cached copy isn't read, however fresh it is
+	chartFetcher.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+	chartCache.EXPECT().Save(query, gomock.Any(), gomock.Any()).Return(nil)
+	chartRecorder.EXPECT().Record(gomock.Any(), query, chart).Return(nil)
+	got, err := chartService.Latest(context.Background(), query)
+	assert.NoError(t, err)
+	assert.Equal(t, chart, got.Series)
+	assert.Equal(t, entity.CacheMiss, got.Status)
+
+	_, err = chartService.Latest(context.Background(), entity.ChartQuery{})
+	assert.Error(t, err)
+	assert.NoError(t, chartService.Close())
+}
+
+func TestChartFreshFor(t *testing.T) {
+	chartService := NewChartService(logging.GetLogger("debug"), nil, nil, nil, newCalendar(t))
+	chartOf := func(exchange string) entity.Series {
+		return entity.Series{Exchange: exchange}
+	}
+	testCases := []struct {
+		title string
+		query entity.ChartQuery
+		chart entity.Series
+		now   time.Time
+		want  time.Duration
+	}{
+		{
+			title: "open market uses short ttl",
+			query: entity.ChartQuery{Symbol: "AAPL"},
+			chart: chartOf("NMS"),
+			now:   time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC),
+			want:  freshFor,
+		},
+		{
+			title: "closed market is fresh until next open",
+			query: entity.ChartQuery{Symbol: "AAPL"},
+			chart: chartOf("NMS"),
+			now:   time.Date(2022, time.October, 10, 21, 0, 0, 0, time.UTC),
+			want:  16*time.Hour + 30*time.Minute,
+		},
+		{
+			title: "weekend is fresh until monday open",
+			query: entity.ChartQuery{Symbol: "AAPL"},
+			chart: chartOf(""),
+			now:   time.Date(2022, time.October, 8, 13, 30, 0, 0, time.UTC),
+			want:  48 * time.Hour,
+		},
+		{
+			title: "exchange time zone is respected",
+			query: entity.ChartQuery{Symbol: "SBER.ME"},
+			chart: chartOf(""),
+			now:   time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC),
+			want:  freshFor,
+		},
+		{
+			title: "market opening soon uses short ttl",
+			query: entity.ChartQuery{Symbol: "AAPL"},
+			chart: chartOf("NYQ"),
+			now:   time.Date(2022, time.October, 10, 13, 29, 30, 0, time.UTC),
+			want:  freshFor,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.want, chartService.freshFor(test.query, test.chart, test.now))
+		})
+	}
+}
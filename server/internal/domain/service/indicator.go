@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/indicator"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const indicatorsFor = staleFor
+
+type IndicatorCache interface {
+	Set(key string, value string, expireAt time.Duration) error
+	Get(key string) (string, error)
+}
+
+type indicatorService struct {
+	logger *logging.Logger
+	charts PriceSource
+	cache  IndicatorCache
+}
+
+func NewIndicatorService(logger *logging.Logger, charts PriceSource, cache IndicatorCache) *indicatorService {
+	return &indicatorService{logger: logger, charts: charts, cache: cache}
+}
+
+// Compute calculates the indicators from the closes of the chart. The result
+// is cached next to the chart under a key that includes the market time of
+// the chart, so a refreshed chart never serves the indicators of the old one.
+func (i *indicatorService) Compute(ctx context.Context, query entity.ChartQuery, specs []indicator.Spec) (entity.IndicatorSet, error) {
+	if len(specs) == 0 {
+		return entity.IndicatorSet{}, errs.New(errs.Validation, errs.Code("indicators are empty"), errs.Parameter("names"))
+	}
+	result, err := i.charts.Get(ctx, query)
+	if err != nil {
+		return entity.IndicatorSet{}, err
+	}
+	key := indicatorsKey(query, result.Series, specs)
+	if cached, err := i.cache.Get(key); err == nil {
+		var set entity.IndicatorSet
+		if err := json.Unmarshal([]byte(cached), &set); err == nil {
+			return set, nil
+		}
+		i.logger.Errorf("cannot decode cached indicators = %v", key)
+	}
+
+	closes := make([]float64, len(result.Series.Bars))
+	set := entity.IndicatorSet{Timestamp: make([]int64, len(result.Series.Bars)), Lines: make(map[string][]*float64)}
+	for i, bar := range result.Series.Bars {
+		set.Timestamp[i] = bar.Timestamp.Unix()
+		closes[i] = bar.Close
+		if bar.Missing {
+			closes[i] = math.NaN()
+		}
+	}
+	for _, spec := range specs {
+		for name, line := range indicator.Compute(spec, closes) {
+			set.Lines[name] = nullable(line)
+		}
+	}
+	payload, err := json.Marshal(set)
+	if err != nil {
+		i.logger.Errorf("cannot encode indicators = %v due to : %v", key, err)
+		return set, nil
+	}
+	if err := i.cache.Set(key, string(payload), indicatorsFor); err != nil {
+		i.logger.Errorf("cannot save indicators to cache due to : %v", err)
+	}
+	return set, nil
+}
+
+func indicatorsKey(query entity.ChartQuery, series entity.Series, specs []indicator.Spec) string {
+	names := make([]string, 0, len(specs))
+	for _, spec := range specs {
+		names = append(names, spec.String())
+	}
+	return fmt.Sprintf("indicators:%v:%v:%v:%v", query.Key(), series.PriceTime.Unix(), len(series.Bars), strings.Join(names, ","))
+}
+
+func nullable(line []float64) []*float64 {
+	out := make([]*float64, len(line))
+	for i := range line {
+		if !math.IsNaN(line[i]) && !math.IsInf(line[i], 0) {
+			value := line[i]
+			out[i] = &value
+		}
+	}
+	return out
+}
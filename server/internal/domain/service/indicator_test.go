@@ -0,0 +1,88 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/indicator"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeIndicators(t *testing.T) {
+	cntr := gomock.NewController(t)
+	charts := mocks.NewMockPriceSource(cntr)
+	cache := mocks.NewMockIndicatorCache(cntr)
+	indicatorService := NewIndicatorService(logging.GetLogger("debug"), charts, cache)
+	query := entity.ChartQuery{Symbol: "AAPL"}
+	chart := entity.Series{Symbol: "AAPL", PriceTime: time.Unix(400, 0), Bars: []entity.Bar{
+		{Timestamp: time.Unix(100, 0), Close: 1},
+		{Timestamp: time.Unix(200, 0), Missing: true},
+		{Timestamp: time.Unix(300, 0), Close: 3},
+		{Timestamp: time.Unix(400, 0), Close: 5},
+	}}
+	key := "indicators:AAPL:::0:0:400:4:sma:2"
+	first, second := 2.0, 4.0
+	want := entity.IndicatorSet{Timestamp: []int64{100, 200, 300, 400}, Lines: map[string][]*float64{"sma:2": {nil, nil, &first, &second}}}
+	cached, _ := json.Marshal(want)
+	specs := []indicator.Spec{{Name: "sma", Params: []float64{2}}}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		want     entity.IndicatorSet
+		isError  bool
+	}{
+		{
+			title: "missing bars are skipped and the result is cached",
+			mockCall: func() {
+				charts.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{Series: chart}, nil)
+				cache.EXPECT().Get(key).Return("", errors.New("redis: nil"))
+				cache.EXPECT().Set(key, string(cached), indicatorsFor).Return(nil)
+			},
+			want: want,
+		},
+		{
+			title: "cached result is returned",
+			mockCall: func() {
+				charts.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{Series: chart}, nil)
+				cache.EXPECT().Get(key).Return(string(cached), nil)
+			},
+			want: want,
+		},
+		{
+			title: "cache failure doesn't fail the request",
+			mockCall: func() {
+				charts.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{Series: chart}, nil)
+				cache.EXPECT().Get(key).Return("", errors.New("redis: nil"))
+				cache.EXPECT().Set(key, gomock.Any(), indicatorsFor).Return(errors.New("redis error"))
+			},
+			want: want,
+		},
+		{
+			title: "chart error is returned",
+			mockCall: func() {
+				charts.EXPECT().Get(gomock.Any(), query).Return(entity.ChartResult{}, errors.New("http client error"))
+			},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := indicatorService.Compute(context.Background(), query, specs)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
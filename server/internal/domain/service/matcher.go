@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const expireEvery = time.Minute
+
+type matcher struct {
+	logger      *logging.Logger
+	storage     BookStorage
+	calendar    MarketCalendar
+	initialCash float64
+	now         func() time.Time
+
+	quotes *quoteQueue
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewMatcher returns the matching engine of the order book. It receives fresh
+// charts as a ChartListener and evaluates the active orders of their symbols
+// while the market of the symbol is open.
+func NewMatcher(logger *logging.Logger, storage BookStorage, calendar MarketCalendar, initialCash float64) *matcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &matcher{
+		logger:      logger,
+		storage:     storage,
+		calendar:    calendar,
+		initialCash: initialCash,
+		now:         time.Now,
+		quotes:      newQuoteQueue(),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+func (m *matcher) OnChart(query entity.ChartQuery, series entity.Series) {
+	m.quotes.push(query.Symbol, series)
+}
+
+func (m *matcher) Start() {
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		ticker := time.NewTicker(expireEvery)
+		defer ticker.Stop()
+		m.expire()
+		for {
+			select {
+			case <-m.ctx.Done():
+				return
+			case <-ticker.C:
+				m.expire()
+			case <-m.quotes.wake:
+				m.round()
+			}
+		}
+	}()
+}
+
+// Close stops the matcher after the current matching round.
+func (m *matcher) Close() error {
+	m.cancel()
+	m.wg.Wait()
+	return nil
+}
+
+func (m *matcher) round() {
+	for symbol, q := range m.quotes.drain() {
+		if m.ctx.Err() != nil {
+			return
+		}
+		m.matchSymbol(symbol, q)
+	}
+}
+
+func (m *matcher) matchSymbol(symbol string, q quote) {
+	if !m.calendar.Status(symbol, q.exchange, m.now()).Open {
+		return
+	}
+	orders, err := m.storage.ActiveBookOrders(m.ctx, symbol)
+	if err != nil {
+		m.logger.Errorf("cannot load active orders of %v due to : %v", symbol, err)
+		return
+	}
+	for _, order := range orders {
+		if !executable(order, q.price) {
+			continue
+		}
+		if !order.ExpiresAt.IsZero() && !m.now().Before(order.ExpiresAt) {
+			continue
+		}
+		matched, err := m.storage.MatchBookOrder(m.ctx, order.Id, m.initialCash,
+			func(order entity.BookOrder, account entity.Account, position entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error) {
+				return match(order, account, position, q.price)
+			})
+		if err != nil {
+			m.logger.Errorf("cannot match order %v due to : %v", order.Id, err)
+			continue
+		}
+		m.logger.Infof("order %v of %v is %v at %v, filled %v of %v", matched.Id, symbol, matched.State, q.price, matched.Filled, matched.Quantity)
+	}
+}
+
+func (m *matcher) expire() {
+	expired, err := m.storage.ExpireBookOrders(m.ctx, m.now())
+	if err != nil {
+		m.logger.Errorf("cannot expire orders due to : %v", err)
+		return
+	}
+	if expired > 0 {
+		m.logger.Infof("expired %v orders", expired)
+	}
+}
@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/alert.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	net "net"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAlertStorage is a mock of AlertStorage interface.
+type MockAlertStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockAlertStorageMockRecorder
+}
+
+// MockAlertStorageMockRecorder is the mock recorder for MockAlertStorage.
+type MockAlertStorageMockRecorder struct {
+	mock *MockAlertStorage
+}
+
+// NewMockAlertStorage creates a new mock instance.
+func NewMockAlertStorage(ctrl *gomock.Controller) *MockAlertStorage {
+	mock := &MockAlertStorage{ctrl: ctrl}
+	mock.recorder = &MockAlertStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAlertStorage) EXPECT() *MockAlertStorageMockRecorder {
+	return m.recorder
+}
+
+// Active mocks base method.
+func (m *MockAlertStorage) Active(ctx context.Context, symbol string) ([]entity.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Active", ctx, symbol)
+	ret0, _ := ret[0].([]entity.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Active indicates an expected call of Active.
+func (mr *MockAlertStorageMockRecorder) Active(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Active", reflect.TypeOf((*MockAlertStorage)(nil).Active), ctx, symbol)
+}
+
+// Delete mocks base method.
+func (m *MockAlertStorage) Delete(ctx context.Context, userId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockAlertStorageMockRecorder) Delete(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockAlertStorage)(nil).Delete), ctx, userId, id)
+}
+
+// FindAll mocks base method.
+func (m *MockAlertStorage) FindAll(ctx context.Context, userId int) ([]entity.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, userId)
+	ret0, _ := ret[0].([]entity.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockAlertStorageMockRecorder) FindAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockAlertStorage)(nil).FindAll), ctx, userId)
+}
+
+// Fire mocks base method.
+func (m *MockAlertStorage) Fire(ctx context.Context, id int, firedAt time.Time) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Fire", ctx, id, firedAt)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Fire indicates an expected call of Fire.
+func (mr *MockAlertStorageMockRecorder) Fire(ctx, id, firedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Fire", reflect.TypeOf((*MockAlertStorage)(nil).Fire), ctx, id, firedAt)
+}
+
+// Insert mocks base method.
+func (m *MockAlertStorage) Insert(ctx context.Context, alert entity.Alert) (entity.Alert, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, alert)
+	ret0, _ := ret[0].(entity.Alert)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockAlertStorageMockRecorder) Insert(ctx, alert interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockAlertStorage)(nil).Insert), ctx, alert)
+}
+
+// Rearm mocks base method.
+func (m *MockAlertStorage) Rearm(ctx context.Context, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rearm", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rearm indicates an expected call of Rearm.
+func (mr *MockAlertStorageMockRecorder) Rearm(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rearm", reflect.TypeOf((*MockAlertStorage)(nil).Rearm), ctx, id)
+}
+
+// MockNotifier is a mock of Notifier interface.
+type MockNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockNotifierMockRecorder
+}
+
+// MockNotifierMockRecorder is the mock recorder for MockNotifier.
+type MockNotifierMockRecorder struct {
+	mock *MockNotifier
+}
+
+// NewMockNotifier creates a new mock instance.
+func NewMockNotifier(ctrl *gomock.Controller) *MockNotifier {
+	mock := &MockNotifier{ctrl: ctrl}
+	mock.recorder = &MockNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockNotifier) EXPECT() *MockNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockNotifier) Notify(ctx context.Context, event entity.AlertEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Notify", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockNotifierMockRecorder) Notify(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockNotifier)(nil).Notify), ctx, event)
+}
+
+// MockResolver is a mock of Resolver interface.
+type MockResolver struct {
+	ctrl     *gomock.Controller
+	recorder *MockResolverMockRecorder
+}
+
+// MockResolverMockRecorder is the mock recorder for MockResolver.
+type MockResolverMockRecorder struct {
+	mock *MockResolver
+}
+
+// NewMockResolver creates a new mock instance.
+func NewMockResolver(ctrl *gomock.Controller) *MockResolver {
+	mock := &MockResolver{ctrl: ctrl}
+	mock.recorder = &MockResolverMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResolver) EXPECT() *MockResolverMockRecorder {
+	return m.recorder
+}
+
+// LookupIPAddr mocks base method.
+func (m *MockResolver) LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupIPAddr", ctx, host)
+	ret0, _ := ret[0].([]net.IPAddr)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupIPAddr indicates an expected call of LookupIPAddr.
+func (mr *MockResolverMockRecorder) LookupIPAddr(ctx, host interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupIPAddr", reflect.TypeOf((*MockResolver)(nil).LookupIPAddr), ctx, host)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/bar.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBarStorage is a mock of BarStorage interface.
+type MockBarStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBarStorageMockRecorder
+}
+
+// MockBarStorageMockRecorder is the mock recorder for MockBarStorage.
+type MockBarStorageMockRecorder struct {
+	mock *MockBarStorage
+}
+
+// NewMockBarStorage creates a new mock instance.
+func NewMockBarStorage(ctrl *gomock.Controller) *MockBarStorage {
+	mock := &MockBarStorage{ctrl: ctrl}
+	mock.recorder = &MockBarStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBarStorage) EXPECT() *MockBarStorageMockRecorder {
+	return m.recorder
+}
+
+// Find mocks base method.
+func (m *MockBarStorage) Find(ctx context.Context, symbol, interval string, from, to time.Time) ([]entity.Bar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, symbol, interval, from, to)
+	ret0, _ := ret[0].([]entity.Bar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockBarStorageMockRecorder) Find(ctx, symbol, interval, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockBarStorage)(nil).Find), ctx, symbol, interval, from, to)
+}
+
+// Upsert mocks base method.
+func (m *MockBarStorage) Upsert(ctx context.Context, symbol, interval string, bars []entity.Bar) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, symbol, interval, bars)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockBarStorageMockRecorder) Upsert(ctx, symbol, interval, bars interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockBarStorage)(nil).Upsert), ctx, symbol, interval, bars)
+}
@@ -49,6 +49,21 @@ func (mr *MockStockStorageMockRecorder) Get(symbol interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockStockStorage)(nil).Get), symbol)
 }
 
+// GetMany mocks base method.
+func (m *MockStockStorage) GetMany(keys []string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", keys)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockStockStorageMockRecorder) GetMany(keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockStockStorage)(nil).GetMany), keys)
+}
+
 // Set mocks base method.
 func (m *MockStockStorage) Set(symbol, stockInfo string, expireAt time.Duration) error {
 	m.ctrl.T.Helper()
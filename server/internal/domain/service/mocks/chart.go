@@ -0,0 +1,293 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/chart.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	calendar "github.com/VrMolodyakov/stock-market/pkg/calendar"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChartCache is a mock of ChartCache interface.
+type MockChartCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartCacheMockRecorder
+}
+
+// MockChartCacheMockRecorder is the mock recorder for MockChartCache.
+type MockChartCacheMockRecorder struct {
+	mock *MockChartCache
+}
+
+// NewMockChartCache creates a new mock instance.
+func NewMockChartCache(ctrl *gomock.Controller) *MockChartCache {
+	mock := &MockChartCache{ctrl: ctrl}
+	mock.recorder = &MockChartCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartCache) EXPECT() *MockChartCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockChartCache) Get(query entity.ChartQuery) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", query)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockChartCacheMockRecorder) Get(query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockChartCache)(nil).Get), query)
+}
+
+// GetMany mocks base method.
+func (m *MockChartCache) GetMany(queries []entity.ChartQuery) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", queries)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockChartCacheMockRecorder) GetMany(queries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockChartCache)(nil).GetMany), queries)
+}
+
+// Save mocks base method.
+func (m *MockChartCache) Save(query entity.ChartQuery, stockInfo string, duration time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", query, stockInfo, duration)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockChartCacheMockRecorder) Save(query, stockInfo, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockChartCache)(nil).Save), query, stockInfo, duration)
+}
+
+// MockChartFetcher is a mock of ChartFetcher interface.
+type MockChartFetcher struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartFetcherMockRecorder
+}
+
+// MockChartFetcherMockRecorder is the mock recorder for MockChartFetcher.
+type MockChartFetcherMockRecorder struct {
+	mock *MockChartFetcher
+}
+
+// NewMockChartFetcher creates a new mock instance.
+func NewMockChartFetcher(ctrl *gomock.Controller) *MockChartFetcher {
+	mock := &MockChartFetcher{ctrl: ctrl}
+	mock.recorder = &MockChartFetcherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartFetcher) EXPECT() *MockChartFetcherMockRecorder {
+	return m.recorder
+}
+
+// GetChart mocks base method.
+func (m *MockChartFetcher) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChart", ctx, query)
+	ret0, _ := ret[0].(entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChart indicates an expected call of GetChart.
+func (mr *MockChartFetcherMockRecorder) GetChart(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChart", reflect.TypeOf((*MockChartFetcher)(nil).GetChart), ctx, query)
+}
+
+// MockChartRecorder is a mock of ChartRecorder interface.
+type MockChartRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartRecorderMockRecorder
+}
+
+// MockChartRecorderMockRecorder is the mock recorder for MockChartRecorder.
+type MockChartRecorderMockRecorder struct {
+	mock *MockChartRecorder
+}
+
+// NewMockChartRecorder creates a new mock instance.
+func NewMockChartRecorder(ctrl *gomock.Controller) *MockChartRecorder {
+	mock := &MockChartRecorder{ctrl: ctrl}
+	mock.recorder = &MockChartRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartRecorder) EXPECT() *MockChartRecorderMockRecorder {
+	return m.recorder
+}
+
+// History mocks base method.
+func (m *MockChartRecorder) History(ctx context.Context, query entity.ChartQuery) ([]entity.Bar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "History", ctx, query)
+	ret0, _ := ret[0].([]entity.Bar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// History indicates an expected call of History.
+func (mr *MockChartRecorderMockRecorder) History(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "History", reflect.TypeOf((*MockChartRecorder)(nil).History), ctx, query)
+}
+
+// Record mocks base method.
+func (m *MockChartRecorder) Record(ctx context.Context, query entity.ChartQuery, chart entity.Series) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, query, chart)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockChartRecorderMockRecorder) Record(ctx, query, chart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockChartRecorder)(nil).Record), ctx, query, chart)
+}
+
+// MockChartLease is a mock of ChartLease interface.
+type MockChartLease struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartLeaseMockRecorder
+}
+
+// MockChartLeaseMockRecorder is the mock recorder for MockChartLease.
+type MockChartLeaseMockRecorder struct {
+	mock *MockChartLease
+}
+
+// NewMockChartLease creates a new mock instance.
+func NewMockChartLease(ctrl *gomock.Controller) *MockChartLease {
+	mock := &MockChartLease{ctrl: ctrl}
+	mock.recorder = &MockChartLeaseMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartLease) EXPECT() *MockChartLeaseMockRecorder {
+	return m.recorder
+}
+
+// Campaign mocks base method.
+func (m *MockChartLease) Campaign(ctx context.Context, key string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Campaign", ctx, key)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Campaign indicates an expected call of Campaign.
+func (mr *MockChartLeaseMockRecorder) Campaign(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Campaign", reflect.TypeOf((*MockChartLease)(nil).Campaign), ctx, key)
+}
+
+// Resign mocks base method.
+func (m *MockChartLease) Resign(key string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Resign", key)
+}
+
+// Resign indicates an expected call of Resign.
+func (mr *MockChartLeaseMockRecorder) Resign(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockChartLease)(nil).Resign), key)
+}
+
+// MockChartListener is a mock of ChartListener interface.
+type MockChartListener struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartListenerMockRecorder
+}
+
+// MockChartListenerMockRecorder is the mock recorder for MockChartListener.
+type MockChartListenerMockRecorder struct {
+	mock *MockChartListener
+}
+
+// NewMockChartListener creates a new mock instance.
+func NewMockChartListener(ctrl *gomock.Controller) *MockChartListener {
+	mock := &MockChartListener{ctrl: ctrl}
+	mock.recorder = &MockChartListenerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartListener) EXPECT() *MockChartListenerMockRecorder {
+	return m.recorder
+}
+
+// OnChart mocks base method.
+func (m *MockChartListener) OnChart(query entity.ChartQuery, chart entity.Series) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnChart", query, chart)
+}
+
+// OnChart indicates an expected call of OnChart.
+func (mr *MockChartListenerMockRecorder) OnChart(query, chart interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnChart", reflect.TypeOf((*MockChartListener)(nil).OnChart), query, chart)
+}
+
+// MockMarketCalendar is a mock of MarketCalendar interface.
+type MockMarketCalendar struct {
+	ctrl     *gomock.Controller
+	recorder *MockMarketCalendarMockRecorder
+}
+
+// MockMarketCalendarMockRecorder is the mock recorder for MockMarketCalendar.
+type MockMarketCalendarMockRecorder struct {
+	mock *MockMarketCalendar
+}
+
+// NewMockMarketCalendar creates a new mock instance.
+func NewMockMarketCalendar(ctrl *gomock.Controller) *MockMarketCalendar {
+	mock := &MockMarketCalendar{ctrl: ctrl}
+	mock.recorder = &MockMarketCalendarMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMarketCalendar) EXPECT() *MockMarketCalendarMockRecorder {
+	return m.recorder
+}
+
+// Status mocks base method.
+func (m *MockMarketCalendar) Status(symbol, exchangeName string, t time.Time) calendar.Status {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Status", symbol, exchangeName, t)
+	ret0, _ := ret[0].(calendar.Status)
+	return ret0
+}
+
+// Status indicates an expected call of Status.
+func (mr *MockMarketCalendarMockRecorder) Status(symbol, exchangeName, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Status", reflect.TypeOf((*MockMarketCalendar)(nil).Status), symbol, exchangeName, t)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/indicator.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIndicatorCache is a mock of IndicatorCache interface.
+type MockIndicatorCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndicatorCacheMockRecorder
+}
+
+// MockIndicatorCacheMockRecorder is the mock recorder for MockIndicatorCache.
+type MockIndicatorCacheMockRecorder struct {
+	mock *MockIndicatorCache
+}
+
+// NewMockIndicatorCache creates a new mock instance.
+func NewMockIndicatorCache(ctrl *gomock.Controller) *MockIndicatorCache {
+	mock := &MockIndicatorCache{ctrl: ctrl}
+	mock.recorder = &MockIndicatorCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndicatorCache) EXPECT() *MockIndicatorCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockIndicatorCache) Get(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIndicatorCacheMockRecorder) Get(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIndicatorCache)(nil).Get), key)
+}
+
+// Set mocks base method.
+func (m *MockIndicatorCache) Set(key, value string, expireAt time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, value, expireAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockIndicatorCacheMockRecorder) Set(key, value, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockIndicatorCache)(nil).Set), key, value, expireAt)
+}
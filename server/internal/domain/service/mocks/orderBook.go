@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/orderBook.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockBookStorage is a mock of BookStorage interface.
+type MockBookStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBookStorageMockRecorder
+}
+
+// MockBookStorageMockRecorder is the mock recorder for MockBookStorage.
+type MockBookStorageMockRecorder struct {
+	mock *MockBookStorage
+}
+
+// NewMockBookStorage creates a new mock instance.
+func NewMockBookStorage(ctrl *gomock.Controller) *MockBookStorage {
+	mock := &MockBookStorage{ctrl: ctrl}
+	mock.recorder = &MockBookStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBookStorage) EXPECT() *MockBookStorageMockRecorder {
+	return m.recorder
+}
+
+// ActiveBookOrders mocks base method.
+func (m *MockBookStorage) ActiveBookOrders(ctx context.Context, symbol string) ([]entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ActiveBookOrders", ctx, symbol)
+	ret0, _ := ret[0].([]entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ActiveBookOrders indicates an expected call of ActiveBookOrders.
+func (mr *MockBookStorageMockRecorder) ActiveBookOrders(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ActiveBookOrders", reflect.TypeOf((*MockBookStorage)(nil).ActiveBookOrders), ctx, symbol)
+}
+
+// BookOrders mocks base method.
+func (m *MockBookStorage) BookOrders(ctx context.Context, userId, limit int) ([]entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BookOrders", ctx, userId, limit)
+	ret0, _ := ret[0].([]entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BookOrders indicates an expected call of BookOrders.
+func (mr *MockBookStorageMockRecorder) BookOrders(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BookOrders", reflect.TypeOf((*MockBookStorage)(nil).BookOrders), ctx, userId, limit)
+}
+
+// CancelBookOrder mocks base method.
+func (m *MockBookStorage) CancelBookOrder(ctx context.Context, userId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelBookOrder", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelBookOrder indicates an expected call of CancelBookOrder.
+func (mr *MockBookStorageMockRecorder) CancelBookOrder(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelBookOrder", reflect.TypeOf((*MockBookStorage)(nil).CancelBookOrder), ctx, userId, id)
+}
+
+// ExpireBookOrders mocks base method.
+func (m *MockBookStorage) ExpireBookOrders(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBookOrders", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireBookOrders indicates an expected call of ExpireBookOrders.
+func (mr *MockBookStorageMockRecorder) ExpireBookOrders(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBookOrders", reflect.TypeOf((*MockBookStorage)(nil).ExpireBookOrders), ctx, now)
+}
+
+// InsertBookOrder mocks base method.
+func (m *MockBookStorage) InsertBookOrder(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertBookOrder", ctx, order)
+	ret0, _ := ret[0].(entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertBookOrder indicates an expected call of InsertBookOrder.
+func (mr *MockBookStorageMockRecorder) InsertBookOrder(ctx, order interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertBookOrder", reflect.TypeOf((*MockBookStorage)(nil).InsertBookOrder), ctx, order)
+}
+
+// MatchBookOrder mocks base method.
+func (m *MockBookStorage) MatchBookOrder(ctx context.Context, id int, initialCash float64, match func(entity.BookOrder, entity.Account, entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error)) (entity.BookOrder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MatchBookOrder", ctx, id, initialCash, match)
+	ret0, _ := ret[0].(entity.BookOrder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MatchBookOrder indicates an expected call of MatchBookOrder.
+func (mr *MockBookStorageMockRecorder) MatchBookOrder(ctx, id, initialCash, match interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MatchBookOrder", reflect.TypeOf((*MockBookStorage)(nil).MatchBookOrder), ctx, id, initialCash, match)
+}
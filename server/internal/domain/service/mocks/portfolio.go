@@ -0,0 +1,134 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/portfolio.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPortfolioStorage is a mock of PortfolioStorage interface.
+type MockPortfolioStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockPortfolioStorageMockRecorder
+}
+
+// MockPortfolioStorageMockRecorder is the mock recorder for MockPortfolioStorage.
+type MockPortfolioStorageMockRecorder struct {
+	mock *MockPortfolioStorage
+}
+
+// NewMockPortfolioStorage creates a new mock instance.
+func NewMockPortfolioStorage(ctrl *gomock.Controller) *MockPortfolioStorage {
+	mock := &MockPortfolioStorage{ctrl: ctrl}
+	mock.recorder = &MockPortfolioStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPortfolioStorage) EXPECT() *MockPortfolioStorageMockRecorder {
+	return m.recorder
+}
+
+// Account mocks base method.
+func (m *MockPortfolioStorage) Account(ctx context.Context, userId int, initialCash float64) (entity.Account, []entity.Position, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Account", ctx, userId, initialCash)
+	ret0, _ := ret[0].(entity.Account)
+	ret1, _ := ret[1].([]entity.Position)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Account indicates an expected call of Account.
+func (mr *MockPortfolioStorageMockRecorder) Account(ctx, userId, initialCash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Account", reflect.TypeOf((*MockPortfolioStorage)(nil).Account), ctx, userId, initialCash)
+}
+
+// Execute mocks base method.
+func (m *MockPortfolioStorage) Execute(ctx context.Context, order entity.Order, initialCash float64, fill func(entity.Account, entity.Position) (entity.Account, entity.Position, entity.Order, error)) (entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, order, initialCash, fill)
+	ret0, _ := ret[0].(entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockPortfolioStorageMockRecorder) Execute(ctx, order, initialCash, fill interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockPortfolioStorage)(nil).Execute), ctx, order, initialCash, fill)
+}
+
+// Orders mocks base method.
+func (m *MockPortfolioStorage) Orders(ctx context.Context, userId, limit int) ([]entity.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Orders", ctx, userId, limit)
+	ret0, _ := ret[0].([]entity.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Orders indicates an expected call of Orders.
+func (mr *MockPortfolioStorageMockRecorder) Orders(ctx, userId, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Orders", reflect.TypeOf((*MockPortfolioStorage)(nil).Orders), ctx, userId, limit)
+}
+
+// MockPriceSource is a mock of PriceSource interface.
+type MockPriceSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceSourceMockRecorder
+}
+
+// MockPriceSourceMockRecorder is the mock recorder for MockPriceSource.
+type MockPriceSourceMockRecorder struct {
+	mock *MockPriceSource
+}
+
+// NewMockPriceSource creates a new mock instance.
+func NewMockPriceSource(ctrl *gomock.Controller) *MockPriceSource {
+	mock := &MockPriceSource{ctrl: ctrl}
+	mock.recorder = &MockPriceSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceSource) EXPECT() *MockPriceSourceMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockPriceSource) Get(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, query)
+	ret0, _ := ret[0].(entity.ChartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPriceSourceMockRecorder) Get(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPriceSource)(nil).Get), ctx, query)
+}
+
+// GetMany mocks base method.
+func (m *MockPriceSource) GetMany(ctx context.Context, queries []entity.ChartQuery) map[string]entity.ChartResult {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMany", ctx, queries)
+	ret0, _ := ret[0].(map[string]entity.ChartResult)
+	return ret0
+}
+
+// GetMany indicates an expected call of GetMany.
+func (mr *MockPriceSourceMockRecorder) GetMany(ctx, queries interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMany", reflect.TypeOf((*MockPriceSource)(nil).GetMany), ctx, queries)
+}
@@ -0,0 +1,156 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/profile.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockProfileProvider is a mock of ProfileProvider interface.
+type MockProfileProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileProviderMockRecorder
+}
+
+// MockProfileProviderMockRecorder is the mock recorder for MockProfileProvider.
+type MockProfileProviderMockRecorder struct {
+	mock *MockProfileProvider
+}
+
+// NewMockProfileProvider creates a new mock instance.
+func NewMockProfileProvider(ctrl *gomock.Controller) *MockProfileProvider {
+	mock := &MockProfileProvider{ctrl: ctrl}
+	mock.recorder = &MockProfileProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileProvider) EXPECT() *MockProfileProviderMockRecorder {
+	return m.recorder
+}
+
+// GetProfile mocks base method.
+func (m *MockProfileProvider) GetProfile(ctx context.Context, symbol string) (entity.Profile, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx, symbol)
+	ret0, _ := ret[0].(entity.Profile)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockProfileProviderMockRecorder) GetProfile(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockProfileProvider)(nil).GetProfile), ctx, symbol)
+}
+
+// Name mocks base method.
+func (m *MockProfileProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockProfileProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockProfileProvider)(nil).Name))
+}
+
+// MockProfileCache is a mock of ProfileCache interface.
+type MockProfileCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockProfileCacheMockRecorder
+}
+
+// MockProfileCacheMockRecorder is the mock recorder for MockProfileCache.
+type MockProfileCacheMockRecorder struct {
+	mock *MockProfileCache
+}
+
+// NewMockProfileCache creates a new mock instance.
+func NewMockProfileCache(ctrl *gomock.Controller) *MockProfileCache {
+	mock := &MockProfileCache{ctrl: ctrl}
+	mock.recorder = &MockProfileCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProfileCache) EXPECT() *MockProfileCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockProfileCache) Get(key string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", key)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockProfileCacheMockRecorder) Get(key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockProfileCache)(nil).Get), key)
+}
+
+// Set mocks base method.
+func (m *MockProfileCache) Set(key, value string, expireAt time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", key, value, expireAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockProfileCacheMockRecorder) Set(key, value, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockProfileCache)(nil).Set), key, value, expireAt)
+}
+
+// MockSymbolLookup is a mock of SymbolLookup interface.
+type MockSymbolLookup struct {
+	ctrl     *gomock.Controller
+	recorder *MockSymbolLookupMockRecorder
+}
+
+// MockSymbolLookupMockRecorder is the mock recorder for MockSymbolLookup.
+type MockSymbolLookupMockRecorder struct {
+	mock *MockSymbolLookup
+}
+
+// NewMockSymbolLookup creates a new mock instance.
+func NewMockSymbolLookup(ctrl *gomock.Controller) *MockSymbolLookup {
+	mock := &MockSymbolLookup{ctrl: ctrl}
+	mock.recorder = &MockSymbolLookupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSymbolLookup) EXPECT() *MockSymbolLookupMockRecorder {
+	return m.recorder
+}
+
+// Info mocks base method.
+func (m *MockSymbolLookup) Info(symbol string) (entity.SymbolInfo, bool) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info", symbol)
+	ret0, _ := ret[0].(entity.SymbolInfo)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockSymbolLookupMockRecorder) Info(symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockSymbolLookup)(nil).Info), symbol)
+}
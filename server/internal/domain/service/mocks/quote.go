@@ -0,0 +1,65 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/quote.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockQuoteProvider is a mock of QuoteProvider interface.
+type MockQuoteProvider struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuoteProviderMockRecorder
+}
+
+// MockQuoteProviderMockRecorder is the mock recorder for MockQuoteProvider.
+type MockQuoteProviderMockRecorder struct {
+	mock *MockQuoteProvider
+}
+
+// NewMockQuoteProvider creates a new mock instance.
+func NewMockQuoteProvider(ctrl *gomock.Controller) *MockQuoteProvider {
+	mock := &MockQuoteProvider{ctrl: ctrl}
+	mock.recorder = &MockQuoteProviderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuoteProvider) EXPECT() *MockQuoteProviderMockRecorder {
+	return m.recorder
+}
+
+// GetChart mocks base method.
+func (m *MockQuoteProvider) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChart", ctx, query)
+	ret0, _ := ret[0].(entity.Series)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChart indicates an expected call of GetChart.
+func (mr *MockQuoteProviderMockRecorder) GetChart(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChart", reflect.TypeOf((*MockQuoteProvider)(nil).GetChart), ctx, query)
+}
+
+// Name mocks base method.
+func (m *MockQuoteProvider) Name() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Name")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Name indicates an expected call of Name.
+func (mr *MockQuoteProviderMockRecorder) Name() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Name", reflect.TypeOf((*MockQuoteProvider)(nil).Name))
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/symbol.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockSymbolDirectory is a mock of SymbolDirectory interface.
+type MockSymbolDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockSymbolDirectoryMockRecorder
+}
+
+// MockSymbolDirectoryMockRecorder is the mock recorder for MockSymbolDirectory.
+type MockSymbolDirectoryMockRecorder struct {
+	mock *MockSymbolDirectory
+}
+
+// NewMockSymbolDirectory creates a new mock instance.
+func NewMockSymbolDirectory(ctrl *gomock.Controller) *MockSymbolDirectory {
+	mock := &MockSymbolDirectory{ctrl: ctrl}
+	mock.recorder = &MockSymbolDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSymbolDirectory) EXPECT() *MockSymbolDirectoryMockRecorder {
+	return m.recorder
+}
+
+// Directory mocks base method.
+func (m *MockSymbolDirectory) Directory(ctx context.Context) ([]entity.SymbolInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Directory", ctx)
+	ret0, _ := ret[0].([]entity.SymbolInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Directory indicates an expected call of Directory.
+func (mr *MockSymbolDirectoryMockRecorder) Directory(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Directory", reflect.TypeOf((*MockSymbolDirectory)(nil).Directory), ctx)
+}
+
+// Search mocks base method.
+func (m *MockSymbolDirectory) Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query, limit)
+	ret0, _ := ret[0].([]entity.SymbolInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSymbolDirectoryMockRecorder) Search(ctx, query, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSymbolDirectory)(nil).Search), ctx, query, limit)
+}
+
+// Upsert mocks base method.
+func (m *MockSymbolDirectory) Upsert(ctx context.Context, infos []entity.SymbolInfo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", ctx, infos)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockSymbolDirectoryMockRecorder) Upsert(ctx, infos interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockSymbolDirectory)(nil).Upsert), ctx, infos)
+}
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 	time "time"
 
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	gomock "github.com/golang/mock/gomock"
 )
 
@@ -34,6 +35,21 @@ func (m *MockTokenStorage) EXPECT() *MockTokenStorageMockRecorder {
 	return m.recorder
 }
 
+// Alive mocks base method.
+func (m *MockTokenStorage) Alive(family string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Alive", family)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Alive indicates an expected call of Alive.
+func (mr *MockTokenStorageMockRecorder) Alive(family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Alive", reflect.TypeOf((*MockTokenStorage)(nil).Alive), family)
+}
+
 // Delete mocks base method.
 func (m *MockTokenStorage) Delete(refreshToken string) error {
 	m.ctrl.T.Helper()
@@ -48,11 +64,68 @@ func (mr *MockTokenStorageMockRecorder) Delete(refreshToken interface{}) *gomock
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockTokenStorage)(nil).Delete), refreshToken)
 }
 
+// DeleteFamily mocks base method.
+func (m *MockTokenStorage) DeleteFamily(family string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFamily", family)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFamily indicates an expected call of DeleteFamily.
+func (mr *MockTokenStorageMockRecorder) DeleteFamily(family interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFamily", reflect.TypeOf((*MockTokenStorage)(nil).DeleteFamily), family)
+}
+
+// DeleteSessions mocks base method.
+func (m *MockTokenStorage) DeleteSessions(userId int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSessions", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSessions indicates an expected call of DeleteSessions.
+func (mr *MockTokenStorageMockRecorder) DeleteSessions(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSessions", reflect.TypeOf((*MockTokenStorage)(nil).DeleteSessions), userId)
+}
+
+// Denied mocks base method.
+func (m *MockTokenStorage) Denied(id string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Denied", id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Denied indicates an expected call of Denied.
+func (mr *MockTokenStorageMockRecorder) Denied(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Denied", reflect.TypeOf((*MockTokenStorage)(nil).Denied), id)
+}
+
+// Deny mocks base method.
+func (m *MockTokenStorage) Deny(id string, expireAt time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Deny", id, expireAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Deny indicates an expected call of Deny.
+func (mr *MockTokenStorageMockRecorder) Deny(id, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Deny", reflect.TypeOf((*MockTokenStorage)(nil).Deny), id, expireAt)
+}
+
 // Get mocks base method.
-func (m *MockTokenStorage) Get(refreshToken string) (int, error) {
+func (m *MockTokenStorage) Get(refreshToken string) (entity.RefreshToken, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "Get", refreshToken)
-	ret0, _ := ret[0].(int)
+	ret0, _ := ret[0].(entity.RefreshToken)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -63,16 +136,46 @@ func (mr *MockTokenStorageMockRecorder) Get(refreshToken interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockTokenStorage)(nil).Get), refreshToken)
 }
 
+// Rotate mocks base method.
+func (m *MockTokenStorage) Rotate(oldToken, newToken string, usedAt time.Time, expireAt time.Duration) (entity.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rotate", oldToken, newToken, usedAt, expireAt)
+	ret0, _ := ret[0].(entity.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rotate indicates an expected call of Rotate.
+func (mr *MockTokenStorageMockRecorder) Rotate(oldToken, newToken, usedAt, expireAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rotate", reflect.TypeOf((*MockTokenStorage)(nil).Rotate), oldToken, newToken, usedAt, expireAt)
+}
+
+// Sessions mocks base method.
+func (m *MockTokenStorage) Sessions(userId int) ([]entity.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sessions", userId)
+	ret0, _ := ret[0].([]entity.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Sessions indicates an expected call of Sessions.
+func (mr *MockTokenStorageMockRecorder) Sessions(userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sessions", reflect.TypeOf((*MockTokenStorage)(nil).Sessions), userId)
+}
+
 // Set mocks base method.
-func (m *MockTokenStorage) Set(refreshToken string, userId int, expireAt time.Duration) error {
+func (m *MockTokenStorage) Set(refreshToken string, session entity.Session, expireAt time.Duration) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Set", refreshToken, userId, expireAt)
+	ret := m.ctrl.Call(m, "Set", refreshToken, session, expireAt)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Set indicates an expected call of Set.
-func (mr *MockTokenStorageMockRecorder) Set(refreshToken, userId, expireAt interface{}) *gomock.Call {
+func (mr *MockTokenStorageMockRecorder) Set(refreshToken, session, expireAt interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTokenStorage)(nil).Set), refreshToken, userId, expireAt)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockTokenStorage)(nil).Set), refreshToken, session, expireAt)
 }
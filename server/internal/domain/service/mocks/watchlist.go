@@ -0,0 +1,151 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/domain/service/watchlist.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockWatchlistStorage is a mock of WatchlistStorage interface.
+type MockWatchlistStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockWatchlistStorageMockRecorder
+}
+
+// MockWatchlistStorageMockRecorder is the mock recorder for MockWatchlistStorage.
+type MockWatchlistStorageMockRecorder struct {
+	mock *MockWatchlistStorage
+}
+
+// NewMockWatchlistStorage creates a new mock instance.
+func NewMockWatchlistStorage(ctrl *gomock.Controller) *MockWatchlistStorage {
+	mock := &MockWatchlistStorage{ctrl: ctrl}
+	mock.recorder = &MockWatchlistStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWatchlistStorage) EXPECT() *MockWatchlistStorageMockRecorder {
+	return m.recorder
+}
+
+// AddSymbol mocks base method.
+func (m *MockWatchlistStorage) AddSymbol(ctx context.Context, id int, symbol string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSymbol", ctx, id, symbol)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSymbol indicates an expected call of AddSymbol.
+func (mr *MockWatchlistStorageMockRecorder) AddSymbol(ctx, id, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSymbol", reflect.TypeOf((*MockWatchlistStorage)(nil).AddSymbol), ctx, id, symbol)
+}
+
+// Delete mocks base method.
+func (m *MockWatchlistStorage) Delete(ctx context.Context, userId, id int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, userId, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockWatchlistStorageMockRecorder) Delete(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockWatchlistStorage)(nil).Delete), ctx, userId, id)
+}
+
+// Find mocks base method.
+func (m *MockWatchlistStorage) Find(ctx context.Context, userId, id int) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Find", ctx, userId, id)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Find indicates an expected call of Find.
+func (mr *MockWatchlistStorageMockRecorder) Find(ctx, userId, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Find", reflect.TypeOf((*MockWatchlistStorage)(nil).Find), ctx, userId, id)
+}
+
+// FindAll mocks base method.
+func (m *MockWatchlistStorage) FindAll(ctx context.Context, userId int) ([]entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindAll", ctx, userId)
+	ret0, _ := ret[0].([]entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindAll indicates an expected call of FindAll.
+func (mr *MockWatchlistStorageMockRecorder) FindAll(ctx, userId interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockWatchlistStorage)(nil).FindAll), ctx, userId)
+}
+
+// Insert mocks base method.
+func (m *MockWatchlistStorage) Insert(ctx context.Context, userId int, name string) (entity.Watchlist, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Insert", ctx, userId, name)
+	ret0, _ := ret[0].(entity.Watchlist)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Insert indicates an expected call of Insert.
+func (mr *MockWatchlistStorageMockRecorder) Insert(ctx, userId, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Insert", reflect.TypeOf((*MockWatchlistStorage)(nil).Insert), ctx, userId, name)
+}
+
+// RemoveSymbol mocks base method.
+func (m *MockWatchlistStorage) RemoveSymbol(ctx context.Context, id int, symbol string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSymbol", ctx, id, symbol)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSymbol indicates an expected call of RemoveSymbol.
+func (mr *MockWatchlistStorageMockRecorder) RemoveSymbol(ctx, id, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSymbol", reflect.TypeOf((*MockWatchlistStorage)(nil).RemoveSymbol), ctx, id, symbol)
+}
+
+// Rename mocks base method.
+func (m *MockWatchlistStorage) Rename(ctx context.Context, userId, id int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rename", ctx, userId, id, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Rename indicates an expected call of Rename.
+func (mr *MockWatchlistStorageMockRecorder) Rename(ctx, userId, id, name interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rename", reflect.TypeOf((*MockWatchlistStorage)(nil).Rename), ctx, userId, id, name)
+}
+
+// Reorder mocks base method.
+func (m *MockWatchlistStorage) Reorder(ctx context.Context, id int, symbols []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reorder", ctx, id, symbols)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reorder indicates an expected call of Reorder.
+func (mr *MockWatchlistStorageMockRecorder) Reorder(ctx, id, symbols interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reorder", reflect.TypeOf((*MockWatchlistStorage)(nil).Reorder), ctx, id, symbols)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+// dayOrderFor bounds DAY orders of markets without sessions, like crypto.
+const dayOrderFor = 24 * time.Hour
+
+type BookStorage interface {
+	InsertBookOrder(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error)
+	BookOrders(ctx context.Context, userId int, limit int) ([]entity.BookOrder, error)
+	ActiveBookOrders(ctx context.Context, symbol string) ([]entity.BookOrder, error)
+	CancelBookOrder(ctx context.Context, userId int, id int) error
+	ExpireBookOrders(ctx context.Context, now time.Time) (int64, error)
+	MatchBookOrder(
+		ctx context.Context,
+		id int,
+		initialCash float64,
+		match func(order entity.BookOrder, account entity.Account, position entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error),
+	) (entity.BookOrder, error)
+}
+
+type orderBookService struct {
+	logger   *logging.Logger
+	storage  BookStorage
+	calendar MarketCalendar
+	now      func() time.Time
+}
+
+func NewOrderBookService(logger *logging.Logger, storage BookStorage, calendar MarketCalendar) *orderBookService {
+	return &orderBookService{logger: logger, storage: storage, calendar: calendar, now: time.Now}
+}
+
+// Place validates the order and puts it into the book. DAY orders expire at
+// the close of the current session or of the next one if the market is closed.
+func (o *orderBookService) Place(ctx context.Context, order entity.BookOrder) (entity.BookOrder, error) {
+	symbol, err := watchlistSymbol(order.Symbol)
+	if err != nil {
+		return entity.BookOrder{}, err
+	}
+	order.Symbol = symbol
+	if order.Side != entity.Buy && order.Side != entity.Sell {
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("unsupported order side"), errs.Parameter("side"))
+	}
+	if order.Quantity <= 0 || order.Quantity > maxOrderQuantity {
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("incorrect quantity"), errs.Parameter("quantity"))
+	}
+	switch order.Type {
+	case entity.Limit:
+		order.StopPrice = 0
+	case entity.Stop:
+		order.LimitPrice = 0
+	case entity.StopLimit:
+	default:
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("unsupported order type"), errs.Parameter("type"))
+	}
+	if order.Type != entity.Stop && order.LimitPrice <= 0 {
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("limit price is required"), errs.Parameter("limit_price"))
+	}
+	if order.Type != entity.Limit && order.StopPrice <= 0 {
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("stop price is required"), errs.Parameter("stop_price"))
+	}
+	now := o.now()
+	switch order.TimeInForce {
+	case "", entity.Day:
+		order.TimeInForce = entity.Day
+		order.ExpiresAt = o.sessionEnd(order.Symbol, now)
+	case entity.GTC:
+		order.ExpiresAt = time.Time{}
+	default:
+		return entity.BookOrder{}, errs.New(errs.Validation, errs.Code("unsupported time in force"), errs.Parameter("time_in_force"))
+	}
+	return o.storage.InsertBookOrder(ctx, order)
+}
+
+func (o *orderBookService) Cancel(ctx context.Context, userId int, id int) error {
+	return o.storage.CancelBookOrder(ctx, userId, id)
+}
+
+func (o *orderBookService) List(ctx context.Context, userId int) ([]entity.BookOrder, error) {
+	return o.storage.BookOrders(ctx, userId, ordersLimit)
+}
+
+func (o *orderBookService) sessionEnd(symbol string, now time.Time) time.Time {
+	status := o.calendar.Status(symbol, "", now)
+	if status.NextClose.IsZero() || status.NextClose.Sub(now) > 7*dayOrderFor {
+		return now.Add(dayOrderFor)
+	}
+	return status.NextClose
+}
+
+// executable reports whether the order should be passed to match at the
+// price, it lets the matcher skip the transaction for most of the orders.
+func executable(order entity.BookOrder, price float64) bool {
+	if order.Type != entity.Limit && !order.Triggered && !stopReached(order, price) {
+		return false
+	}
+	return order.Type == entity.Stop || limitReached(order, price)
+}
+
+// match triggers the stop of the order and fills as much of it at the price
+// as the account allows. A stop order becomes a market order once the stop
+// price is reached and a stop-limit order becomes a limit order.
+func match(order entity.BookOrder, account entity.Account, position entity.Position, price float64) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error) {
+	if order.Type != entity.Limit && !order.Triggered {
+		if !stopReached(order, price) {
+			return order, account, position, nil, nil
+		}
+		order.Triggered = true
+	}
+	if order.Type != entity.Stop && !limitReached(order, price) {
+		return order, account, position, nil, nil
+	}
+	quantity := order.Remaining()
+	switch order.Side {
+	case entity.Buy:
+		if affordable := int64(math.Floor(account.Cash / price)); affordable < quantity {
+			quantity = affordable
+		}
+	case entity.Sell:
+		if position.Quantity < quantity {
+			quantity = position.Quantity
+		}
+	}
+	if quantity <= 0 {
+		return order, account, position, nil, nil
+	}
+	account, position, filled, err := fill(account, position, entity.Order{
+		UserId:   order.UserId,
+		Symbol:   order.Symbol,
+		Side:     order.Side,
+		Quantity: quantity,
+		Price:    price,
+	})
+	if err != nil {
+		return order, account, position, nil, err
+	}
+	order.Filled += quantity
+	order.State = entity.PartiallyFilled
+	if order.Remaining() == 0 {
+		order.State = entity.Filled
+	}
+	return order, account, position, &filled, nil
+}
+
+func stopReached(order entity.BookOrder, price float64) bool {
+	if order.Side == entity.Buy {
+		return price >= order.StopPrice
+	}
+	return price <= order.StopPrice
+}
+
+func limitReached(order entity.BookOrder, price float64) bool {
+	if order.Side == entity.Buy {
+		return price <= order.LimitPrice
+	}
+	return price >= order.LimitPrice
+}
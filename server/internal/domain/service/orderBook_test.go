@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatch(t *testing.T) {
+	account := entity.Account{UserId: 1, Cash: 1000}
+	testCases := []struct {
+		title        string
+		order        entity.BookOrder
+		position     entity.Position
+		price        float64
+		wantState    entity.OrderState
+		wantFilled   int64
+		wantTrigger  bool
+		wantExecuted bool
+	}{
+		{
+			title:     "buy limit above the price waits",
+			order:     entity.BookOrder{Side: entity.Buy, Type: entity.Limit, Quantity: 5, LimitPrice: 90, State: entity.Open},
+			price:     100,
+			wantState: entity.Open,
+		},
+		{
+			title:        "buy limit fills at the price",
+			order:        entity.BookOrder{Side: entity.Buy, Type: entity.Limit, Quantity: 5, LimitPrice: 110, State: entity.Open},
+			price:        100,
+			wantState:    entity.Filled,
+			wantFilled:   5,
+			wantExecuted: true,
+		},
+		{
+			title:        "buy limit is partially filled by the available cash",
+			order:        entity.BookOrder{Side: entity.Buy, Type: entity.Limit, Quantity: 20, LimitPrice: 110, State: entity.Open},
+			price:        100,
+			wantState:    entity.PartiallyFilled,
+			wantFilled:   10,
+			wantExecuted: true,
+		},
+		{
+			title:        "sell limit is partially filled by the held shares",
+			order:        entity.BookOrder{Side: entity.Sell, Type: entity.Limit, Quantity: 5, LimitPrice: 90, State: entity.Open},
+			position:     entity.Position{Quantity: 2, AverageCost: 80},
+			price:        100,
+			wantState:    entity.PartiallyFilled,
+			wantFilled:   2,
+			wantExecuted: true,
+		},
+		{
+			title:     "sell stop above the price waits",
+			order:     entity.BookOrder{Side: entity.Sell, Type: entity.Stop, Quantity: 2, StopPrice: 90, State: entity.Open},
+			position:  entity.Position{Quantity: 2, AverageCost: 80},
+			price:     100,
+			wantState: entity.Open,
+		},
+		{
+			title:        "sell stop triggers and fills at market",
+			order:        entity.BookOrder{Side: entity.Sell, Type: entity.Stop, Quantity: 2, StopPrice: 90, State: entity.Open},
+			position:     entity.Position{Quantity: 2, AverageCost: 80},
+			price:        85,
+			wantState:    entity.Filled,
+			wantFilled:   2,
+			wantTrigger:  true,
+			wantExecuted: true,
+		},
+		{
+			title:       "buy stop limit triggers but waits for the limit",
+			order:       entity.BookOrder{Side: entity.Buy, Type: entity.StopLimit, Quantity: 2, StopPrice: 100, LimitPrice: 101, State: entity.Open},
+			price:       105,
+			wantState:   entity.Open,
+			wantTrigger: true,
+		},
+		{
+			title:        "triggered buy stop limit fills below the limit",
+			order:        entity.BookOrder{Side: entity.Buy, Type: entity.StopLimit, Quantity: 2, StopPrice: 100, LimitPrice: 101, Triggered: true, State: entity.Open},
+			price:        99,
+			wantState:    entity.Filled,
+			wantFilled:   2,
+			wantTrigger:  true,
+			wantExecuted: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			order, _, _, executed, err := match(test.order, account, test.position, test.price)
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantState, order.State)
+			assert.Equal(t, test.wantFilled, order.Filled)
+			assert.Equal(t, test.wantTrigger, order.Triggered)
+			assert.Equal(t, test.wantExecuted, executed != nil)
+			if executed != nil {
+				assert.Equal(t, test.price, executed.Price)
+				assert.Equal(t, test.wantFilled, executed.Quantity)
+			}
+		})
+	}
+}
+
+func TestPlaceBookOrder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockBookStorage(cntr)
+	marketCalendar := mocks.NewMockMarketCalendar(cntr)
+	bookService := NewOrderBookService(logging.GetLogger("debug"), storage, marketCalendar)
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	bookService.now = func() time.Time { return now }
+	sessionClose := time.Date(2026, 3, 2, 21, 0, 0, 0, time.UTC)
+	type mockCall func()
+	testCases := []struct {
+		title         string
+		order         entity.BookOrder
+		mockCall      mockCall
+		wantExpiresAt time.Time
+		isError       bool
+	}{
+		{
+			title: "day order expires at the session close",
+			order: entity.BookOrder{Symbol: "aapl", Side: entity.Buy, Type: entity.Limit, Quantity: 1, LimitPrice: 100, StopPrice: 5},
+			mockCall: func() {
+				marketCalendar.EXPECT().Status("AAPL", "", now).Return(calendar.Status{Open: true, NextClose: sessionClose})
+				storage.EXPECT().InsertBookOrder(gomock.Any(), entity.BookOrder{
+					Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 1, LimitPrice: 100,
+					TimeInForce: entity.Day, ExpiresAt: sessionClose,
+				}).DoAndReturn(func(_ context.Context, order entity.BookOrder) (entity.BookOrder, error) { return order, nil })
+			},
+			wantExpiresAt: sessionClose,
+		},
+		{
+			title: "day order of a market without sessions expires in a day",
+			order: entity.BookOrder{Symbol: "BTC-USD", Side: entity.Sell, Type: entity.Stop, Quantity: 1, StopPrice: 100, TimeInForce: entity.Day},
+			mockCall: func() {
+				marketCalendar.EXPECT().Status("BTC-USD", "", now).Return(calendar.Status{Open: true})
+				storage.EXPECT().InsertBookOrder(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, order entity.BookOrder) (entity.BookOrder, error) { return order, nil })
+			},
+			wantExpiresAt: now.Add(dayOrderFor),
+		},
+		{
+			title: "gtc order never expires",
+			order: entity.BookOrder{Symbol: "AAPL", Side: entity.Buy, Type: entity.StopLimit, Quantity: 1, StopPrice: 100, LimitPrice: 101, TimeInForce: entity.GTC},
+			mockCall: func() {
+				storage.EXPECT().InsertBookOrder(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, order entity.BookOrder) (entity.BookOrder, error) { return order, nil })
+			},
+		},
+		{
+			title:    "limit order without limit price",
+			order:    entity.BookOrder{Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 1, StopPrice: 100},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "stop limit order without stop price",
+			order:    entity.BookOrder{Symbol: "AAPL", Side: entity.Buy, Type: entity.StopLimit, Quantity: 1, LimitPrice: 100},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "unsupported order type",
+			order:    entity.BookOrder{Symbol: "AAPL", Side: entity.Buy, Type: "market", Quantity: 1},
+			mockCall: func() {},
+			isError:  true,
+		},
+		{
+			title:    "unsupported time in force",
+			order:    entity.BookOrder{Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 1, LimitPrice: 100, TimeInForce: "IOC"},
+			mockCall: func() {},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := bookService.Place(context.Background(), test.order)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.wantExpiresAt, got.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestMatcherRound(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockBookStorage(cntr)
+	marketCalendar := mocks.NewMockMarketCalendar(cntr)
+	matcher := NewMatcher(logging.GetLogger("debug"), storage, marketCalendar, 1000)
+	now := time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)
+	matcher.now = func() time.Time { return now }
+	chart := priceChart(100)
+	chart.Series.Exchange = "NMS"
+	type matchFunc = func(entity.BookOrder, entity.Account, entity.Position) (entity.BookOrder, entity.Account, entity.Position, *entity.Order, error)
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+	}{
+		{
+			title: "closed market is skipped",
+			mockCall: func() {
+				marketCalendar.EXPECT().Status("AAPL", "NMS", now).Return(calendar.Status{})
+			},
+		},
+		{
+			title: "only executable orders are matched",
+			mockCall: func() {
+				marketCalendar.EXPECT().Status("AAPL", "NMS", now).Return(calendar.Status{Open: true})
+				storage.EXPECT().ActiveBookOrders(gomock.Any(), "AAPL").Return([]entity.BookOrder{
+					{Id: 1, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, LimitPrice: 90, State: entity.Open},
+					{Id: 2, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, LimitPrice: 110, State: entity.Open},
+					{Id: 3, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, LimitPrice: 110, State: entity.Open, ExpiresAt: now},
+				}, nil)
+				storage.EXPECT().MatchBookOrder(gomock.Any(), 2, 1000.0, gomock.Any()).DoAndReturn(
+					func(_ context.Context, _ int, _ float64, match matchFunc) (entity.BookOrder, error) {
+						order, _, _, filled, err := match(
+							entity.BookOrder{Id: 2, Symbol: "AAPL", Side: entity.Buy, Type: entity.Limit, Quantity: 2, LimitPrice: 110, State: entity.Open},
+							entity.Account{UserId: 1, Cash: 1000},
+							entity.Position{Symbol: "AAPL"},
+						)
+						assert.NotNil(t, filled)
+						assert.Equal(t, entity.Filled, order.State)
+						return order, err
+					})
+			},
+		},
+		{
+			title: "storage error stops the symbol",
+			mockCall: func() {
+				marketCalendar.EXPECT().Status("AAPL", "NMS", now).Return(calendar.Status{Open: true})
+				storage.EXPECT().ActiveBookOrders(gomock.Any(), "AAPL").Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			matcher.OnChart(entity.ChartQuery{Symbol: "AAPL"}, chart.Series)
+			matcher.round()
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const (
+	maxOrderQuantity = 1_000_000
+	ordersLimit      = 100
+)
+
+type PortfolioStorage interface {
+	Account(ctx context.Context, userId int, initialCash float64) (entity.Account, []entity.Position, error)
+	Execute(
+		ctx context.Context,
+		order entity.Order,
+		initialCash float64,
+		fill func(account entity.Account, position entity.Position) (entity.Account, entity.Position, entity.Order, error),
+	) (entity.Order, error)
+	Orders(ctx context.Context, userId int, limit int) ([]entity.Order, error)
+}
+
+type PriceSource interface {
+	Get(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error)
+	GetMany(ctx context.Context, queries []entity.ChartQuery) map[string]entity.ChartResult
+}
+
+type portfolioService struct {
+	logger      *logging.Logger
+	storage     PortfolioStorage
+	prices      PriceSource
+	initialCash float64
+}
+
+// NewPortfolioService returns a paper trading service. Every user starts with
+// initialCash and market orders are filled at the current market price.
+func NewPortfolioService(logger *logging.Logger, storage PortfolioStorage, prices PriceSource, initialCash float64) *portfolioService {
+	return &portfolioService{logger: logger, storage: storage, prices: prices, initialCash: initialCash}
+}
+
+// Get returns the portfolio valued at the current market prices. A position
+// without a known price is valued at its average cost.
+func (p *portfolioService) Get(ctx context.Context, userId int) (entity.Portfolio, error) {
+	account, positions, err := p.storage.Account(ctx, userId, p.initialCash)
+	if err != nil {
+		return entity.Portfolio{}, err
+	}
+	portfolio := entity.Portfolio{Account: account, Holdings: make([]entity.Holding, 0, len(positions)), Equity: account.Cash}
+	if len(positions) == 0 {
+		return portfolio, nil
+	}
+	queries := make([]entity.ChartQuery, len(positions))
+	for i, position := range positions {
+		queries[i] = entity.ChartQuery{Symbol: position.Symbol}
+	}
+	charts := p.prices.GetMany(ctx, queries)
+	for i, position := range positions {
+		price, ok := marketPrice(charts[queries[i].Key()])
+		if !ok {
+			p.logger.Errorf("cannot value position %v, price is unavailable", position.Symbol)
+			price = position.AverageCost
+		}
+		holding := entity.Holding{
+			Position:    position,
+			Price:       price,
+			MarketValue: price * float64(position.Quantity),
+			Unrealized:  (price - position.AverageCost) * float64(position.Quantity),
+		}
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+		portfolio.Unrealized += holding.Unrealized
+		portfolio.Equity += holding.MarketValue
+	}
+	return portfolio, nil
+}
+
+// PlaceOrder fills a market order at the current price of the symbol, the
+// order is rejected when only a stale price is known.
+func (p *portfolioService) PlaceOrder(ctx context.Context, userId int, symbol string, side entity.OrderSide, quantity int64) (entity.Order, error) {
+	symbol, err := watchlistSymbol(symbol)
+	if err != nil {
+		return entity.Order{}, err
+	}
+	if side != entity.Buy && side != entity.Sell {
+		return entity.Order{}, errs.New(errs.Validation, errs.Code("unsupported order side"), errs.Parameter("side"))
+	}
+	if quantity <= 0 || quantity > maxOrderQuantity {
+		return entity.Order{}, errs.New(errs.Validation, errs.Code("incorrect quantity"), errs.Parameter("quantity"))
+	}
+	result, err := p.prices.Get(ctx, entity.ChartQuery{Symbol: symbol})
+	if err != nil {
+		return entity.Order{}, err
+	}
+	price, ok := marketPrice(result)
+	if !ok {
+		return entity.Order{}, errs.New(errs.Validation, errs.Code("price is unavailable"), errs.Parameter("symbol"))
+	}
+	// a stale chart is served when the providers fail and its price may be
+	// far behind the market
+	if result.Stale() {
+		return entity.Order{}, errs.New(errs.Validation, errs.Code("price is stale"), errs.Parameter("symbol"))
+	}
+	order := entity.Order{UserId: userId, Symbol: symbol, Side: side, Quantity: quantity, Price: price}
+	filled, err := p.storage.Execute(ctx, order, p.initialCash, func(account entity.Account, position entity.Position) (entity.Account, entity.Position, entity.Order, error) {
+		return fill(account, position, order)
+	})
+	if err != nil {
+		return entity.Order{}, err
+	}
+	p.logger.Infof("user %v filled %v %v %v at %v", userId, side, quantity, symbol, price)
+	return filled, nil
+}
+
+func (p *portfolioService) Orders(ctx context.Context, userId int) ([]entity.Order, error) {
+	return p.storage.Orders(ctx, userId, ordersLimit)
+}
+
+// fill applies the order to the account and the position. Buying moves the
+// average cost, selling realizes the profit against it. Short selling and
+// buying on margin are not allowed.
+func fill(account entity.Account, position entity.Position, order entity.Order) (entity.Account, entity.Position, entity.Order, error) {
+	amount := order.Price * float64(order.Quantity)
+	switch order.Side {
+	case entity.Buy:
+		if amount > account.Cash {
+			return account, position, order, errs.New(errs.Validation, errs.Code("insufficient funds"), errs.Parameter("quantity"))
+		}
+		quantity := position.Quantity + order.Quantity
+		position.AverageCost = (position.AverageCost*float64(position.Quantity) + amount) / float64(quantity)
+		position.Quantity = quantity
+		account.Cash -= amount
+	case entity.Sell:
+		if order.Quantity > position.Quantity {
+			return account, position, order, errs.New(errs.Validation, errs.Code("insufficient shares"), errs.Parameter("quantity"))
+		}
+		order.Realized = (order.Price - position.AverageCost) * float64(order.Quantity)
+		position.Quantity -= order.Quantity
+		if position.Quantity == 0 {
+			position.AverageCost = 0
+		}
+		account.Cash += amount
+		account.Realized += order.Realized
+	}
+	return account, position, order, nil
+}
+
+func marketPrice(result entity.ChartResult) (float64, bool) {
+	if result.Err != nil {
+		return 0, false
+	}
+	return result.Series.Price, result.Series.Price > 0
+}
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func priceChart(price float64) entity.ChartResult {
+	return entity.ChartResult{Series: entity.Series{Price: price}}
+}
+
+func TestPlaceOrder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockPortfolioStorage(cntr)
+	prices := mocks.NewMockPriceSource(cntr)
+	portfolioService := NewPortfolioService(logging.GetLogger("debug"), storage, prices, 1000)
+	aapl := entity.ChartQuery{Symbol: "AAPL"}
+	type fillFunc = func(entity.Account, entity.Position) (entity.Account, entity.Position, entity.Order, error)
+	execute := func(account entity.Account, position entity.Position, wantAccount entity.Account, wantPosition entity.Position) {
+		storage.EXPECT().Execute(gomock.Any(), gomock.Any(), 1000.0, gomock.Any()).DoAndReturn(
+			func(_ context.Context, order entity.Order, _ float64, fill fillFunc) (entity.Order, error) {
+				gotAccount, gotPosition, filled, err := fill(account, position)
+				if err != nil {
+					return entity.Order{}, err
+				}
+				assert.Equal(t, wantAccount, gotAccount)
+				assert.Equal(t, wantPosition, gotPosition)
+				filled.Id = 1
+				return filled, nil
+			})
+	}
+	type args struct {
+		symbol   string
+		side     entity.OrderSide
+		quantity int64
+	}
+	type mockCall func()
+	testCases := []struct {
+		title        string
+		mockCall     mockCall
+		args         args
+		wantRealized float64
+		isError      bool
+	}{
+		{
+			title: "buy opens a position at market price",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(100), nil)
+				execute(
+					entity.Account{UserId: 1, Cash: 1000},
+					entity.Position{Symbol: "AAPL"},
+					entity.Account{UserId: 1, Cash: 700},
+					entity.Position{Symbol: "AAPL", Quantity: 3, AverageCost: 100},
+				)
+			},
+			args: args{symbol: "aapl", side: entity.Buy, quantity: 3},
+		},
+		{
+			title: "buy moves the average cost",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(130), nil)
+				execute(
+					entity.Account{UserId: 1, Cash: 700},
+					entity.Position{Symbol: "AAPL", Quantity: 3, AverageCost: 100},
+					entity.Account{UserId: 1, Cash: 570},
+					entity.Position{Symbol: "AAPL", Quantity: 4, AverageCost: 107.5},
+				)
+			},
+			args: args{symbol: "AAPL", side: entity.Buy, quantity: 1},
+		},
+		{
+			title: "sell realizes the profit and closes the position",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(120), nil)
+				execute(
+					entity.Account{UserId: 1, Cash: 570, Realized: 5},
+					entity.Position{Symbol: "AAPL", Quantity: 4, AverageCost: 107.5},
+					entity.Account{UserId: 1, Cash: 1050, Realized: 55},
+					entity.Position{Symbol: "AAPL"},
+				)
+			},
+			args:         args{symbol: "AAPL", side: entity.Sell, quantity: 4},
+			wantRealized: 50,
+		},
+		{
+			title: "buy without funds and return error",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(100), nil)
+				execute(entity.Account{UserId: 1, Cash: 1000}, entity.Position{Symbol: "AAPL"}, entity.Account{}, entity.Position{})
+			},
+			args:    args{symbol: "AAPL", side: entity.Buy, quantity: 11},
+			isError: true,
+		},
+		{
+			title: "short sell and return error",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(100), nil)
+				execute(entity.Account{UserId: 1, Cash: 1000}, entity.Position{Symbol: "AAPL", Quantity: 1}, entity.Account{}, entity.Position{})
+			},
+			args:    args{symbol: "AAPL", side: entity.Sell, quantity: 2},
+			isError: true,
+		},
+		{
+			title: "unknown price and return error",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(priceChart(0), nil)
+			},
+			args:    args{symbol: "AAPL", side: entity.Buy, quantity: 1},
+			isError: true,
+		},
+		{
+			title: "stale price and return error",
+			mockCall: func() {
+				stale := priceChart(100)
+				stale.Status = entity.CacheStale
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(stale, nil)
+			},
+			args:    args{symbol: "AAPL", side: entity.Buy, quantity: 1},
+			isError: true,
+		},
+		{
+			title: "provider failure and return error",
+			mockCall: func() {
+				prices.EXPECT().Get(gomock.Any(), aapl).Return(entity.ChartResult{}, errors.New("upstream error"))
+			},
+			args:    args{symbol: "AAPL", side: entity.Buy, quantity: 1},
+			isError: true,
+		},
+		{
+			title:    "unsupported side and return error",
+			mockCall: func() {},
+			args:     args{symbol: "AAPL", side: "hold", quantity: 1},
+			isError:  true,
+		},
+		{
+			title:    "zero quantity and return error",
+			mockCall: func() {},
+			args:     args{symbol: "AAPL", side: entity.Buy},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := portfolioService.PlaceOrder(context.Background(), 1, test.args.symbol, test.args.side, test.args.quantity)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, 1, got.Id)
+				assert.Equal(t, "AAPL", got.Symbol)
+				assert.Equal(t, test.wantRealized, got.Realized)
+			}
+		})
+	}
+}
+
+func TestGetPortfolio(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockPortfolioStorage(cntr)
+	prices := mocks.NewMockPriceSource(cntr)
+	portfolioService := NewPortfolioService(logging.GetLogger("debug"), storage, prices, 1000)
+	account := entity.Account{UserId: 1, Cash: 500, Realized: 10}
+	positions := []entity.Position{
+		{Symbol: "AAPL", Quantity: 2, AverageCost: 100},
+		{Symbol: "MSFT", Quantity: 1, AverageCost: 200},
+	}
+	aapl := entity.ChartQuery{Symbol: "AAPL"}
+	msft := entity.ChartQuery{Symbol: "MSFT"}
+	storage.EXPECT().Account(gomock.Any(), 1, 1000.0).Return(account, positions, nil)
+	prices.EXPECT().GetMany(gomock.Any(), []entity.ChartQuery{aapl, msft}).Return(map[string]entity.ChartResult{
+		aapl.Key(): priceChart(150),
+		msft.Key(): {Err: errors.New("upstream error")},
+	})
+
+	got, err := portfolioService.Get(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, account, got.Account)
+	assert.Equal(t, []entity.Holding{
+		{Position: positions[0], Price: 150, MarketValue: 300, Unrealized: 100},
+		{Position: positions[1], Price: 200, MarketValue: 200},
+	}, got.Holdings)
+	assert.Equal(t, 100.0, got.Unrealized)
+	assert.Equal(t, 1000.0, got.Equity)
+}
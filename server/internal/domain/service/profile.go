@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const (
+	profileFor        = 24 * time.Hour
+	partialProfileFor = time.Hour
+)
+
+// ProfileProvider is implemented by the quote providers that know the
+// fundamentals of the companies.
+type ProfileProvider interface {
+	Name() string
+	GetProfile(ctx context.Context, symbol string) (entity.Profile, error)
+}
+
+type ProfileCache interface {
+	Set(key string, value string, expireAt time.Duration) error
+	Get(key string) (string, error)
+}
+
+type SymbolLookup interface {
+	Info(symbol string) (entity.SymbolInfo, bool)
+}
+
+type profileService struct {
+	logger    *logging.Logger
+	cache     ProfileCache
+	charts    PriceSource
+	symbols   SymbolLookup
+	providers []ProfileProvider
+}
+
+// NewProfileService returns a service that asks the quote providers that
+// support fundamentals in the given order, the rest of them are skipped.
+func NewProfileService(logger *logging.Logger, cache ProfileCache, charts PriceSource, symbols SymbolLookup, providers ...QuoteProvider) *profileService {
+	profiles := make([]ProfileProvider, 0, len(providers))
+	for _, provider := range providers {
+		if profile, ok := provider.(ProfileProvider); ok {
+			profiles = append(profiles, profile)
+		}
+	}
+	return &profileService{logger: logger, cache: cache, charts: charts, symbols: symbols, providers: profiles}
+}
+
+// Get returns the cached profile or fetches it from the providers. When none
+// of them has the fundamentals, a partial profile is built from the symbol
+// directory and the 52-week range of the daily chart and cached for less time.
+func (p *profileService) Get(ctx context.Context, symbol string) (entity.Profile, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if len(symbol) == 0 {
+		return entity.Profile{}, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	key := profileKey(symbol)
+	if cached, err := p.cache.Get(key); err == nil {
+		var profile entity.Profile
+		if err := json.Unmarshal([]byte(cached), &profile); err == nil {
+			return profile, nil
+		}
+		p.logger.Errorf("cannot decode cached profile = %v", key)
+	}
+	profile, err := p.fetch(ctx, symbol)
+	if err != nil {
+		if isNotExist(err) {
+			return entity.Profile{}, err
+		}
+		return p.fallback(ctx, symbol)
+	}
+	p.save(key, profile, profileFor)
+	return profile, nil
+}
+
+func (p *profileService) fetch(ctx context.Context, symbol string) (entity.Profile, error) {
+	if len(p.providers) == 0 {
+		return entity.Profile{}, errs.New(errs.Internal, "no provider supports profiles")
+	}
+	profile, err := firstAnswer(ctx, p.logger, "profile = "+symbol, p.providers, func(ctx context.Context, provider ProfileProvider) (entity.Profile, error) {
+		return provider.GetProfile(ctx, symbol)
+	})
+	if err != nil {
+		return entity.Profile{}, err
+	}
+	return p.complete(symbol, profile), nil
+}
+
+// fallback builds the partial profile, it fails only if neither the chart nor
+// the directory know the symbol.
+func (p *profileService) fallback(ctx context.Context, symbol string) (entity.Profile, error) {
+	profile := p.complete(symbol, entity.Profile{Symbol: symbol, Partial: true})
+	_, known := p.symbols.Info(symbol)
+	result, err := p.charts.Get(ctx, entity.ChartQuery{Symbol: symbol, Range: "1y", Interval: "1d"})
+	if err != nil {
+		if !known || isNotExist(err) {
+			return entity.Profile{}, err
+		}
+		p.logger.Errorf("cannot get 52-week range of %v due to : %v", symbol, err)
+		return profile, nil
+	}
+	if profile.Exchange == "" {
+		profile.Exchange = result.Series.Exchange
+	}
+	profile.Week52Low, profile.Week52High = weekRange(result.Series.Bars)
+	p.save(profileKey(symbol), profile, partialProfileFor)
+	return profile, nil
+}
+
+// complete fills what the provider left empty from the symbol directory.
+func (p *profileService) complete(symbol string, profile entity.Profile) entity.Profile {
+	profile.Symbol = symbol
+	info, ok := p.symbols.Info(symbol)
+	if !ok {
+		return profile
+	}
+	if profile.Name == "" {
+		profile.Name = info.Name
+	}
+	if profile.Exchange == "" {
+		profile.Exchange = info.Exchange
+	}
+	if profile.Currency == "" {
+		profile.Currency = info.Currency
+	}
+	return profile
+}
+
+func (p *profileService) save(key string, profile entity.Profile, duration time.Duration) {
+	payload, err := json.Marshal(profile)
+	if err != nil {
+		p.logger.Errorf("cannot encode profile = %v due to : %v", key, err)
+		return
+	}
+	if err := p.cache.Set(key, string(payload), duration); err != nil {
+		p.logger.Errorf("cannot save profile to cache due to : %v", err)
+	}
+}
+
+func weekRange(bars []entity.Bar) (*float64, *float64) {
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, bar := range bars {
+		if bar.Missing {
+			continue
+		}
+		low = math.Min(low, bar.Low)
+		high = math.Max(high, bar.High)
+	}
+	if math.IsInf(low, 1) {
+		return nil, nil
+	}
+	return &low, &high
+}
+
+func profileKey(symbol string) string {
+	return "profile:" + symbol
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+// fundamentalsProvider is a quote provider that supports profiles.
+type fundamentalsProvider struct {
+	*mocks.MockQuoteProvider
+	profiles *mocks.MockProfileProvider
+}
+
+func (f fundamentalsProvider) GetProfile(ctx context.Context, symbol string) (entity.Profile, error) {
+	return f.profiles.GetProfile(ctx, symbol)
+}
+
+func TestGetProfile(t *testing.T) {
+	cntr := gomock.NewController(t)
+	cache := mocks.NewMockProfileCache(cntr)
+	charts := mocks.NewMockPriceSource(cntr)
+	symbols := mocks.NewMockSymbolLookup(cntr)
+	chartOnly := mocks.NewMockQuoteProvider(cntr)
+	profiles := mocks.NewMockProfileProvider(cntr)
+	provider := fundamentalsProvider{MockQuoteProvider: mocks.NewMockQuoteProvider(cntr), profiles: profiles}
+	profileService := NewProfileService(logging.GetLogger("debug"), cache, charts, symbols, chartOnly, provider)
+
+	capitalization, pe := 2.5e12, 28.5
+	full := entity.Profile{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NMS", Sector: "Technology", Currency: "USD", MarketCap: &capitalization, PERatio: &pe}
+	cachedFull, _ := json.Marshal(full)
+	info := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Currency: "USD"}
+	yearQuery := entity.ChartQuery{Symbol: "AAPL", Range: "1y", Interval: "1d"}
+	year := entity.Series{Symbol: "AAPL", Exchange: "NMS", Bars: []entity.Bar{
+		{Timestamp: time.Unix(100, 0), Low: 120, High: 130},
+		{Timestamp: time.Unix(200, 0), Missing: true},
+		{Timestamp: time.Unix(300, 0), Low: 110, High: 190},
+	}}
+	low, high := 110.0, 190.0
+	partial := entity.Profile{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Currency: "USD", Week52Low: &low, Week52High: &high, Partial: true}
+	cachedPartial, _ := json.Marshal(partial)
+	notFound := errs.New(errs.NotExist, errs.Code("symbol not found"), errs.Parameter("symbol"))
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		symbol   string
+		want     entity.Profile
+		isError  bool
+	}{
+		{
+			title: "cached profile is returned",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:AAPL").Return(string(cachedFull), nil)
+			},
+			symbol: " aapl",
+			want:   full,
+		},
+		{
+			title: "profile of the provider is cached for a long time",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:AAPL").Return("", errors.New("redis: nil"))
+				profiles.EXPECT().GetProfile(gomock.Any(), "AAPL").Return(full, nil)
+				symbols.EXPECT().Info("AAPL").Return(info, true)
+				cache.EXPECT().Set("profile:AAPL", string(cachedFull), profileFor).Return(nil)
+			},
+			symbol: "AAPL",
+			want:   full,
+		},
+		{
+			title: "provider failure falls back to the directory and the chart",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:AAPL").Return("", errors.New("redis: nil"))
+				profiles.EXPECT().GetProfile(gomock.Any(), "AAPL").Return(entity.Profile{}, errors.New("http client error"))
+				provider.MockQuoteProvider.EXPECT().Name().Return("yahoo")
+				symbols.EXPECT().Info("AAPL").Return(info, true).Times(2)
+				charts.EXPECT().Get(gomock.Any(), yearQuery).Return(entity.ChartResult{Series: year}, nil)
+				cache.EXPECT().Set("profile:AAPL", string(cachedPartial), partialProfileFor).Return(nil)
+			},
+			symbol: "AAPL",
+			want:   partial,
+		},
+		{
+			title: "chart failure of a known symbol returns the directory entry",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:AAPL").Return("", errors.New("redis: nil"))
+				profiles.EXPECT().GetProfile(gomock.Any(), "AAPL").Return(entity.Profile{}, errors.New("http client error"))
+				provider.MockQuoteProvider.EXPECT().Name().Return("yahoo")
+				symbols.EXPECT().Info("AAPL").Return(info, true).Times(2)
+				charts.EXPECT().Get(gomock.Any(), yearQuery).Return(entity.ChartResult{}, errors.New("http client error"))
+			},
+			symbol: "AAPL",
+			want:   entity.Profile{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Currency: "USD", Partial: true},
+		},
+		{
+			title: "unknown symbol and return error",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:NOPE").Return("", errors.New("redis: nil"))
+				profiles.EXPECT().GetProfile(gomock.Any(), "NOPE").Return(entity.Profile{}, notFound)
+				provider.MockQuoteProvider.EXPECT().Name().Return("yahoo")
+			},
+			symbol:  "NOPE",
+			isError: true,
+		},
+		{
+			title: "chart failure of an unknown symbol and return error",
+			mockCall: func() {
+				cache.EXPECT().Get("profile:NOPE").Return("", errors.New("redis: nil"))
+				profiles.EXPECT().GetProfile(gomock.Any(), "NOPE").Return(entity.Profile{}, errors.New("http client error"))
+				provider.MockQuoteProvider.EXPECT().Name().Return("yahoo")
+				symbols.EXPECT().Info("NOPE").Return(entity.SymbolInfo{}, false).Times(2)
+				charts.EXPECT().Get(gomock.Any(), entity.ChartQuery{Symbol: "NOPE", Range: "1y", Interval: "1d"}).Return(entity.ChartResult{}, errors.New("http client error"))
+			},
+			symbol:  "NOPE",
+			isError: true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			symbol:   " ",
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := profileService.Get(context.Background(), test.symbol)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestGetProfileWithoutFundamentals(t *testing.T) {
+	cntr := gomock.NewController(t)
+	cache := mocks.NewMockProfileCache(cntr)
+	charts := mocks.NewMockPriceSource(cntr)
+	symbols := mocks.NewMockSymbolLookup(cntr)
+	profileService := NewProfileService(logging.GetLogger("debug"), cache, charts, symbols, mocks.NewMockQuoteProvider(cntr))
+	want := entity.Profile{Symbol: "AAPL", Exchange: "NMS", Partial: true}
+	cached, _ := json.Marshal(want)
+
+	cache.EXPECT().Get("profile:AAPL").Return("", errors.New("redis: nil"))
+	symbols.EXPECT().Info("AAPL").Return(entity.SymbolInfo{}, false).Times(2)
+	charts.EXPECT().Get(gomock.Any(), gomock.Any()).Return(entity.ChartResult{Series: entity.Series{Symbol: "AAPL", Exchange: "NMS"}}, nil)
+	cache.EXPECT().Set("profile:AAPL", string(cached), partialProfileFor).Return(nil)
+
+	got, err := profileService.Get(context.Background(), "AAPL")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+type namedProvider interface {
+	Name() string
+}
+
+// firstAnswer asks the providers in the given order and returns the first
+// answer. The symbol is reported as not existing only when every provider
+// said so, otherwise a failure of a provider wins, so an outage of one
+// provider isn't turned into a missing symbol by the next one. A provider
+// that doesn't support the query is skipped, the query is rejected only when
+// no provider supports it.
+func firstAnswer[P namedProvider, T any](
+	ctx context.Context,
+	logger *logging.Logger,
+	what string,
+	providers []P,
+	ask func(ctx context.Context, provider P) (T, error),
+) (T, error) {
+	var zero T
+	var notExist, failure, unsupported error
+	for i, provider := range providers {
+		answer, err := ask(ctx, provider)
+		if err == nil {
+			return answer, nil
+		}
+		if kindOf(err) == errs.Invalid {
+			logger.Debugf("provider %v skipped %v : %v", provider.Name(), what, err)
+			unsupported = err
+			continue
+		}
+		logger.Warnf("provider %v couldn't get %v due to : %v", provider.Name(), what, err)
+		if kindOf(err) == errs.NotExist {
+			notExist = err
+		} else {
+			failure = err
+		}
+		if ctx.Err() != nil && i < len(providers)-1 {
+			if failure == nil {
+				failure = errs.New(errs.Internal, ctx.Err())
+			}
+			break
+		}
+	}
+	if failure != nil {
+		return zero, failure
+	}
+	if notExist != nil {
+		return zero, notExist
+	}
+	var e *errs.Error
+	if errors.As(unsupported, &e) {
+		return zero, errs.New(errs.Validation, e.Code, e.Param, e.Err)
+	}
+	return zero, errs.New(errs.Internal, "no provider configured")
+}
+
+func isNotExist(err error) bool {
+	return kindOf(err) == errs.NotExist
+}
+
+// kindOf returns the kind of the error, Other when it isn't classified.
+func kindOf(err error) errs.Kind {
+	var e *errs.Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return errs.Other
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+const upstreamTimeout = 30 * time.Second
+
+type QuoteProvider interface {
+	Name() string
+	GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error)
+}
+
+type quoteService struct {
+	logger    *logging.Logger
+	metric    metric.Metric
+	providers []QuoteProvider
+	group     singleflight.Group
+}
+
+// NewQuoteService returns a service that asks the providers in the given order
+// and falls back to the next one when a provider fails. Concurrent requests
+// for the same chart share a single upstream call.
+func NewQuoteService(logger *logging.Logger, metric metric.Metric, providers ...QuoteProvider) *quoteService {
+	return &quoteService{logger: logger, metric: metric, providers: providers}
+}
+
+func (q *quoteService) GetChart(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	if len(query.Symbol) == 0 {
+		return entity.Series{}, errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	if len(q.providers) == 0 {
+		return entity.Series{}, errs.New(errs.Internal, "no quote provider configured")
+	}
+	// the upstream call is detached from the caller, so that one waiter leaving
+	// doesn't cancel the request for everybody else.
+	originated := false
+	ch := q.group.DoChan(query.Key(), func() (interface{}, error) {
+		originated = true
+		q.metric.UpstreamRequestCounter.WithLabelValues("origin").Inc()
+		upstreamCtx, cancel := context.WithTimeout(context.Background(), upstreamTimeout)
+		defer cancel()
+		return q.fetch(upstreamCtx, query)
+	})
+	select {
+	case <-ctx.Done():
+		return entity.Series{}, errs.New(errs.Internal, ctx.Err())
+	case res := <-ch:
+		if !originated {
+			q.metric.UpstreamRequestCounter.WithLabelValues("coalesced").Inc()
+		}
+		if res.Err != nil {
+			return entity.Series{}, res.Err
+		}
+		return res.Val.(entity.Series), nil
+	}
+}
+
+func (q *quoteService) fetch(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+	return firstAnswer(ctx, q.logger, "chart = "+query.Key(), q.providers, func(ctx context.Context, provider QuoteProvider) (entity.Series, error) {
+		return provider.GetChart(ctx, query)
+	})
+}
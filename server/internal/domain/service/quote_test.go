@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/metric"
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetChart(t *testing.T) {
+	cntr := gomock.NewController(t)
+	primary := mocks.NewMockQuoteProvider(cntr)
+	secondary := mocks.NewMockQuoteProvider(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	quoteService := NewQuoteService(logging.GetLogger("debug"), metric.NewMetric(prometheusClient.Registry()), primary, secondary)
+	chart := entity.Series{Symbol: "TEST"}
+	query := entity.ChartQuery{Symbol: "TEST", Range: "1mo", Interval: "1d"}
+	type mockCall func()
+	testCases := []struct {
+		title      string
+		mockCall   mockCall
+		input      entity.ChartQuery
+		isError    bool
+		notExist   bool
+		validation bool
+		want       entity.Series
+	}{
+		{
+			title: "first provider returns chart",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+			},
+			input:   query,
+			want:    chart,
+			isError: false,
+		},
+		{
+			title: "first provider failed and second one returns chart",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+			},
+			input:   query,
+			want:    chart,
+			isError: false,
+		},
+		{
+			title: "all providers failed and return error",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+				secondary.EXPECT().Name().Return("secondary")
+			},
+			input:   query,
+			isError: true,
+		},
+		{
+			title: "outage of a provider is reported over unknown symbol",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found")))
+				secondary.EXPECT().Name().Return("secondary")
+			},
+			input:   query,
+			isError: true,
+		},
+		{
+			title: "symbol unknown to every provider",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found")))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.NotExist, errs.Code("symbol not found")))
+				secondary.EXPECT().Name().Return("secondary")
+			},
+			input:    query,
+			isError:  true,
+			notExist: true,
+		},
+		{
+			title: "provider that doesn't support the query is skipped",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.Invalid, errs.Code("only daily interval"), errs.Parameter("interval"), "only daily interval"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(chart, nil)
+			},
+			input: query,
+			want:  chart,
+		},
+		{
+			title: "outage of a provider is reported over unsupported query",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.Invalid, errs.Code("only daily interval"), errs.Parameter("interval"), "only daily interval"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errors.New("upstream error"))
+				secondary.EXPECT().Name().Return("secondary")
+			},
+			input:   query,
+			isError: true,
+		},
+		{
+			title: "query unsupported by every provider",
+			mockCall: func() {
+				primary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.Invalid, errs.Code("only daily interval"), errs.Parameter("interval"), "only daily interval"))
+				primary.EXPECT().Name().Return("primary")
+				secondary.EXPECT().GetChart(gomock.Any(), query).Return(entity.Series{}, errs.New(errs.Invalid, errs.Code("only daily interval"), errs.Parameter("interval"), "only daily interval"))
+				secondary.EXPECT().Name().Return("secondary")
+			},
+			input:      query,
+			isError:    true,
+			validation: true,
+		},
+		{
+			title:      "empty symbol and return error",
+			mockCall:   func() {},
+			input:      entity.ChartQuery{},
+			isError:    true,
+			validation: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := quoteService.GetChart(context.Background(), test.input)
+			if test.isError {
+				assert.Error(t, err)
+				var e *errs.Error
+				assert.Equal(t, test.notExist, errors.As(err, &e) && e.Kind == errs.NotExist)
+				assert.Equal(t, test.validation, errors.As(err, &e) && e.Kind == errs.Validation)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+// waitingContext reports on the channel when a caller starts waiting for its
+// result, which happens only after the caller has joined the upstream call.
+type waitingContext struct {
+	context.Context
+	waiting chan<- struct{}
+	once    sync.Once
+}
+
+func (c *waitingContext) Done() <-chan struct{} {
+	c.once.Do(func() { c.waiting <- struct{}{} })
+	return c.Context.Done()
+}
+
+func TestGetChartCoalescing(t *testing.T) {
+	cntr := gomock.NewController(t)
+	provider := mocks.NewMockQuoteProvider(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	metrics := metric.NewMetric(prometheusClient.Registry())
+	quoteService := NewQuoteService(logging.GetLogger("debug"), metrics, provider)
+	query := entity.ChartQuery{Symbol: "TEST", Range: "1d"}
+	chart := entity.Series{Symbol: "TEST"}
+	const callers = 10
+	waiting := make(chan struct{}, callers)
+
+	// the provider answers once every caller waits for the shared request
+	provider.EXPECT().GetChart(gomock.Any(), query).DoAndReturn(
+		func(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+			for i := 0; i < callers; i++ {
+				<-waiting
+			}
+			return chart, nil
+		}).Times(1)
+
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx := &waitingContext{Context: context.Background(), waiting: waiting}
+			got, err := quoteService.GetChart(ctx, query)
+			assert.NoError(t, err)
+			assert.Equal(t, chart, got)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, float64(1), testutil.ToFloat64(metrics.UpstreamRequestCounter.WithLabelValues("origin")))
+	assert.Equal(t, float64(callers-1), testutil.ToFloat64(metrics.UpstreamRequestCounter.WithLabelValues("coalesced")))
+}
+
+func TestGetChartCallerCancelled(t *testing.T) {
+	cntr := gomock.NewController(t)
+	provider := mocks.NewMockQuoteProvider(cntr)
+	prometheusClient := metric.NewPrometheusClient(true)
+	quoteService := NewQuoteService(logging.GetLogger("debug"), metric.NewMetric(prometheusClient.Registry()), provider)
+	query := entity.ChartQuery{Symbol: "TEST"}
+
+	release := make(chan struct{})
+	done := make(chan struct{})
+	provider.EXPECT().GetChart(gomock.Any(), query).DoAndReturn(
+		func(ctx context.Context, query entity.ChartQuery) (entity.Series, error) {
+			defer close(done)
+			<-release
+			return entity.Series{}, ctx.Err()
+		})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := quoteService.GetChart(ctx, query)
+	assert.Error(t, err)
+	close(release)
+	<-done
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"sync"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type quote struct {
+	price         float64
+	previousClose float64
+	exchange      string
+}
+
+// quoteQueue keeps only the latest quote of every symbol until it's drained,
+// so chart listeners never block the chart requests.
+type quoteQueue struct {
+	mu      sync.Mutex
+	pending map[string]quote
+	wake    chan struct{}
+}
+
+func newQuoteQueue() *quoteQueue {
+	return &quoteQueue{pending: make(map[string]quote), wake: make(chan struct{}, 1)}
+}
+
+func (q *quoteQueue) push(symbol string, series entity.Series) {
+	if series.Price <= 0 {
+		return
+	}
+	q.mu.Lock()
+	previousClose := series.PreviousClose
+	if previousClose <= 0 {
+		previousClose = q.pending[symbol].previousClose
+	}
+	q.pending[symbol] = quote{price: series.Price, previousClose: previousClose, exchange: series.Exchange}
+	q.mu.Unlock()
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *quoteQueue) drain() map[string]quote {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pending := q.pending
+	q.pending = make(map[string]quote)
+	return pending
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
+)
+
+type SessionCalendar interface {
+	ForSymbol(symbol string, exchangeName string) *calendar.Exchange
+}
+
+type spanUnit int
+
+const (
+	minuteUnit spanUnit = iota
+	dayUnit
+	weekUnit
+	monthUnit
+)
+
+// span is an interval of bars like 15m, 1d, 1wk or 3mo.
+type span struct {
+	count int
+	unit  spanUnit
+}
+
+var spanPattern = regexp.MustCompile(`^(\d{1,3})(m|h|d|w|wk|mo)$`)
+
+func parseSpan(interval string) (span, bool) {
+	match := spanPattern.FindStringSubmatch(interval)
+	if match == nil {
+		return span{}, false
+	}
+	count, _ := strconv.Atoi(match[1])
+	if count == 0 {
+		return span{}, false
+	}
+	switch match[2] {
+	case "m":
+		return span{count: count, unit: minuteUnit}, true
+	case "h":
+		return span{count: count * 60, unit: minuteUnit}, true
+	case "d":
+		return span{count: count, unit: dayUnit}, true
+	case "w", "wk":
+		return span{count: count, unit: weekUnit}, true
+	default:
+		return span{count: count, unit: monthUnit}, true
+	}
+}
+
+// approximate is used only to compare spans of different units.
+func (s span) approximate() time.Duration {
+	switch s.unit {
+	case minuteUnit:
+		return time.Duration(s.count) * time.Minute
+	case dayUnit:
+		return time.Duration(s.count) * 24 * time.Hour
+	case weekUnit:
+		return time.Duration(s.count) * 7 * 24 * time.Hour
+	default:
+		return time.Duration(s.count) * 30 * 24 * time.Hour
+	}
+}
+
+// multipleOf reports whether a bucket of the span holds a whole number of
+// bars of the other one. Days hold the intraday bars that divide a day, weeks
+// hold days and months hold days only.
+func (s span) multipleOf(other span) bool {
+	switch {
+	case s.unit == other.unit:
+		return s.count%other.count == 0
+	case other.unit == minuteUnit:
+		return 24*60%other.count == 0
+	case other.unit == dayUnit && s.unit == weekUnit:
+		return 7%other.count == 0
+	case other.unit == dayUnit && s.unit == monthUnit:
+		return other.count == 1
+	}
+	return false
+}
+
+// bucket returns the start of the bucket of t. Intraday buckets are counted
+// from the session open, so they never span two sessions, and the other
+// buckets follow the local calendar of the exchange.
+func (s span) bucket(t time.Time, exchange *calendar.Exchange) time.Time {
+	local := t.In(exchange.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, exchange.Location)
+	switch s.unit {
+	case minuteUnit:
+		open := exchange.SessionOpen(local)
+		size := time.Duration(s.count) * time.Minute
+		offset := local.Sub(open)
+		n := offset / size
+		if offset < 0 && offset%size != 0 {
+			n--
+		}
+		return open.Add(n * size)
+	case dayUnit:
+		return midnight
+	case weekUnit:
+		return midnight.AddDate(0, 0, -(int(local.Weekday())+6)%7)
+	default:
+		month := (int(local.Month())-1)/s.count*s.count + 1
+		return time.Date(local.Year(), time.Month(month), 1, 0, 0, 0, 0, exchange.Location)
+	}
+}
+
+type resampler struct {
+	calendar SessionCalendar
+}
+
+// NewResampler returns the aggregation of chart bars into coarser intervals.
+func NewResampler(calendar SessionCalendar) *resampler {
+	return &resampler{calendar: calendar}
+}
+
+// Validate reports whether the bars of the query can be aggregated into the
+// interval, so that an unsupported aggregate is rejected before the chart is
+// fetched.
+func (r *resampler) Validate(query entity.ChartQuery, interval string) error {
+	_, err := r.target(query, interval)
+	return err
+}
+
+// Resample aggregates the bars of the chart into the interval. The bucket
+// takes the open of its first bar, the close of its last one, the extremes
+// of high and low and the sum of volumes. Missing bars are skipped and
+// buckets without bars are left out.
+func (r *resampler) Resample(query entity.ChartQuery, series entity.Series, interval string) (entity.Series, error) {
+	target, err := r.target(query, interval)
+	if err != nil {
+		return entity.Series{}, err
+	}
+	exchange := r.calendar.ForSymbol(query.Symbol, series.Exchange)
+	resampled := series
+	resampled.Bars = make([]entity.Bar, 0)
+	var current time.Time
+	for _, bar := range series.Bars {
+		if bar.Missing {
+			continue
+		}
+		start := target.bucket(bar.Timestamp, exchange)
+		last := len(resampled.Bars) - 1
+		if last < 0 || !start.Equal(current) {
+			current = start
+			bar.Timestamp = start.UTC()
+			resampled.Bars = append(resampled.Bars, bar)
+			continue
+		}
+		aggregated := &resampled.Bars[last]
+		aggregated.High = math.Max(aggregated.High, bar.High)
+		aggregated.Low = math.Min(aggregated.Low, bar.Low)
+		aggregated.Close = bar.Close
+		aggregated.Volume += bar.Volume
+	}
+	return resampled, nil
+}
+
+// target returns the span of the interval, it must be an integer multiple of
+// the interval of the query.
+func (r *resampler) target(query entity.ChartQuery, interval string) (span, error) {
+	target, ok := parseSpan(interval)
+	if !ok || (target.unit == minuteUnit && target.count > 24*60) || (target.unit != minuteUnit && target.unit != monthUnit && target.count != 1) {
+		return span{}, errs.New(errs.Validation, errs.Code("unsupported aggregate interval"), errs.Parameter("aggregate"))
+	}
+	if target.unit == monthUnit && 12%target.count != 0 {
+		return span{}, errs.New(errs.Validation, errs.Code("months of aggregate must divide a year"), errs.Parameter("aggregate"))
+	}
+	from, ok := parseSpan(intervalOf(query))
+	if !ok || target.approximate() < from.approximate() {
+		return span{}, errs.New(errs.Validation, errs.Code("aggregate must be coarser than interval"), errs.Parameter("aggregate"))
+	}
+	if !target.multipleOf(from) {
+		return span{}, errs.New(errs.Validation, errs.Code("aggregate must be a multiple of interval"), errs.Parameter("aggregate"))
+	}
+	return target, nil
+}
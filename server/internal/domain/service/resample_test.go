@@ -0,0 +1,154 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/calendar"
+	"github.com/stretchr/testify/assert"
+)
+
+// bars builds a series of the given prices, a NaN close makes a missing bar.
+func bars(timestamps []time.Time, open, high, low, close, volume []float64) entity.Series {
+	series := entity.Series{Symbol: "AAPL", Exchange: "NMS", Bars: make([]entity.Bar, len(timestamps))}
+	for i, timestamp := range timestamps {
+		if math.IsNaN(close[i]) {
+			series.Bars[i] = entity.Bar{Timestamp: timestamp.UTC(), Missing: true}
+			continue
+		}
+		series.Bars[i] = entity.Bar{Timestamp: timestamp.UTC(), Open: open[i], High: high[i], Low: low[i], Close: close[i], Volume: volume[i]}
+	}
+	return series
+}
+
+func TestResample(t *testing.T) {
+	marketCalendar, err := calendar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	resampler := NewResampler(marketCalendar)
+	open := time.Date(2022, time.October, 10, 9, 30, 0, 0, newYork)
+	minutes := bars(
+		[]time.Time{open.Add(-time.Minute), open, open.Add(time.Minute), open.Add(14 * time.Minute), open.Add(15 * time.Minute)},
+		[]float64{9, 10, 0, 11, 13},
+		[]float64{9.5, 12, 0, 11.5, 14},
+		[]float64{8.5, 9.5, 0, 10, 12.5},
+		[]float64{9, 11, math.NaN(), 10.5, 13.5},
+		[]float64{1, 10, 0, 20, 30},
+	)
+	days := bars(
+		[]time.Time{open.AddDate(0, 0, -10), open.AddDate(0, 0, -4), open.AddDate(0, 0, -3), open},
+		[]float64{1, 2, 3, 4},
+		[]float64{2, 5, 4, 5},
+		[]float64{0.5, 1, 2, 3},
+		[]float64{1.5, 3, 3.5, 4.5},
+		[]float64{100, 200, 300, 400},
+	)
+	testCases := []struct {
+		title    string
+		query    entity.ChartQuery
+		chart    entity.Series
+		interval string
+		want     entity.Series
+		isError  bool
+	}{
+		{
+			title:    "minutes are counted from the session open and gaps are skipped",
+			query:    entity.ChartQuery{Symbol: "AAPL", Interval: "1m"},
+			chart:    minutes,
+			interval: "15m",
+			want: bars(
+				[]time.Time{open.Add(-15 * time.Minute), open, open.Add(15 * time.Minute)},
+				[]float64{9, 10, 13},
+				[]float64{9.5, 12, 14},
+				[]float64{8.5, 9.5, 12.5},
+				[]float64{9, 10.5, 13.5},
+				[]float64{1, 30, 30},
+			),
+		},
+		{
+			title:    "days are aggregated into weeks starting on monday",
+			query:    entity.ChartQuery{Symbol: "AAPL"},
+			chart:    days,
+			interval: "1wk",
+			want: bars(
+				[]time.Time{time.Date(2022, time.September, 26, 0, 0, 0, 0, newYork), time.Date(2022, time.October, 3, 0, 0, 0, 0, newYork), time.Date(2022, time.October, 10, 0, 0, 0, 0, newYork)},
+				[]float64{1, 2, 4},
+				[]float64{2, 5, 5},
+				[]float64{0.5, 1, 3},
+				[]float64{1.5, 3.5, 4.5},
+				[]float64{100, 500, 400},
+			),
+		},
+		{
+			title:    "days are aggregated into quarters",
+			query:    entity.ChartQuery{Symbol: "AAPL", Interval: "1d"},
+			chart:    days,
+			interval: "3mo",
+			want: bars(
+				[]time.Time{time.Date(2022, time.July, 1, 0, 0, 0, 0, newYork), time.Date(2022, time.October, 1, 0, 0, 0, 0, newYork)},
+				[]float64{1, 2},
+				[]float64{2, 5},
+				[]float64{0.5, 1},
+				[]float64{1.5, 4.5},
+				[]float64{100, 900},
+			),
+		},
+		{
+			title:    "aggregate finer than the interval",
+			query:    entity.ChartQuery{Symbol: "AAPL", Interval: "1d"},
+			chart:    days,
+			interval: "15m",
+			isError:  true,
+		},
+		{
+			title:    "aggregate that isn't a multiple of the interval",
+			query:    entity.ChartQuery{Symbol: "AAPL", Interval: "15m"},
+			chart:    days,
+			interval: "20m",
+			isError:  true,
+		},
+		{
+			title:    "weeks aggregated into months",
+			query:    entity.ChartQuery{Symbol: "AAPL", Interval: "1wk"},
+			chart:    days,
+			interval: "1mo",
+			isError:  true,
+		},
+		{
+			title:    "months that don't divide a year",
+			query:    entity.ChartQuery{Symbol: "AAPL"},
+			chart:    days,
+			interval: "5mo",
+			isError:  true,
+		},
+		{
+			title:    "several days",
+			query:    entity.ChartQuery{Symbol: "AAPL"},
+			chart:    days,
+			interval: "2d",
+			isError:  true,
+		},
+		{
+			title:    "malformed interval",
+			query:    entity.ChartQuery{Symbol: "AAPL"},
+			chart:    days,
+			interval: "fortnight",
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			got, err := resampler.Resample(test.query, test.chart, test.interval)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	maxSearchQuery     = 64
+	maxIndexScan       = 1000
+	// fuzzyThreshold is the number of prefix matches below which the
+	// directory is asked for fuzzy matches.
+	fuzzyThreshold = 3
+)
+
+// column sizes of the symbol directory
+const (
+	maxNameLength     = 200
+	maxExchangeLength = 32
+	maxTypeLength     = 16
+	maxCurrencyLength = 8
+)
+
+type SymbolDirectory interface {
+	Upsert(ctx context.Context, infos []entity.SymbolInfo) error
+	Directory(ctx context.Context) ([]entity.SymbolInfo, error)
+	Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error)
+}
+
+type symbolService struct {
+	logger    *logging.Logger
+	directory SymbolDirectory
+	mu        sync.RWMutex
+	index     *symbolIndex
+}
+
+func NewSymbolService(logger *logging.Logger, directory SymbolDirectory) *symbolService {
+	return &symbolService{logger: logger, directory: directory, index: newSymbolIndex(nil)}
+}
+
+// Load builds the in-memory index from the stored directory.
+func (s *symbolService) Load(ctx context.Context) error {
+	infos, err := s.directory.Directory(ctx)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.index = newSymbolIndex(infos)
+	s.mu.Unlock()
+	s.logger.Infof("symbol index is loaded with %v symbols", len(infos))
+	return nil
+}
+
+// Import stores the entries in the directory and rebuilds the index. Entries
+// without a symbol or a name are skipped, the last of the duplicates wins.
+func (s *symbolService) Import(ctx context.Context, infos []entity.SymbolInfo) (int, error) {
+	positions := make(map[string]int, len(infos))
+	valid := make([]entity.SymbolInfo, 0, len(infos))
+	for _, info := range infos {
+		info, ok := normalizeSymbolInfo(info)
+		if !ok {
+			s.logger.Warnf("skip incorrect directory entry %+v", info)
+			continue
+		}
+		if i, ok := positions[info.Symbol]; ok {
+			valid[i] = info
+			continue
+		}
+		positions[info.Symbol] = len(valid)
+		valid = append(valid, info)
+	}
+	if err := s.directory.Upsert(ctx, valid); err != nil {
+		return 0, err
+	}
+	return len(valid), s.Load(ctx)
+}
+
+// Search returns the symbols for the autocomplete. Symbols and words of the
+// names that start with the query are found in the index, the directory is
+// asked for fuzzy matches only when the index finds fewer than a few. A limit
+// above the maximum is capped.
+func (s *symbolService) Search(ctx context.Context, query string, limit int) ([]entity.SymbolInfo, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, errs.New(errs.Validation, errs.Code("query is empty"), errs.Parameter("q"))
+	}
+	if len(query) > maxSearchQuery {
+		return nil, errs.New(errs.Validation, errs.Code("query is too long"), errs.Parameter("q"))
+	}
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	if limit < 0 {
+		return nil, errs.New(errs.Validation, errs.Code("limit is out of range"), errs.Parameter("limit"))
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+	s.mu.RLock()
+	found := s.index.prefix(query, limit)
+	s.mu.RUnlock()
+	if len(found) == limit || len(found) >= fuzzyThreshold {
+		return found, nil
+	}
+	fuzzy, err := s.directory.Search(ctx, query, limit)
+	if err != nil {
+		if len(found) > 0 {
+			s.logger.Errorf("cannot search directory for %v due to : %v", query, err)
+			return found, nil
+		}
+		return nil, err
+	}
+	seen := make(map[string]bool, len(found))
+	for _, info := range found {
+		seen[info.Symbol] = true
+	}
+	for _, info := range fuzzy {
+		if len(found) == limit {
+			break
+		}
+		if !seen[info.Symbol] {
+			seen[info.Symbol] = true
+			found = append(found, info)
+		}
+	}
+	return found, nil
+}
+
+// Info returns the directory entry of the symbol.
+func (s *symbolService) Info(symbol string) (entity.SymbolInfo, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.index.lookup(strings.ToUpper(strings.TrimSpace(symbol)))
+}
+
+// normalizeSymbolInfo reports whether the entry fits the directory, a name
+// longer than its column is truncated.
+func normalizeSymbolInfo(info entity.SymbolInfo) (entity.SymbolInfo, bool) {
+	info.Symbol = strings.ToUpper(clean(info.Symbol))
+	info.Name = truncate(clean(info.Name), maxNameLength)
+	info.Exchange = strings.ToUpper(clean(info.Exchange))
+	info.Type = strings.ToLower(clean(info.Type))
+	info.Currency = strings.ToUpper(clean(info.Currency))
+	ok := info.Symbol != "" && utf8.RuneCountInString(info.Symbol) <= maxSymbolLength && info.Name != "" &&
+		utf8.RuneCountInString(info.Exchange) <= maxExchangeLength &&
+		utf8.RuneCountInString(info.Type) <= maxTypeLength &&
+		utf8.RuneCountInString(info.Currency) <= maxCurrencyLength
+	return info, ok
+}
+
+// clean drops the invalid UTF-8 the database rejects and the surrounding spaces.
+func clean(value string) string {
+	return strings.TrimSpace(strings.ToValidUTF8(value, ""))
+}
+
+// truncate cuts the value to the length in characters, as the columns count them.
+func truncate(value string, length int) string {
+	if utf8.RuneCountInString(value) <= length {
+		return value
+	}
+	return strings.TrimSpace(string([]rune(value)[:length]))
+}
+
+type indexKey struct {
+	key      string
+	position int
+}
+
+// symbolIndex answers prefix queries from memory. The symbols and the names
+// with each of their words are kept upper cased and sorted for binary search.
+type symbolIndex struct {
+	infos   []entity.SymbolInfo
+	symbols []indexKey
+	names   []indexKey
+}
+
+func newSymbolIndex(infos []entity.SymbolInfo) *symbolIndex {
+	index := &symbolIndex{infos: infos, symbols: make([]indexKey, len(infos))}
+	for i, info := range infos {
+		index.symbols[i] = indexKey{key: strings.ToUpper(info.Symbol), position: i}
+		name := strings.ToUpper(info.Name)
+		index.names = append(index.names, indexKey{key: name, position: i})
+		words := strings.FieldsFunc(name, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		for _, word := range words {
+			if word != name {
+				index.names = append(index.names, indexKey{key: word, position: i})
+			}
+		}
+	}
+	byKey := func(keys []indexKey) func(i, j int) bool {
+		return func(i, j int) bool { return keys[i].key < keys[j].key }
+	}
+	sort.Slice(index.symbols, byKey(index.symbols))
+	sort.Slice(index.names, byKey(index.names))
+	return index
+}
+
+// prefix returns the entries whose symbol starts with the query, shorter
+// symbols first, followed by the entries with a matching name.
+func (i *symbolIndex) prefix(query string, limit int) []entity.SymbolInfo {
+	query = strings.ToUpper(query)
+	bySymbol := i.scan(i.symbols, query)
+	sort.Slice(bySymbol, func(a, b int) bool {
+		first, second := i.infos[bySymbol[a]].Symbol, i.infos[bySymbol[b]].Symbol
+		if len(first) != len(second) {
+			return len(first) < len(second)
+		}
+		return first < second
+	})
+	byName := i.scan(i.names, query)
+	sort.Slice(byName, func(a, b int) bool { return i.infos[byName[a]].Symbol < i.infos[byName[b]].Symbol })
+
+	found := make([]entity.SymbolInfo, 0, limit)
+	seen := make(map[int]bool)
+	for _, position := range append(bySymbol, byName...) {
+		if len(found) == limit {
+			break
+		}
+		if !seen[position] {
+			seen[position] = true
+			found = append(found, i.infos[position])
+		}
+	}
+	return found
+}
+
+func (i *symbolIndex) lookup(symbol string) (entity.SymbolInfo, bool) {
+	j := sort.Search(len(i.symbols), func(j int) bool { return i.symbols[j].key >= symbol })
+	if j == len(i.symbols) || i.symbols[j].key != symbol {
+		return entity.SymbolInfo{}, false
+	}
+	return i.infos[i.symbols[j].position], true
+}
+
+func (i *symbolIndex) scan(keys []indexKey, query string) []int {
+	positions := make([]int, 0)
+	start := sort.Search(len(keys), func(j int) bool { return keys[j].key >= query })
+	for j := start; j < len(keys) && len(positions) < maxIndexScan && strings.HasPrefix(keys[j].key, query); j++ {
+		positions = append(positions, keys[j].position)
+	}
+	return positions
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSymbolImport(t *testing.T) {
+	cntr := gomock.NewController(t)
+	directory := mocks.NewMockSymbolDirectory(cntr)
+	symbolService := NewSymbolService(logging.GetLogger("debug"), directory)
+	apple := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    []entity.SymbolInfo
+		want     int
+		isError  bool
+	}{
+		{
+			title: "entries are normalized, incorrect and duplicate ones are dropped",
+			mockCall: func() {
+				directory.EXPECT().Upsert(gomock.Any(), []entity.SymbolInfo{apple}).Return(nil)
+				directory.EXPECT().Directory(gomock.Any()).Return([]entity.SymbolInfo{apple}, nil)
+			},
+			input: []entity.SymbolInfo{
+				{Symbol: " aapl", Name: "Apple", Exchange: "nasdaq", Type: "Stock", Currency: "usd"},
+				{Symbol: "", Name: "No symbol"},
+				{Symbol: "NONAME"},
+				{Symbol: "CURR", Name: "Bad currency", Currency: "US DOLLARS"},
+				{Symbol: "TYPE", Name: "Bad type", Type: "exchange traded fund"},
+				{Symbol: "AAPL", Name: "Apple Inc.", Exchange: "NASDAQ", Type: "stock", Currency: "USD"},
+			},
+			want: 1,
+		},
+		{
+			title: "long names are truncated to the column",
+			mockCall: func() {
+				long := entity.SymbolInfo{Symbol: "LONG", Name: strings.Repeat("é", maxNameLength)}
+				directory.EXPECT().Upsert(gomock.Any(), []entity.SymbolInfo{long}).Return(nil)
+				directory.EXPECT().Directory(gomock.Any()).Return([]entity.SymbolInfo{long}, nil)
+			},
+			input: []entity.SymbolInfo{{Symbol: "long", Name: strings.Repeat("é", maxNameLength+10) + "\xff"}},
+			want:  1,
+		},
+		{
+			title: "storage error is returned",
+			mockCall: func() {
+				directory.EXPECT().Upsert(gomock.Any(), gomock.Any()).Return(errors.New("db error"))
+			},
+			input:   []entity.SymbolInfo{apple},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := symbolService.Import(context.Background(), test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestSymbolSearch(t *testing.T) {
+	cntr := gomock.NewController(t)
+	directory := mocks.NewMockSymbolDirectory(cntr)
+	symbolService := NewSymbolService(logging.GetLogger("debug"), directory)
+	apple := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc."}
+	applied := entity.SymbolInfo{Symbol: "AMAT", Name: "Applied Materials, Inc."}
+	a := entity.SymbolInfo{Symbol: "A", Name: "Agilent Technologies, Inc."}
+	amazon := entity.SymbolInfo{Symbol: "AMZN", Name: "Amazon.com, Inc."}
+	directory.EXPECT().Directory(gomock.Any()).Return([]entity.SymbolInfo{apple, applied, a, amazon}, nil)
+	assert.NoError(t, symbolService.Load(context.Background()))
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		query    string
+		limit    int
+		want     []entity.SymbolInfo
+		isError  bool
+	}{
+		{
+			title:    "shorter symbols go first and the index fills the limit",
+			mockCall: func() {},
+			query:    "a",
+			limit:    3,
+			want:     []entity.SymbolInfo{a, apple, applied},
+		},
+		{
+			title: "words of the names are matched and fuzzy matches are appended",
+			mockCall: func() {
+				directory.EXPECT().Search(gomock.Any(), "appl", 10).Return([]entity.SymbolInfo{apple, amazon}, nil)
+			},
+			query: " appl",
+			want:  []entity.SymbolInfo{apple, applied, amazon},
+		},
+		{
+			title: "misspelled query is found in the directory",
+			mockCall: func() {
+				directory.EXPECT().Search(gomock.Any(), "aple", 10).Return([]entity.SymbolInfo{apple}, nil)
+			},
+			query: "aple",
+			want:  []entity.SymbolInfo{apple},
+		},
+		{
+			title: "directory failure doesn't hide the prefix matches",
+			mockCall: func() {
+				directory.EXPECT().Search(gomock.Any(), "MATERIALS", 10).Return(nil, errors.New("db error"))
+			},
+			query: "MATERIALS",
+			want:  []entity.SymbolInfo{applied},
+		},
+		{
+			title: "directory failure without matches and return error",
+			mockCall: func() {
+				directory.EXPECT().Search(gomock.Any(), "zzz", 10).Return(nil, errors.New("db error"))
+			},
+			query:   "zzz",
+			isError: true,
+		},
+		{
+			title:    "empty query and return error",
+			mockCall: func() {},
+			query:    "  ",
+			isError:  true,
+		},
+		{
+			title:    "a few prefix matches don't ask the directory",
+			mockCall: func() {},
+			query:    "a",
+			want:     []entity.SymbolInfo{a, apple, applied, amazon},
+		},
+		{
+			title: "limit above the maximum is capped",
+			mockCall: func() {
+				directory.EXPECT().Search(gomock.Any(), "aple", maxSearchLimit).Return([]entity.SymbolInfo{apple}, nil)
+			},
+			query: "aple",
+			limit: 1000,
+			want:  []entity.SymbolInfo{apple},
+		},
+		{
+			title:    "negative limit and return error",
+			mockCall: func() {},
+			query:    "a",
+			limit:    -1,
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := symbolService.Search(context.Background(), test.query, test.limit)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestSymbolInfo(t *testing.T) {
+	cntr := gomock.NewController(t)
+	directory := mocks.NewMockSymbolDirectory(cntr)
+	symbolService := NewSymbolService(logging.GetLogger("debug"), directory)
+	apple := entity.SymbolInfo{Symbol: "AAPL", Name: "Apple Inc."}
+	amazon := entity.SymbolInfo{Symbol: "AMZN", Name: "Amazon.com, Inc."}
+	directory.EXPECT().Directory(gomock.Any()).Return([]entity.SymbolInfo{amazon, apple}, nil)
+	assert.NoError(t, symbolService.Load(context.Background()))
+
+	got, ok := symbolService.Info(" aapl")
+	assert.True(t, ok)
+	assert.Equal(t, apple, got)
+	_, ok = symbolService.Info("AAP")
+	assert.False(t, ok)
+}
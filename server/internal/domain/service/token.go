@@ -1,47 +1,240 @@
 package service
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"sort"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/errs"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/VrMolodyakov/stock-market/pkg/lru"
+)
+
+const (
+	revocationCacheSize = 10000
+	// allowedFor is how long a replica keeps accepting an access token revoked
+	// by another replica.
+	allowedFor = 5 * time.Second
+	// sessionPrefix keeps the ended sessions apart from the denied tokens in
+	// the memory of the revocations.
+	sessionPrefix = "session:"
 )
 
 type TokenStorage interface {
-	Set(refreshToken string, userId int, expireAt time.Duration) error
-	Get(refreshToken string) (int, error)
+	Set(refreshToken string, session entity.Session, expireAt time.Duration) error
+	Get(refreshToken string) (entity.RefreshToken, error)
+	Rotate(oldToken string, newToken string, usedAt time.Time, expireAt time.Duration) (entity.RefreshToken, error)
+	Sessions(userId int) ([]entity.Session, error)
 	Delete(refreshToken string) error
+	DeleteFamily(family string) error
+	DeleteSessions(userId int) error
+	Alive(family string) (bool, error)
+	Deny(id string, expireAt time.Duration) error
+	Denied(id string) (bool, error)
 }
 
 type tokenService struct {
-	logger  *logging.Logger
-	storage TokenStorage
+	logger      *logging.Logger
+	storage     TokenStorage
+	revocations *lru.Cache
+	now         func() time.Time
 }
 
 func NewTokenService(storage TokenStorage, logger *logging.Logger) *tokenService {
-	return &tokenService{storage: storage, logger: logger}
+	return &tokenService{storage: storage, logger: logger, revocations: lru.New(revocationCacheSize), now: time.Now}
 }
 
-func (t *tokenService) Save(refreshToken string, userId int, expireAt time.Duration) error {
+// Save starts a new session with the refresh token of the sign-in as the
+// first of its family and returns the id of the session.
+func (t *tokenService) Save(refreshToken string, session entity.Session, expireAt time.Duration) (string, error) {
 	if len(refreshToken) == 0 {
-		return errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
+		return "", errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
+	}
+	if session.UserId < 0 {
+		return "", errs.New(errs.Validation, errs.Code("user id can't be less than zero"), errs.Parameter("user id"))
 	}
-	if userId < 0 {
-		return errs.New(errs.Validation, errs.Code("user id can't be less than zero"), errs.Parameter("user id"))
+	family, err := newFamily()
+	if err != nil {
+		return "", errs.New(errs.Internal, err)
 	}
-	return t.storage.Set(refreshToken, userId, expireAt)
+	session.Id = family
+	session.CreatedAt = t.now().UTC()
+	session.LastUsedAt = session.CreatedAt
+	if err := t.storage.Set(refreshToken, session, expireAt); err != nil {
+		return "", err
+	}
+	return family, nil
 }
 
-func (t *tokenService) Find(refreshToken string) (int, error) {
+// Find returns a refresh token that can still be exchanged.
+func (t *tokenService) Find(refreshToken string) (entity.RefreshToken, error) {
 	if len(refreshToken) == 0 {
-		return -1, errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
+		return entity.RefreshToken{}, errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
+	}
+	token, err := t.storage.Get(refreshToken)
+	if err != nil {
+		return entity.RefreshToken{}, err
+	}
+	if err := t.check(token); err != nil {
+		return entity.RefreshToken{}, err
 	}
-	return t.storage.Get(refreshToken)
+	return token, nil
 }
 
+// Rotate exchanges the refresh token for the new one of the same family. An
+// already rotated token means that it was stolen, so the whole family is
+// revoked and the user has to sign in again.
+func (t *tokenService) Rotate(oldToken string, newToken string, expireAt time.Duration) error {
+	if len(oldToken) == 0 || len(newToken) == 0 {
+		return errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
+	}
+	token, err := t.storage.Rotate(oldToken, newToken, t.now().UTC(), expireAt)
+	if err != nil {
+		return err
+	}
+	return t.check(token)
+}
+
+// Remove revokes the family of the refresh token.
 func (t *tokenService) Remove(refreshToken string) error {
 	if len(refreshToken) == 0 {
 		return errs.New(errs.Validation, errs.Code("refresh token is empty"), errs.Parameter("refresh token"))
 	}
-	return t.storage.Delete(refreshToken)
+	token, err := t.storage.Get(refreshToken)
+	if err != nil {
+		return err
+	}
+	if err := t.storage.Delete(refreshToken); err != nil {
+		return err
+	}
+	return t.endSession(token.Family)
+}
+
+// Sessions returns the sessions of the user, the last used first.
+func (t *tokenService) Sessions(userId int) ([]entity.Session, error) {
+	sessions, err := t.storage.Sessions(userId)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(sessions, func(i, j int) bool { return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt) })
+	return sessions, nil
+}
+
+// RemoveSession ends one session of the user together with its access tokens.
+func (t *tokenService) RemoveSession(userId int, id string) error {
+	sessions, err := t.storage.Sessions(userId)
+	if err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		if session.Id == id {
+			return t.endSession(id)
+		}
+	}
+	return errs.New(errs.NotExist, errs.Code("session not found"), errs.Parameter("id"))
+}
+
+// RemoveSessions ends every session of the user together with their access
+// tokens.
+func (t *tokenService) RemoveSessions(userId int) error {
+	sessions, err := t.storage.Sessions(userId)
+	if err != nil {
+		return err
+	}
+	if err := t.storage.DeleteSessions(userId); err != nil {
+		return err
+	}
+	for _, session := range sessions {
+		t.revocations.Add(sessionPrefix+session.Id, true, allowedFor)
+	}
+	return nil
+}
+
+// Revoke denies the access token until it expires.
+func (t *tokenService) Revoke(id string, expiresAt time.Time) error {
+	if len(id) == 0 {
+		return errs.New(errs.Validation, errs.Code("token id is empty"), errs.Parameter("jti"))
+	}
+	ttl := expiresAt.Sub(t.now())
+	if ttl <= 0 {
+		return nil
+	}
+	if err := t.storage.Deny(id, ttl); err != nil {
+		return err
+	}
+	t.revocations.Add(id, true, ttl)
+	return nil
+}
+
+// Revoked reports whether the access token is denied or its session has
+// ended. The answers are kept in memory, denied tokens until they expire and
+// allowed ones for a short time, so that most requests don't reach the
+// storage.
+func (t *tokenService) Revoked(id string, session string, expiresAt time.Time) (bool, error) {
+	if len(id) != 0 {
+		denied, err := t.revoked(id, expiresAt, func() (bool, error) {
+			return t.storage.Denied(id)
+		})
+		if err != nil || denied {
+			return denied, err
+		}
+	}
+	if len(session) == 0 {
+		return false, nil
+	}
+	return t.revoked(sessionPrefix+session, expiresAt, func() (bool, error) {
+		alive, err := t.storage.Alive(session)
+		return !alive, err
+	})
+}
+
+func (t *tokenService) revoked(key string, expiresAt time.Time, denied func() (bool, error)) (bool, error) {
+	if cached, ok := t.revocations.Get(key); ok {
+		return cached.(bool), nil
+	}
+	revoked, err := denied()
+	if err != nil {
+		return false, err
+	}
+	ttl := allowedFor
+	if revoked {
+		ttl = expiresAt.Sub(t.now())
+	}
+	t.revocations.Add(key, revoked, ttl)
+	return revoked, nil
+}
+
+// endSession revokes the family. Its access tokens are denied on this replica
+// right away, the other replicas find the session gone once their allowed
+// answer expires.
+func (t *tokenService) endSession(family string) error {
+	if err := t.storage.DeleteFamily(family); err != nil {
+		return err
+	}
+	t.revocations.Add(sessionPrefix+family, true, allowedFor)
+	return nil
+}
+
+func (t *tokenService) check(token entity.RefreshToken) error {
+	if token.Revoked {
+		return errs.New(errs.Unauthorized, errs.Code("refresh token is revoked"), "refresh token is revoked")
+	}
+	if token.Rotated {
+		t.logger.Warnf("reuse of rotated refresh token of user with id = %v, revoke family %v", token.UserId, token.Family)
+		if err := t.endSession(token.Family); err != nil {
+			return err
+		}
+		return errs.New(errs.Unauthorized, errs.Code("refresh token is reused"), "refresh token is reused")
+	}
+	return nil
+}
+
+func newFamily() (string, error) {
+	family := make([]byte, 16)
+	if _, err := rand.Read(family); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(family), nil
 }
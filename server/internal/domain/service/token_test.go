@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
 	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
 	"github.com/VrMolodyakov/stock-market/pkg/logging"
 	"github.com/golang/mock/gomock"
@@ -32,7 +33,13 @@ func TestSave(t *testing.T) {
 			title: "Success save token",
 			mockCall: func() *tokenService {
 				logger := logging.GetLogger("debug")
-				tokenRepo.EXPECT().Set(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				tokenRepo.EXPECT().Set("refresh token", gomock.Any(), 5*time.Second).DoAndReturn(func(_ string, session entity.Session, _ time.Duration) error {
+					assert.Equal(t, 1, session.UserId)
+					assert.Equal(t, "Firefox", session.UserAgent)
+					assert.NotEmpty(t, session.Id)
+					assert.False(t, session.CreatedAt.IsZero())
+					return nil
+				})
 				return NewTokenService(tokenRepo, logger)
 			},
 			input:   args{refreshToken: "refresh token", userId: 1, expireAt: 5 * time.Second},
@@ -70,9 +77,10 @@ func TestSave(t *testing.T) {
 	for _, test := range testCases {
 		t.Run(test.title, func(t *testing.T) {
 			tokenService := test.mockCall()
-			err := tokenService.Save(test.input.refreshToken, test.input.userId, test.input.expireAt)
+			session, err := tokenService.Save(test.input.refreshToken, entity.Session{UserId: test.input.userId, UserAgent: "Firefox"}, test.input.expireAt)
 			if !test.isError {
 				assert.NoError(t, err)
+				assert.NotEmpty(t, session)
 			} else {
 				assert.Error(t, err)
 			}
@@ -95,19 +103,40 @@ func TestFind(t *testing.T) {
 		mockCall mock
 		input    args
 		isError  bool
-		want     int
+		want     entity.RefreshToken
 	}{
 		{
 			title: "Success save token",
 			mockCall: func() *tokenService {
 				logger := logging.GetLogger("debug")
-				tokenRepo.EXPECT().Get(gomock.Any()).Return(1, nil)
+				tokenRepo.EXPECT().Get(gomock.Any()).Return(entity.RefreshToken{UserId: 1, Family: "family"}, nil)
 				return NewTokenService(tokenRepo, logger)
 			},
 			input:   args{refreshToken: "refresh token"},
-			want:    1,
+			want:    entity.RefreshToken{UserId: 1, Family: "family"},
 			isError: false,
 		},
+		{
+			title: "Rotated token revokes family and return error",
+			mockCall: func() *tokenService {
+				logger := logging.GetLogger("debug")
+				tokenRepo.EXPECT().Get(gomock.Any()).Return(entity.RefreshToken{UserId: 1, Family: "family", Rotated: true}, nil)
+				tokenRepo.EXPECT().DeleteFamily("family").Return(nil)
+				return NewTokenService(tokenRepo, logger)
+			},
+			input:   args{refreshToken: "refresh token"},
+			isError: true,
+		},
+		{
+			title: "Token of revoked family and return error",
+			mockCall: func() *tokenService {
+				logger := logging.GetLogger("debug")
+				tokenRepo.EXPECT().Get(gomock.Any()).Return(entity.RefreshToken{UserId: 1, Family: "family", Revoked: true}, nil)
+				return NewTokenService(tokenRepo, logger)
+			},
+			input:   args{refreshToken: "refresh token"},
+			isError: true,
+		},
 		{
 			title: "Refresh token is empty and return error",
 			mockCall: func() *tokenService {
@@ -115,18 +144,16 @@ func TestFind(t *testing.T) {
 				return NewTokenService(tokenRepo, logger)
 			},
 			input:   args{refreshToken: ""},
-			want:    -1,
 			isError: true,
 		},
 		{
 			title: "Internal db error error",
 			mockCall: func() *tokenService {
 				logger := logging.GetLogger("debug")
-				tokenRepo.EXPECT().Get(gomock.Any()).Return(-1, errors.New("internal db error"))
+				tokenRepo.EXPECT().Get(gomock.Any()).Return(entity.RefreshToken{}, errors.New("internal db error"))
 				return NewTokenService(tokenRepo, logger)
 			},
 			input:   args{refreshToken: "refresh token"},
-			want:    -1,
 			isError: true,
 		},
 	}
@@ -164,7 +191,9 @@ func TestRemove(t *testing.T) {
 			title: "Success save token",
 			mockCall: func() *tokenService {
 				logger := logging.GetLogger("debug")
-				tokenRepo.EXPECT().Delete(gomock.Any()).Return(nil)
+				tokenRepo.EXPECT().Get("refresh token").Return(entity.RefreshToken{UserId: 1, Family: "family"}, nil)
+				tokenRepo.EXPECT().Delete("refresh token").Return(nil)
+				tokenRepo.EXPECT().DeleteFamily("family").Return(nil)
 				return NewTokenService(tokenRepo, logger)
 			},
 			input:   args{refreshToken: "refresh token"},
@@ -183,6 +212,7 @@ func TestRemove(t *testing.T) {
 			title: "Internal db error error",
 			mockCall: func() *tokenService {
 				logger := logging.GetLogger("debug")
+				tokenRepo.EXPECT().Get(gomock.Any()).Return(entity.RefreshToken{UserId: 1, Family: "family"}, nil)
 				tokenRepo.EXPECT().Delete(gomock.Any()).Return(errors.New("internal db error"))
 				return NewTokenService(tokenRepo, logger)
 			},
@@ -203,3 +233,226 @@ func TestRemove(t *testing.T) {
 		})
 	}
 }
+
+func TestRotate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	type mock func()
+	testCases := []struct {
+		title    string
+		mockCall mock
+		oldToken string
+		isError  bool
+	}{
+		{
+			title: "Success rotate token",
+			mockCall: func() {
+				tokenRepo.EXPECT().Rotate("old token", "new token", gomock.Any(), time.Minute).Return(entity.RefreshToken{UserId: 1, Family: "family"}, nil)
+			},
+			oldToken: "old token",
+		},
+		{
+			title: "Reused token revokes family and return error",
+			mockCall: func() {
+				tokenRepo.EXPECT().Rotate("old token", "new token", gomock.Any(), time.Minute).Return(entity.RefreshToken{UserId: 1, Family: "family", Rotated: true}, nil)
+				tokenRepo.EXPECT().DeleteFamily("family").Return(nil)
+			},
+			oldToken: "old token",
+			isError:  true,
+		},
+		{
+			title: "Token of revoked family and return error",
+			mockCall: func() {
+				tokenRepo.EXPECT().Rotate("old token", "new token", gomock.Any(), time.Minute).Return(entity.RefreshToken{UserId: 1, Family: "family", Revoked: true}, nil)
+			},
+			oldToken: "old token",
+			isError:  true,
+		},
+		{
+			title: "Internal db error error",
+			mockCall: func() {
+				tokenRepo.EXPECT().Rotate("old token", "new token", gomock.Any(), time.Minute).Return(entity.RefreshToken{}, errors.New("internal db error"))
+			},
+			oldToken: "old token",
+			isError:  true,
+		},
+		{
+			title:    "Refresh token is empty and return error",
+			mockCall: func() {},
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			err := tokenService.Rotate(test.oldToken, "new token", time.Minute)
+			if !test.isError {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSessions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	laptop := entity.Session{Id: "laptop", UserId: 1, LastUsedAt: time.Unix(100, 0)}
+	phone := entity.Session{Id: "phone", UserId: 1, LastUsedAt: time.Unix(200, 0)}
+
+	tokenRepo.EXPECT().Sessions(1).Return([]entity.Session{laptop, phone}, nil)
+	got, err := tokenService.Sessions(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []entity.Session{phone, laptop}, got)
+
+	tokenRepo.EXPECT().Sessions(1).Return(nil, errors.New("internal db error"))
+	_, err = tokenService.Sessions(1)
+	assert.Error(t, err)
+}
+
+func TestRemoveSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	laptop := entity.Session{Id: "laptop", UserId: 1}
+	type mock func()
+	testCases := []struct {
+		title    string
+		mockCall mock
+		id       string
+		isError  bool
+	}{
+		{
+			title: "Success remove session",
+			mockCall: func() {
+				tokenRepo.EXPECT().Sessions(1).Return([]entity.Session{laptop}, nil)
+				tokenRepo.EXPECT().DeleteFamily("laptop").Return(nil)
+			},
+			id: "laptop",
+		},
+		{
+			title: "Session of another user and return error",
+			mockCall: func() {
+				tokenRepo.EXPECT().Sessions(1).Return([]entity.Session{laptop}, nil)
+			},
+			id:      "phone",
+			isError: true,
+		},
+		{
+			title: "Internal db error error",
+			mockCall: func() {
+				tokenRepo.EXPECT().Sessions(1).Return(nil, errors.New("internal db error"))
+			},
+			id:      "laptop",
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			err := tokenService.RemoveSession(1, test.id)
+			if !test.isError {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	now := time.Unix(100, 0)
+	tokenService.now = func() time.Time { return now }
+
+	tokenRepo.EXPECT().Deny("token id", 30*time.Second).Return(nil)
+	assert.NoError(t, tokenService.Revoke("token id", now.Add(30*time.Second)))
+	revoked, err := tokenService.Revoked("token id", "", now.Add(30*time.Second))
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	assert.NoError(t, tokenService.Revoke("expired id", now.Add(-time.Second)))
+
+	tokenRepo.EXPECT().Deny("other id", time.Minute).Return(errors.New("internal db error"))
+	assert.Error(t, tokenService.Revoke("other id", now.Add(time.Minute)))
+	assert.Error(t, tokenService.Revoke("", now.Add(time.Minute)))
+}
+
+func TestRevoked(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	now := time.Now()
+	tokenService.now = func() time.Time { return now }
+	expiresAt := now.Add(time.Minute)
+
+	tokenRepo.EXPECT().Denied("allowed id").Return(false, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		revoked, err := tokenService.Revoked("allowed id", "", expiresAt)
+		assert.NoError(t, err)
+		assert.False(t, revoked)
+	}
+
+	tokenRepo.EXPECT().Denied("denied id").Return(true, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		revoked, err := tokenService.Revoked("denied id", "", expiresAt)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	}
+
+	revoked, err := tokenService.Revoked("", "", expiresAt)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	tokenRepo.EXPECT().Denied("failed id").Return(false, errors.New("internal db error"))
+	_, err = tokenService.Revoked("failed id", "", expiresAt)
+	assert.Error(t, err)
+}
+
+func TestRevokedSession(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	tokenRepo := mocks.NewMockTokenStorage(ctrl)
+	tokenService := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	expiresAt := time.Now().Add(time.Minute)
+	laptop := entity.Session{Id: "laptop", UserId: 1}
+	phone := entity.Session{Id: "phone", UserId: 1}
+
+	tokenRepo.EXPECT().Denied(gomock.Any()).Return(false, nil).AnyTimes()
+	tokenRepo.EXPECT().Alive("laptop").Return(true, nil)
+	revoked, err := tokenService.Revoked("laptop token", "laptop", expiresAt)
+	assert.NoError(t, err)
+	assert.False(t, revoked)
+
+	// the access tokens of an ended session are denied right away
+	tokenRepo.EXPECT().Sessions(1).Return([]entity.Session{laptop, phone}, nil)
+	tokenRepo.EXPECT().DeleteFamily("laptop").Return(nil)
+	assert.NoError(t, tokenService.RemoveSession(1, "laptop"))
+	revoked, err = tokenService.Revoked("laptop token", "laptop", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	tokenRepo.EXPECT().Sessions(1).Return([]entity.Session{phone}, nil)
+	tokenRepo.EXPECT().DeleteSessions(1).Return(nil)
+	assert.NoError(t, tokenService.RemoveSessions(1))
+	revoked, err = tokenService.Revoked("phone token", "phone", expiresAt)
+	assert.NoError(t, err)
+	assert.True(t, revoked)
+
+	// an ended session is found by the replicas that didn't end it
+	other := NewTokenService(tokenRepo, logging.GetLogger("debug"))
+	tokenRepo.EXPECT().Alive("laptop").Return(false, nil).Times(1)
+	for i := 0; i < 2; i++ {
+		revoked, err = other.Revoked("laptop token", "laptop", expiresAt)
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+	}
+
+	tokenRepo.EXPECT().Alive("tablet").Return(false, errors.New("internal db error"))
+	_, err = other.Revoked("tablet token", "tablet", expiresAt)
+	assert.Error(t, err)
+}
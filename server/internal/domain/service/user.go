@@ -30,7 +30,7 @@ func (u *userService) Create(ctx context.Context, username string, password stri
 	if password == "" {
 		return entity.User{}, errs.New(errs.Validation, errs.Parameter("password"), errs.Code("empty password"))
 	}
-	u.logger.Debug("create user with login = %v , password = %v", username, password)
+	u.logger.Debugf("create user with login = %v , password = %v", username, password)
 	return u.storage.Insert(ctx, username, password)
 }
 
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+const (
+	maxWatchlistName    = 100
+	maxWatchlistSymbols = 100
+	maxSymbolLength     = 32
+)
+
+// symbolPattern holds the characters of the symbols of the providers, so a
+// symbol can't carry line breaks into the mails and the requests it ends up in.
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9.^=\-]+$`)
+
+type WatchlistStorage interface {
+	Insert(ctx context.Context, userId int, name string) (entity.Watchlist, error)
+	FindAll(ctx context.Context, userId int) ([]entity.Watchlist, error)
+	Find(ctx context.Context, userId int, id int) (entity.Watchlist, error)
+	Rename(ctx context.Context, userId int, id int, name string) error
+	Delete(ctx context.Context, userId int, id int) error
+	AddSymbol(ctx context.Context, id int, symbol string) error
+	RemoveSymbol(ctx context.Context, id int, symbol string) error
+	Reorder(ctx context.Context, id int, symbols []string) error
+}
+
+type watchlistService struct {
+	logger  *logging.Logger
+	storage WatchlistStorage
+}
+
+func NewWatchlistService(logger *logging.Logger, storage WatchlistStorage) *watchlistService {
+	return &watchlistService{logger: logger, storage: storage}
+}
+
+func (w *watchlistService) Create(ctx context.Context, userId int, name string) (entity.Watchlist, error) {
+	name, err := watchlistName(name)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	return w.storage.Insert(ctx, userId, name)
+}
+
+func (w *watchlistService) GetAll(ctx context.Context, userId int) ([]entity.Watchlist, error) {
+	return w.storage.FindAll(ctx, userId)
+}
+
+func (w *watchlistService) Get(ctx context.Context, userId int, id int) (entity.Watchlist, error) {
+	return w.storage.Find(ctx, userId, id)
+}
+
+func (w *watchlistService) Rename(ctx context.Context, userId int, id int, name string) (entity.Watchlist, error) {
+	name, err := watchlistName(name)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	err = w.storage.Rename(ctx, userId, id, name)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	return w.storage.Find(ctx, userId, id)
+}
+
+func (w *watchlistService) Delete(ctx context.Context, userId int, id int) error {
+	return w.storage.Delete(ctx, userId, id)
+}
+
+func (w *watchlistService) AddSymbol(ctx context.Context, userId int, id int, symbol string) (entity.Watchlist, error) {
+	symbol, err := watchlistSymbol(symbol)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	watchlist, err := w.storage.Find(ctx, userId, id)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	if contains(watchlist.Symbols, symbol) {
+		return watchlist, nil
+	}
+	if len(watchlist.Symbols) >= maxWatchlistSymbols {
+		return entity.Watchlist{}, errs.New(errs.Validation, errs.Code("watchlist is full"), errs.Parameter("symbol"))
+	}
+	err = w.storage.AddSymbol(ctx, id, symbol)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	watchlist.Symbols = append(watchlist.Symbols, symbol)
+	return watchlist, nil
+}
+
+func (w *watchlistService) RemoveSymbol(ctx context.Context, userId int, id int, symbol string) (entity.Watchlist, error) {
+	symbol, err := watchlistSymbol(symbol)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	_, err = w.storage.Find(ctx, userId, id)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	err = w.storage.RemoveSymbol(ctx, id, symbol)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	return w.storage.Find(ctx, userId, id)
+}
+
+// Reorder accepts the full list of the watchlist symbols in the new order.
+func (w *watchlistService) Reorder(ctx context.Context, userId int, id int, symbols []string) (entity.Watchlist, error) {
+	watchlist, err := w.storage.Find(ctx, userId, id)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	ordered := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol, err := watchlistSymbol(symbol)
+		if err != nil {
+			return entity.Watchlist{}, err
+		}
+		if !contains(watchlist.Symbols, symbol) || contains(ordered, symbol) {
+			return entity.Watchlist{}, errs.New(errs.Validation, errs.Code("symbols don't match the watchlist"), errs.Parameter("symbols"))
+		}
+		ordered = append(ordered, symbol)
+	}
+	if len(ordered) != len(watchlist.Symbols) {
+		return entity.Watchlist{}, errs.New(errs.Validation, errs.Code("symbols don't match the watchlist"), errs.Parameter("symbols"))
+	}
+	err = w.storage.Reorder(ctx, id, ordered)
+	if err != nil {
+		return entity.Watchlist{}, err
+	}
+	watchlist.Symbols = ordered
+	return watchlist, nil
+}
+
+func watchlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errs.New(errs.Validation, errs.Code("empty watchlist name"), errs.Parameter("name"))
+	}
+	if len(name) > maxWatchlistName {
+		return "", errs.New(errs.Validation, errs.Code("watchlist name is too long"), errs.Parameter("name"))
+	}
+	return name, nil
+}
+
+func watchlistSymbol(symbol string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return "", errs.New(errs.Validation, errs.Code("symbol is empty"), errs.Parameter("symbol"))
+	}
+	if len(symbol) > maxSymbolLength {
+		return "", errs.New(errs.Validation, errs.Code("symbol is too long"), errs.Parameter("symbol"))
+	}
+	if !symbolPattern.MatchString(symbol) {
+		return "", errs.New(errs.Validation, errs.Code("incorrect symbol"), errs.Parameter("symbol"))
+	}
+	return symbol, nil
+}
+
+func contains(symbols []string, symbol string) bool {
+	for _, s := range symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/domain/service/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWatchlistCreate(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockWatchlistStorage(cntr)
+	watchlistService := NewWatchlistService(logging.GetLogger("debug"), storage)
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    string
+		isError  bool
+	}{
+		{
+			title: "name is trimmed and watchlist is created",
+			mockCall: func() {
+				storage.EXPECT().Insert(gomock.Any(), 1, "Tech").Return(entity.Watchlist{Id: 1, UserId: 1, Name: "Tech"}, nil)
+			},
+			input: "  Tech ",
+		},
+		{
+			title:    "empty name and return error",
+			mockCall: func() {},
+			input:    "  ",
+			isError:  true,
+		},
+		{
+			title: "storage error is returned",
+			mockCall: func() {
+				storage.EXPECT().Insert(gomock.Any(), 1, "Tech").Return(entity.Watchlist{}, errors.New("db error"))
+			},
+			input:   "Tech",
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := watchlistService.Create(context.Background(), 1, test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, "Tech", got.Name)
+			}
+		})
+	}
+}
+
+func TestWatchlistAddSymbol(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockWatchlistStorage(cntr)
+	watchlistService := NewWatchlistService(logging.GetLogger("debug"), storage)
+	full := make([]string, maxWatchlistSymbols)
+	for i := range full {
+		full[i] = string(rune('A'+i%26)) + string(rune('A'+i/26))
+	}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    string
+		want     []string
+		isError  bool
+	}{
+		{
+			title: "symbol is normalized and appended",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(entity.Watchlist{Id: 2, Symbols: []string{"AAPL"}}, nil)
+				storage.EXPECT().AddSymbol(gomock.Any(), 2, "MSFT").Return(nil)
+			},
+			input: " msft",
+			want:  []string{"AAPL", "MSFT"},
+		},
+		{
+			title: "present symbol is not added twice",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(entity.Watchlist{Id: 2, Symbols: []string{"AAPL"}}, nil)
+			},
+			input: "AAPL",
+			want:  []string{"AAPL"},
+		},
+		{
+			title: "full watchlist and return error",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(entity.Watchlist{Id: 2, Symbols: full}, nil)
+			},
+			input:   "MSFT",
+			isError: true,
+		},
+		{
+			title: "watchlist of another user and return error",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(entity.Watchlist{}, errors.New("watchlist not found"))
+			},
+			input:   "MSFT",
+			isError: true,
+		},
+		{
+			title:    "empty symbol and return error",
+			mockCall: func() {},
+			input:    "",
+			isError:  true,
+		},
+		{
+			title:    "symbol with a line break and return error",
+			mockCall: func() {},
+			input:    "TSLA\r\nBcc: victim@example.com",
+			isError:  true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := watchlistService.AddSymbol(context.Background(), 1, 2, test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got.Symbols)
+			}
+		})
+	}
+}
+
+func TestWatchlistReorder(t *testing.T) {
+	cntr := gomock.NewController(t)
+	storage := mocks.NewMockWatchlistStorage(cntr)
+	watchlistService := NewWatchlistService(logging.GetLogger("debug"), storage)
+	current := entity.Watchlist{Id: 2, Symbols: []string{"AAPL", "MSFT", "TSLA"}}
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		input    []string
+		isError  bool
+	}{
+		{
+			title: "symbols are reordered",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(current, nil)
+				storage.EXPECT().Reorder(gomock.Any(), 2, []string{"TSLA", "AAPL", "MSFT"}).Return(nil)
+			},
+			input: []string{"tsla", "AAPL", "MSFT"},
+		},
+		{
+			title: "missing symbol and return error",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(current, nil)
+			},
+			input:   []string{"TSLA", "AAPL"},
+			isError: true,
+		},
+		{
+			title: "duplicated symbol and return error",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(current, nil)
+			},
+			input:   []string{"TSLA", "AAPL", "AAPL"},
+			isError: true,
+		},
+		{
+			title: "unknown symbol and return error",
+			mockCall: func() {
+				storage.EXPECT().Find(gomock.Any(), 1, 2).Return(current, nil)
+			},
+			input:   []string{"TSLA", "AAPL", "GOOG"},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			got, err := watchlistService.Reorder(context.Background(), 1, 2, test.input)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"TSLA", "AAPL", "MSFT"}, got.Symbols)
+			}
+		})
+	}
+}
@@ -33,6 +33,9 @@ func HTTPErrorResponse(ctx *gin.Context, logger *logging.Logger, err error) {
 		case Unauthorized:
 			unauthorizedErrorResponse(ctx, logger, e)
 			return
+		case NotExist:
+			notFoundResponse(ctx, logger, e)
+			return
 		default:
 			commonErrorResponse(ctx, logger, e)
 			return
@@ -41,6 +44,23 @@ func HTTPErrorResponse(ctx *gin.Context, logger *logging.Logger, err error) {
 	unknownErrorResponse(ctx, logger, err)
 }
 
+// StatusOf returns the status code HTTPErrorResponse responds with to the
+// error, as a label of the response metrics.
+func StatusOf(err error) string {
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.Kind {
+		case Unauthenticated, Validation:
+			return "400"
+		case Unauthorized:
+			return "403"
+		case NotExist:
+			return "404"
+		}
+	}
+	return "500"
+}
+
 func badRequesteResponse(c *gin.Context, logger *logging.Logger, err *Error) {
 	logger.Errorf("http status code %v\n error = %v", http.StatusUnauthorized, err)
 	c.JSON(http.StatusBadRequest, err.Error())
@@ -56,6 +76,11 @@ func unauthorizedErrorResponse(c *gin.Context, logger *logging.Logger, err *Erro
 	c.JSON(http.StatusForbidden, err.Error())
 }
 
+func notFoundResponse(c *gin.Context, logger *logging.Logger, err *Error) {
+	logger.Errorf("http status code %v\n error = %v", http.StatusNotFound, err)
+	c.JSON(http.StatusNotFound, err.Code)
+}
+
 func unknownErrorResponse(c *gin.Context, logger *logging.Logger, err error) {
 	logger.Errorf("http status code %v\n error = %v", http.StatusInternalServerError, err)
 	c.Header("Content-Type", "application/json")
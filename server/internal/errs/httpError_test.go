@@ -31,6 +31,25 @@ func Test_httpErrorStatusCode(t *testing.T) {
 	}
 }
 
+func TestStatusOf(t *testing.T) {
+	tests := []struct {
+		title string
+		err   error
+		want  string
+	}{
+		{"Validation", New(Validation, "incorrect"), "400"},
+		{"Unauthorized", New(Unauthorized, "forbidden"), "403"},
+		{"NotExist", New(NotExist, "missing"), "404"},
+		{"Database", New(Database, "db error"), "500"},
+		{"unknown", errors.New("some error"), "500"},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			assert.Equal(t, test.want, StatusOf(test.err))
+		})
+	}
+}
+
 func TestHTTPErrorResponse_StatusCode(t *testing.T) {
 
 	type args struct {
@@ -43,6 +62,7 @@ func TestHTTPErrorResponse_StatusCode(t *testing.T) {
 
 	unauthenticatedErr := New(Unauthenticated, "some error from Google")
 	unauthorizedErr := New(Unauthorized, "some authorization error")
+	notExistErr := New(NotExist, Code("symbol not found"), "some provider error")
 
 	tests := []struct {
 		name string
@@ -52,6 +72,7 @@ func TestHTTPErrorResponse_StatusCode(t *testing.T) {
 		{"empty *Error", args{httptest.NewRecorder(), l, &Error{Err: errors.New("")}}, http.StatusInternalServerError},
 		{"unauthenticated", args{httptest.NewRecorder(), l, unauthenticatedErr}, http.StatusBadRequest},
 		{"unauthorized", args{httptest.NewRecorder(), l, unauthorizedErr}, http.StatusForbidden},
+		{"not exist", args{httptest.NewRecorder(), l, notExistErr}, http.StatusNotFound},
 	}
 
 	for _, test := range tests {
@@ -82,6 +103,7 @@ func TestHTTPErrorResponse_Body(t *testing.T) {
 		{"empty Error", args{httptest.NewRecorder(), lgr, &Error{}}, "\"internal server error - please contact support\""},
 		{"unauthenticated", args{httptest.NewRecorder(), lgr, New(Unauthenticated, "some unauthenticated error")}, "\"some unauthenticated error\""},
 		{"unauthorized", args{httptest.NewRecorder(), lgr, New(Unauthorized, "some authorization error")}, "\"some authorization error\""},
+		{"not exist", args{httptest.NewRecorder(), lgr, New(NotExist, Code("symbol not found"), "some provider error")}, "\"symbol not found\""},
 		{"normal", args{httptest.NewRecorder(), lgr, New(Exist, Parameter("some_param"), Code("some_code"), errors.New("some error"))}, "\"{\\\"error\\\":{\\\"kind\\\":\\\"item_already_exists\\\",\\\"code\\\":\\\"some_code\\\",\\\"param\\\":\\\"some_param\\\",\\\"message\\\":\\\"some error\\\"}}\""},
 		{"not via New", args{httptest.NewRecorder(), lgr, errors.New("some error")}, "\"some error\""},
 	}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/scheduler/scheduler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockChartWarmer is a mock of ChartWarmer interface.
+type MockChartWarmer struct {
+	ctrl     *gomock.Controller
+	recorder *MockChartWarmerMockRecorder
+}
+
+// MockChartWarmerMockRecorder is the mock recorder for MockChartWarmer.
+type MockChartWarmerMockRecorder struct {
+	mock *MockChartWarmer
+}
+
+// NewMockChartWarmer creates a new mock instance.
+func NewMockChartWarmer(ctrl *gomock.Controller) *MockChartWarmer {
+	mock := &MockChartWarmer{ctrl: ctrl}
+	mock.recorder = &MockChartWarmerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockChartWarmer) EXPECT() *MockChartWarmerMockRecorder {
+	return m.recorder
+}
+
+// Warm mocks base method.
+func (m *MockChartWarmer) Warm(ctx context.Context, query entity.ChartQuery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Warm", ctx, query)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Warm indicates an expected call of Warm.
+func (mr *MockChartWarmerMockRecorder) Warm(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Warm", reflect.TypeOf((*MockChartWarmer)(nil).Warm), ctx, query)
+}
+
+// MockSymbolSource is a mock of SymbolSource interface.
+type MockSymbolSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockSymbolSourceMockRecorder
+}
+
+// MockSymbolSourceMockRecorder is the mock recorder for MockSymbolSource.
+type MockSymbolSourceMockRecorder struct {
+	mock *MockSymbolSource
+}
+
+// NewMockSymbolSource creates a new mock instance.
+func NewMockSymbolSource(ctrl *gomock.Controller) *MockSymbolSource {
+	mock := &MockSymbolSource{ctrl: ctrl}
+	mock.recorder = &MockSymbolSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSymbolSource) EXPECT() *MockSymbolSourceMockRecorder {
+	return m.recorder
+}
+
+// Tracked mocks base method.
+func (m *MockSymbolSource) Tracked(ctx context.Context) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Tracked", ctx)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Tracked indicates an expected call of Tracked.
+func (mr *MockSymbolSourceMockRecorder) Tracked(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Tracked", reflect.TypeOf((*MockSymbolSource)(nil).Tracked), ctx)
+}
@@ -0,0 +1,192 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+type ChartWarmer interface {
+	Warm(ctx context.Context, query entity.ChartQuery) error
+}
+
+type SymbolSource interface {
+	Tracked(ctx context.Context) ([]string, error)
+}
+
+type Options struct {
+	Symbols    []string
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxBackoff time.Duration
+	Reload     time.Duration
+}
+
+type scheduler struct {
+	logger  *logging.Logger
+	warmer  ChartWarmer
+	source  SymbolSource
+	options Options
+	random  func(n int64) int64
+
+	mu     sync.Mutex
+	jobs   map[string]context.CancelFunc
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New returns a scheduler that keeps the charts of the tracked symbols warm.
+// The symbols from the options are always tracked, the source adds symbols
+// that are reloaded every Reload period.
+func New(logger *logging.Logger, warmer ChartWarmer, source SymbolSource, options Options) *scheduler {
+	if options.MaxBackoff < options.Interval {
+		options.MaxBackoff = options.Interval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	// the jobs draw the jitter concurrently and *rand.Rand isn't safe for it
+	var randomMu sync.Mutex
+	seeded := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return &scheduler{
+		logger:  logger,
+		warmer:  warmer,
+		source:  source,
+		options: options,
+		random: func(n int64) int64 {
+			randomMu.Lock()
+			defer randomMu.Unlock()
+			return seeded.Int63n(n)
+		},
+		jobs:   make(map[string]context.CancelFunc),
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+func (s *scheduler) Start() {
+	s.reload()
+	if s.options.Reload <= 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.options.Reload)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-ticker.C:
+				s.reload()
+			}
+		}
+	}()
+}
+
+// Close stops all jobs and waits for the running fetches to finish.
+func (s *scheduler) Close() error {
+	s.cancel()
+	s.wg.Wait()
+	return nil
+}
+
+// reload starts jobs for new symbols and stops jobs of symbols that are no
+// longer tracked. If the source fails the current jobs are kept and the
+// symbols of the options are started anyway.
+func (s *scheduler) reload() {
+	symbols := make(map[string]bool)
+	for _, symbol := range s.options.Symbols {
+		symbols[normalize(symbol)] = true
+	}
+	keep := false
+	if s.source != nil {
+		tracked, err := s.source.Tracked(s.ctx)
+		if err != nil {
+			s.logger.Errorf("cannot reload tracked symbols due to : %v", err)
+			keep = true
+		}
+		for _, symbol := range tracked {
+			symbols[normalize(symbol)] = true
+		}
+	}
+	delete(symbols, "")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx.Err() != nil {
+		return
+	}
+	if keep {
+		for symbol := range s.jobs {
+			symbols[symbol] = true
+		}
+	}
+	for symbol, cancel := range s.jobs {
+		if !symbols[symbol] {
+			s.logger.Infof("stop ingestion of %v", symbol)
+			cancel()
+			delete(s.jobs, symbol)
+		}
+	}
+	for symbol := range symbols {
+		if _, ok := s.jobs[symbol]; ok {
+			continue
+		}
+		s.logger.Infof("start ingestion of %v", symbol)
+		ctx, cancel := context.WithCancel(s.ctx)
+		s.jobs[symbol] = cancel
+		s.wg.Add(1)
+		go s.run(ctx, symbol)
+	}
+}
+
+// run warms the chart of one symbol every Interval plus a random jitter, so
+// that the jobs don't hit the providers at the same moment. Failed fetches
+// are retried with an exponential backoff up to MaxBackoff.
+func (s *scheduler) run(ctx context.Context, symbol string) {
+	defer s.wg.Done()
+	query := entity.ChartQuery{Symbol: symbol}
+	delay := s.jitter()
+	backoff := s.options.Interval
+	for {
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		err := s.warmer.Warm(ctx, query)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			backoff *= 2
+			if backoff > s.options.MaxBackoff {
+				backoff = s.options.MaxBackoff
+			}
+			s.logger.Errorf("cannot ingest %v, retry in %v due to : %v", symbol, backoff, err)
+			delay = backoff + s.jitter()
+			continue
+		}
+		backoff = s.options.Interval
+		delay = s.options.Interval + s.jitter()
+	}
+}
+
+func (s *scheduler) jitter() time.Duration {
+	if s.options.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(s.random(int64(s.options.Jitter)))
+}
+
+func normalize(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
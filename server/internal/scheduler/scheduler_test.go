@@ -0,0 +1,167 @@
+package scheduler
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/scheduler/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func (s *scheduler) symbols() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	symbols := make([]string, 0, len(s.jobs))
+	for symbol := range s.jobs {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+func TestReload(t *testing.T) {
+	cntr := gomock.NewController(t)
+	warmer := mocks.NewMockChartWarmer(cntr)
+	source := mocks.NewMockSymbolSource(cntr)
+	warmer.EXPECT().Warm(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	ingestion := New(logging.GetLogger("debug"), warmer, source, Options{Symbols: []string{"aapl", " "}, Interval: time.Hour})
+	defer ingestion.Close()
+	type mockCall func()
+	testCases := []struct {
+		title    string
+		mockCall mockCall
+		want     []string
+	}{
+		{
+			title: "config and tracked symbols are started",
+			mockCall: func() {
+				source.EXPECT().Tracked(gomock.Any()).Return([]string{"MSFT", "AAPL", "TSLA"}, nil)
+			},
+			want: []string{"AAPL", "MSFT", "TSLA"},
+		},
+		{
+			title: "untracked symbols are stopped",
+			mockCall: func() {
+				source.EXPECT().Tracked(gomock.Any()).Return([]string{"TSLA"}, nil)
+			},
+			want: []string{"AAPL", "TSLA"},
+		},
+		{
+			title: "source failure keeps current jobs",
+			mockCall: func() {
+				source.EXPECT().Tracked(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+			want: []string{"AAPL", "TSLA"},
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			test.mockCall()
+			ingestion.reload()
+			assert.Equal(t, test.want, ingestion.symbols())
+		})
+	}
+}
+
+func TestReloadSourceUnavailable(t *testing.T) {
+	cntr := gomock.NewController(t)
+	warmer := mocks.NewMockChartWarmer(cntr)
+	source := mocks.NewMockSymbolSource(cntr)
+	warmer.EXPECT().Warm(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	source.EXPECT().Tracked(gomock.Any()).Return(nil, errors.New("db error"))
+	ingestion := New(logging.GetLogger("debug"), warmer, source, Options{Symbols: []string{"AAPL", "MSFT"}, Interval: time.Hour})
+	defer ingestion.Close()
+	ingestion.reload()
+	assert.Equal(t, []string{"AAPL", "MSFT"}, ingestion.symbols())
+}
+
+func TestRunBackoff(t *testing.T) {
+	cntr := gomock.NewController(t)
+	warmer := mocks.NewMockChartWarmer(cntr)
+	interval := 20 * time.Millisecond
+	var mu sync.Mutex
+	calls := make([]time.Time, 0)
+	done := make(chan struct{})
+	warmer.EXPECT().Warm(gomock.Any(), entity.ChartQuery{Symbol: "AAPL"}).DoAndReturn(
+		func(_ interface{}, _ entity.ChartQuery) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls = append(calls, time.Now())
+			switch len(calls) {
+			case 1, 2:
+				return errors.New("upstream error")
+			case 4:
+				close(done)
+			}
+			return nil
+		}).MinTimes(4)
+	ingestion := New(logging.GetLogger("debug"), warmer, nil, Options{Symbols: []string{"AAPL"}, Interval: interval, MaxBackoff: 3 * interval})
+	ingestion.Start()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler didn't retry")
+	}
+	assert.NoError(t, ingestion.Close())
+
+	mu.Lock()
+	got := append([]time.Time(nil), calls...)
+	mu.Unlock()
+	assert.GreaterOrEqual(t, got[1].Sub(got[0]), 2*interval)
+	assert.GreaterOrEqual(t, got[2].Sub(got[1]), 3*interval)
+	assert.GreaterOrEqual(t, got[3].Sub(got[2]), interval)
+
+	time.Sleep(3 * interval)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, len(got), len(calls))
+}
+
+func TestRunJitterConcurrently(t *testing.T) {
+	cntr := gomock.NewController(t)
+	warmer := mocks.NewMockChartWarmer(cntr)
+	symbols := []string{"AAPL", "MSFT", "TSLA", "AMZN", "GOOG"}
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	done := make(chan struct{})
+	warmer.EXPECT().Warm(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, query entity.ChartQuery) error {
+			mu.Lock()
+			defer mu.Unlock()
+			calls[query.Symbol]++
+			if len(calls) == len(symbols) && calls[query.Symbol] == 3 {
+				select {
+				case <-done:
+				default:
+					close(done)
+				}
+			}
+			return nil
+		}).AnyTimes()
+	interval := time.Millisecond
+	ingestion := New(logging.GetLogger("debug"), warmer, nil, Options{Symbols: symbols, Interval: interval, Jitter: interval})
+	ingestion.Start()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("scheduler didn't warm the symbols")
+	}
+	assert.NoError(t, ingestion.Close())
+}
+
+func TestJitter(t *testing.T) {
+	ingestion := New(logging.GetLogger("debug"), nil, nil, Options{Interval: time.Second, Jitter: 10 * time.Second})
+	for i := 0; i < 100; i++ {
+		jitter := ingestion.jitter()
+		assert.GreaterOrEqual(t, jitter, time.Duration(0))
+		assert.Less(t, jitter, 10*time.Second)
+	}
+	ingestion = New(logging.GetLogger("debug"), nil, nil, Options{Interval: time.Second})
+	assert.Equal(t, time.Duration(0), ingestion.jitter())
+}
@@ -0,0 +1,35 @@
+package stream
+
+import (
+	"sync"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+)
+
+type book struct {
+	mu     sync.RWMutex
+	quotes map[string]entity.Tick
+}
+
+// NewBook returns the latest quotes received from the broker. Every replica
+// keeps its own book, so the latest price of a streamed symbol can be served
+// without asking the providers or the cache.
+func NewBook() *book {
+	return &book{quotes: make(map[string]entity.Tick)}
+}
+
+func (b *book) OnQuote(tick entity.Tick) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if last, ok := b.quotes[tick.Symbol]; ok && last.Time.After(tick.Time) {
+		return
+	}
+	b.quotes[tick.Symbol] = tick
+}
+
+func (b *book) Latest(symbol string) (entity.Tick, bool) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	tick, ok := b.quotes[symbol]
+	return tick, ok
+}
@@ -0,0 +1,240 @@
+package stream
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+// PriceSource returns the latest chart from the providers, the cache keeps a
+// chart fresh for longer than the hub polls.
+type PriceSource interface {
+	Latest(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error)
+}
+
+// Broker shares the ticks between the replicas of the server. Only the
+// replica that wins the campaign for a symbol polls its price, the others
+// receive the ticks through OnQuote.
+type Broker interface {
+	Campaign(ctx context.Context, symbol string) bool
+	Resign(symbol string)
+	Publish(tick entity.Tick) error
+}
+
+const defaultInterval = 5 * time.Second
+
+type Options struct {
+	Interval   time.Duration
+	MaxSymbols int
+}
+
+type hub struct {
+	logger  *logging.Logger
+	source  PriceSource
+	broker  Broker
+	options Options
+
+	mu          sync.Mutex
+	subscribers map[string]map[*Subscriber]struct{}
+	pollers     map[string]context.CancelFunc
+	last        map[string]entity.Tick
+	sequence    uint64
+	closed      bool
+	ctx         context.Context
+	cancel      context.CancelFunc
+	wg          sync.WaitGroup
+}
+
+// New returns a hub that polls the price of every symbol with at least one
+// subscriber once per Interval, no matter how many clients subscribed to it,
+// and pushes the changed prices to the subscribers. Tick ids start from the
+// current time, so they keep growing across restarts. The broker is optional,
+// without it the hub polls every subscribed symbol itself.
+func New(logger *logging.Logger, source PriceSource, broker Broker, options Options) *hub {
+	if options.Interval <= 0 {
+		options.Interval = defaultInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &hub{
+		logger:      logger,
+		source:      source,
+		broker:      broker,
+		options:     options,
+		subscribers: make(map[string]map[*Subscriber]struct{}),
+		pollers:     make(map[string]context.CancelFunc),
+		last:        make(map[string]entity.Tick),
+		sequence:    uint64(time.Now().UnixNano()),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+}
+
+// Connect returns a subscriber without subscriptions. The subscriber must be
+// closed when the client goes away.
+func (h *hub) Connect() *Subscriber {
+	subscriber := &Subscriber{
+		hub:     h,
+		symbols: make(map[string]bool),
+		pending: make(map[string]entity.Tick),
+		ready:   make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		subscriber.closeDone()
+	}
+	return subscriber
+}
+
+// Close stops the pollers and disconnects every subscriber.
+func (h *hub) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	h.cancel()
+	for _, subscribers := range h.subscribers {
+		for subscriber := range subscribers {
+			subscriber.closeDone()
+		}
+	}
+	h.mu.Unlock()
+	h.wg.Wait()
+	return nil
+}
+
+func (h *hub) subscribe(subscriber *Subscriber, symbol string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+	subscribers, ok := h.subscribers[symbol]
+	if !ok {
+		subscribers = make(map[*Subscriber]struct{})
+		h.subscribers[symbol] = subscribers
+		ctx, cancel := context.WithCancel(h.ctx)
+		h.pollers[symbol] = cancel
+		h.wg.Add(1)
+		go h.poll(ctx, symbol)
+	}
+	subscribers[subscriber] = struct{}{}
+	if tick, ok := h.last[symbol]; ok {
+		subscriber.push(tick)
+	}
+}
+
+func (h *hub) unsubscribe(subscriber *Subscriber, symbol string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subscribers, ok := h.subscribers[symbol]
+	if !ok {
+		return
+	}
+	delete(subscribers, subscriber)
+	if len(subscribers) > 0 {
+		return
+	}
+	h.pollers[symbol]()
+	delete(h.pollers, symbol)
+	delete(h.subscribers, symbol)
+	delete(h.last, symbol)
+}
+
+func (h *hub) poll(ctx context.Context, symbol string) {
+	defer h.wg.Done()
+	if h.broker != nil {
+		defer h.broker.Resign(symbol)
+	}
+	ticker := time.NewTicker(h.options.Interval)
+	defer ticker.Stop()
+	for {
+		if h.broker == nil || h.broker.Campaign(ctx, symbol) {
+			h.fetch(ctx, symbol)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *hub) fetch(ctx context.Context, symbol string) {
+	result, err := h.source.Latest(ctx, entity.ChartQuery{Symbol: symbol})
+	if err == nil {
+		err = result.Err
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			h.logger.Errorf("cannot stream price of %v due to : %v", symbol, err)
+		}
+		return
+	}
+	tick, ok := tickOf(symbol, result.Series)
+	if !ok {
+		return
+	}
+	h.mu.Lock()
+	if ctx.Err() != nil {
+		h.mu.Unlock()
+		return
+	}
+	if last, ok := h.last[symbol]; ok && last.Price == tick.Price && last.Time.Equal(tick.Time) && last.Bar == tick.Bar {
+		h.mu.Unlock()
+		return
+	}
+	h.sequence++
+	tick.Id = h.sequence
+	h.dispatch(tick)
+	h.mu.Unlock()
+	if h.broker == nil {
+		return
+	}
+	if err := h.broker.Publish(tick); err != nil {
+		h.logger.Errorf("cannot publish price of %v due to : %v", symbol, err)
+	}
+}
+
+// OnQuote receives the ticks published by the replicas. Ticks of symbols
+// without subscribers and ticks older than the last one are dropped. The
+// sequence follows the received ids, so the ids keep growing when this
+// replica takes over the polling.
+func (h *hub) OnQuote(tick entity.Tick) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if tick.Id > h.sequence {
+		h.sequence = tick.Id
+	}
+	if _, ok := h.subscribers[tick.Symbol]; !ok {
+		return
+	}
+	if last, ok := h.last[tick.Symbol]; ok && tick.Id <= last.Id {
+		return
+	}
+	h.dispatch(tick)
+}
+
+func (h *hub) dispatch(tick entity.Tick) {
+	h.last[tick.Symbol] = tick
+	for subscriber := range h.subscribers[tick.Symbol] {
+		subscriber.push(tick)
+	}
+}
+
+func tickOf(symbol string, series entity.Series) (entity.Tick, bool) {
+	if series.Price <= 0 {
+		return entity.Tick{}, false
+	}
+	tick := entity.Tick{Symbol: symbol, Price: series.Price, Time: series.PriceTime.UTC()}
+	if series.PreviousClose > 0 {
+		tick.Change = (series.Price - series.PreviousClose) / series.PreviousClose * 100
+	}
+	if bar, ok := series.LastBar(); ok {
+		bar.Symbol = symbol
+		tick.Bar = bar
+	}
+	return tick, true
+}
@@ -0,0 +1,220 @@
+package stream
+
+import (
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/stream/mocks"
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func chart(price float64, previousClose float64) entity.ChartResult {
+	return entity.ChartResult{Series: entity.Series{
+		Symbol:        "AAPL",
+		Price:         price,
+		PriceTime:     time.Unix(1700000000, 0).UTC(),
+		PreviousClose: previousClose,
+		Bars: []entity.Bar{
+			{Timestamp: time.Unix(1699999800, 0).UTC(), Open: 100, High: 106, Low: 99, Close: 105, Volume: 1000},
+			{Timestamp: time.Unix(1699999860, 0).UTC(), Open: 105, High: 111, Low: 104, Close: 110, Volume: 2000},
+			{Timestamp: time.Unix(1699999920, 0).UTC(), Missing: true},
+		},
+	}}
+}
+
+func (h *hub) polled() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	symbols := make([]string, 0, len(h.pollers))
+	for symbol := range h.pollers {
+		symbols = append(symbols, symbol)
+	}
+	return symbols
+}
+
+func waitTicks(t *testing.T, subscriber *Subscriber) []entity.Tick {
+	select {
+	case <-subscriber.Ready():
+		return subscriber.Ticks()
+	case <-time.After(time.Second):
+		t.Fatal("no ticks")
+		return nil
+	}
+}
+
+func TestHubPollsOncePerSymbol(t *testing.T) {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	hub := New(logging.GetLogger("debug"), source, nil, Options{Interval: time.Hour})
+	hub.sequence = 0
+	defer hub.Close()
+	fetched := make(chan struct{})
+	source.EXPECT().Latest(gomock.Any(), entity.ChartQuery{Symbol: "AAPL"}).
+		DoAndReturn(func(_ interface{}, _ entity.ChartQuery) (entity.ChartResult, error) {
+			close(fetched)
+			return chart(110, 100), nil
+		}).Times(1)
+
+	first := hub.Connect()
+	symbols, err := first.Subscribe("aapl")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"AAPL"}, symbols)
+	<-fetched
+	assert.Equal(t, []entity.Tick{{
+		Id:     1,
+		Symbol: "AAPL",
+		Price:  110,
+		Change: 10,
+		Time:   time.Unix(1700000000, 0).UTC(),
+		Bar:    entity.Bar{Symbol: "AAPL", Timestamp: time.Unix(1699999860, 0).UTC(), Open: 105, High: 111, Low: 104, Close: 110, Volume: 2000},
+	}}, waitTicks(t, first), "the last bar that isn't missing is attached")
+
+	second := hub.Connect()
+	_, err = second.Subscribe("AAPL")
+	assert.NoError(t, err)
+	assert.Equal(t, "AAPL", waitTicks(t, second)[0].Symbol, "the last tick is pushed to a new subscriber")
+
+	first.Close()
+	assert.Equal(t, []string{"AAPL"}, hub.polled())
+	_, err = second.Unsubscribe("AAPL")
+	assert.NoError(t, err)
+	assert.Empty(t, hub.polled())
+}
+
+func TestSubscriberKeepsLatestTick(t *testing.T) {
+	hub := New(logging.GetLogger("debug"), nil, nil, Options{})
+	subscriber := &Subscriber{hub: hub, symbols: map[string]bool{"AAPL": true}, pending: map[string]entity.Tick{}, ready: make(chan struct{}, 1), done: make(chan struct{})}
+	for _, price := range []float64{1, 2, 3} {
+		subscriber.push(entity.Tick{Symbol: "AAPL", Price: price})
+	}
+	subscriber.push(entity.Tick{Symbol: "MSFT", Price: 4})
+	assert.Equal(t, []entity.Tick{{Symbol: "AAPL", Price: 3}}, waitTicks(t, subscriber))
+}
+
+func TestSubscribe(t *testing.T) {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	source.EXPECT().Latest(gomock.Any(), gomock.Any()).Return(chart(1, 0), nil).AnyTimes()
+	hub := New(logging.GetLogger("debug"), source, nil, Options{Interval: time.Hour, MaxSymbols: 2})
+	defer hub.Close()
+	testCases := []struct {
+		title   string
+		symbols []string
+		want    []string
+		isError bool
+	}{
+		{
+			title:   "symbols are normalized",
+			symbols: []string{" msft", "AAPL"},
+			want:    []string{"AAPL", "MSFT"},
+		},
+		{
+			title:   "symbols over the limit",
+			symbols: []string{"TSLA"},
+			isError: true,
+		},
+		{
+			title:   "incorrect symbol",
+			symbols: []string{"AAPL;DROP"},
+			isError: true,
+		},
+		{
+			title:   "subscribed symbols don't count twice",
+			symbols: []string{"AAPL"},
+			want:    []string{"AAPL", "MSFT"},
+		},
+	}
+	subscriber := hub.Connect()
+	defer subscriber.Close()
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			got, err := subscriber.Subscribe(test.symbols...)
+			if test.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.want, got)
+			}
+		})
+	}
+}
+
+func TestHubCloseDisconnectsSubscribers(t *testing.T) {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	source.EXPECT().Latest(gomock.Any(), gomock.Any()).Return(chart(1, 0), nil).AnyTimes()
+	hub := New(logging.GetLogger("debug"), source, nil, Options{Interval: time.Hour})
+	subscriber := hub.Connect()
+	_, err := subscriber.Subscribe("AAPL")
+	assert.NoError(t, err)
+	assert.NoError(t, hub.Close())
+	select {
+	case <-subscriber.Done():
+	case <-time.After(time.Second):
+		t.Fatal("subscriber isn't disconnected")
+	}
+	select {
+	case <-hub.Connect().Done():
+	default:
+		t.Fatal("connection to a closed hub isn't done")
+	}
+}
+
+func TestHubFollowsBroker(t *testing.T) {
+	cntr := gomock.NewController(t)
+	source := mocks.NewMockPriceSource(cntr)
+	broker := mocks.NewMockBroker(cntr)
+	hub := New(logging.GetLogger("debug"), source, broker, Options{Interval: time.Hour})
+	hub.sequence = 0
+	defer hub.Close()
+	campaigned := make(chan struct{})
+	broker.EXPECT().Campaign(gomock.Any(), "AAPL").DoAndReturn(func(_ interface{}, _ string) bool {
+		close(campaigned)
+		return false
+	})
+	broker.EXPECT().Resign("AAPL").AnyTimes()
+
+	subscriber := hub.Connect()
+	defer subscriber.Close()
+	_, err := subscriber.Subscribe("AAPL")
+	assert.NoError(t, err)
+	<-campaigned
+	hub.OnQuote(entity.Tick{Id: 5, Symbol: "AAPL", Price: 110})
+	assert.Equal(t, []entity.Tick{{Id: 5, Symbol: "AAPL", Price: 110}}, waitTicks(t, subscriber), "the replica that lost the campaign streams the published ticks")
+	hub.OnQuote(entity.Tick{Id: 4, Symbol: "AAPL", Price: 100})
+	hub.OnQuote(entity.Tick{Id: 6, Symbol: "MSFT", Price: 300})
+	hub.OnQuote(entity.Tick{Id: 7, Symbol: "AAPL", Price: 111})
+	assert.Equal(t, []entity.Tick{{Id: 7, Symbol: "AAPL", Price: 111}}, waitTicks(t, subscriber), "older ticks and ticks of other symbols are dropped")
+
+	published := make(chan entity.Tick, 1)
+	broker.EXPECT().Campaign(gomock.Any(), "MSFT").Return(true)
+	source.EXPECT().Latest(gomock.Any(), entity.ChartQuery{Symbol: "MSFT"}).Return(chart(300, 0), nil)
+	broker.EXPECT().Publish(gomock.Any()).DoAndReturn(func(tick entity.Tick) error {
+		published <- tick
+		return nil
+	})
+	broker.EXPECT().Resign("MSFT").AnyTimes()
+	_, err = subscriber.Subscribe("MSFT")
+	assert.NoError(t, err)
+	select {
+	case tick := <-published:
+		assert.Equal(t, uint64(8), tick.Id, "the ids continue from the received ticks")
+		assert.Equal(t, 300.0, tick.Price)
+	case <-time.After(time.Second):
+		t.Fatal("the winner of the campaign doesn't publish")
+	}
+}
+
+func TestBookKeepsLatestQuote(t *testing.T) {
+	book := NewBook()
+	book.OnQuote(entity.Tick{Id: 2, Symbol: "AAPL", Price: 110, Time: time.Unix(20, 0)})
+	book.OnQuote(entity.Tick{Id: 1, Symbol: "AAPL", Price: 100, Time: time.Unix(10, 0)})
+	tick, ok := book.Latest("AAPL")
+	assert.True(t, ok)
+	assert.Equal(t, 110.0, tick.Price)
+	_, ok = book.Latest("MSFT")
+	assert.False(t, ok)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./internal/stream/hub.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	entity "github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPriceSource is a mock of PriceSource interface.
+type MockPriceSource struct {
+	ctrl     *gomock.Controller
+	recorder *MockPriceSourceMockRecorder
+}
+
+// MockPriceSourceMockRecorder is the mock recorder for MockPriceSource.
+type MockPriceSourceMockRecorder struct {
+	mock *MockPriceSource
+}
+
+// NewMockPriceSource creates a new mock instance.
+func NewMockPriceSource(ctrl *gomock.Controller) *MockPriceSource {
+	mock := &MockPriceSource{ctrl: ctrl}
+	mock.recorder = &MockPriceSourceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPriceSource) EXPECT() *MockPriceSourceMockRecorder {
+	return m.recorder
+}
+
+// Latest mocks base method.
+func (m *MockPriceSource) Latest(ctx context.Context, query entity.ChartQuery) (entity.ChartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", ctx, query)
+	ret0, _ := ret[0].(entity.ChartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MockPriceSourceMockRecorder) Latest(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*MockPriceSource)(nil).Latest), ctx, query)
+}
+
+// MockBroker is a mock of Broker interface.
+type MockBroker struct {
+	ctrl     *gomock.Controller
+	recorder *MockBrokerMockRecorder
+}
+
+// MockBrokerMockRecorder is the mock recorder for MockBroker.
+type MockBrokerMockRecorder struct {
+	mock *MockBroker
+}
+
+// NewMockBroker creates a new mock instance.
+func NewMockBroker(ctrl *gomock.Controller) *MockBroker {
+	mock := &MockBroker{ctrl: ctrl}
+	mock.recorder = &MockBrokerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBroker) EXPECT() *MockBrokerMockRecorder {
+	return m.recorder
+}
+
+// Campaign mocks base method.
+func (m *MockBroker) Campaign(ctx context.Context, symbol string) bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Campaign", ctx, symbol)
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Campaign indicates an expected call of Campaign.
+func (mr *MockBrokerMockRecorder) Campaign(ctx, symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Campaign", reflect.TypeOf((*MockBroker)(nil).Campaign), ctx, symbol)
+}
+
+// Publish mocks base method.
+func (m *MockBroker) Publish(tick entity.Tick) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", tick)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockBrokerMockRecorder) Publish(tick interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockBroker)(nil).Publish), tick)
+}
+
+// Resign mocks base method.
+func (m *MockBroker) Resign(symbol string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Resign", symbol)
+}
+
+// Resign indicates an expected call of Resign.
+func (mr *MockBrokerMockRecorder) Resign(symbol interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Resign", reflect.TypeOf((*MockBroker)(nil).Resign), symbol)
+}
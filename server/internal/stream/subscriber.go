@@ -0,0 +1,156 @@
+package stream
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/VrMolodyakov/stock-market/internal/domain/entity"
+	"github.com/VrMolodyakov/stock-market/internal/errs"
+)
+
+var symbolPattern = regexp.MustCompile(`^[A-Z0-9.^=\-]{1,32}$`)
+
+// Subscriber is the connection of a single client to the hub. It keeps only
+// the latest tick of every symbol until the client takes it, so a slow client
+// skips the intermediate prices instead of slowing down the hub or piling up
+// memory.
+type Subscriber struct {
+	hub *hub
+
+	mu        sync.Mutex
+	symbols   map[string]bool
+	pending   map[string]entity.Tick
+	ready     chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Subscribe adds the symbols and returns all subscribed symbols.
+func (s *Subscriber) Subscribe(symbols ...string) ([]string, error) {
+	normalized, err := normalize(symbols)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	added := make([]string, 0, len(normalized))
+	for _, symbol := range normalized {
+		if !s.symbols[symbol] {
+			added = append(added, symbol)
+		}
+	}
+	if max := s.hub.options.MaxSymbols; max > 0 && len(s.symbols)+len(added) > max {
+		s.mu.Unlock()
+		return nil, errs.New(errs.Validation, errs.Code("too many symbols"), errs.Parameter("symbols"))
+	}
+	for _, symbol := range added {
+		s.symbols[symbol] = true
+	}
+	s.mu.Unlock()
+	for _, symbol := range added {
+		s.hub.subscribe(s, symbol)
+	}
+	return s.Symbols(), nil
+}
+
+// Unsubscribe removes the symbols and returns the symbols left.
+func (s *Subscriber) Unsubscribe(symbols ...string) ([]string, error) {
+	normalized, err := normalize(symbols)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	removed := make([]string, 0, len(normalized))
+	for _, symbol := range normalized {
+		if s.symbols[symbol] {
+			delete(s.symbols, symbol)
+			delete(s.pending, symbol)
+			removed = append(removed, symbol)
+		}
+	}
+	s.mu.Unlock()
+	for _, symbol := range removed {
+		s.hub.unsubscribe(s, symbol)
+	}
+	return s.Symbols(), nil
+}
+
+func (s *Subscriber) Symbols() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// Ready is signalled when there are ticks to take.
+func (s *Subscriber) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Done is closed when the subscriber or the hub is closed.
+func (s *Subscriber) Done() <-chan struct{} {
+	return s.done
+}
+
+// Ticks takes the pending ticks ordered by symbol.
+func (s *Subscriber) Ticks() []entity.Tick {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ticks := make([]entity.Tick, 0, len(s.pending))
+	for _, tick := range s.pending {
+		ticks = append(ticks, tick)
+	}
+	s.pending = make(map[string]entity.Tick)
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i].Symbol < ticks[j].Symbol })
+	return ticks
+}
+
+func (s *Subscriber) Close() {
+	s.mu.Lock()
+	symbols := make([]string, 0, len(s.symbols))
+	for symbol := range s.symbols {
+		symbols = append(symbols, symbol)
+	}
+	s.symbols = make(map[string]bool)
+	s.pending = make(map[string]entity.Tick)
+	s.mu.Unlock()
+	for _, symbol := range symbols {
+		s.hub.unsubscribe(s, symbol)
+	}
+	s.closeDone()
+}
+
+func (s *Subscriber) push(tick entity.Tick) {
+	s.mu.Lock()
+	if !s.symbols[tick.Symbol] {
+		s.mu.Unlock()
+		return
+	}
+	s.pending[tick.Symbol] = tick
+	s.mu.Unlock()
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Subscriber) closeDone() {
+	s.closeOnce.Do(func() { close(s.done) })
+}
+
+func normalize(symbols []string) ([]string, error) {
+	normalized := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if !symbolPattern.MatchString(symbol) {
+			return nil, errs.New(errs.Validation, errs.Code("incorrect symbol"), errs.Parameter("symbols"))
+		}
+		normalized = append(normalized, symbol)
+	}
+	return normalized, nil
+}
@@ -0,0 +1,181 @@
+package calendar
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// the docker image has no zoneinfo, so the database is embedded
+	_ "time/tzdata"
+)
+
+const dayLayout = "2006-01-02"
+
+// lookahead bounds the search of the next session, it is longer than any
+// realistic run of weekends and holidays.
+const lookahead = 30
+
+type Exchange struct {
+	Code       string
+	Location   *time.Location
+	Open       time.Duration
+	Close      time.Duration
+	AlwaysOpen bool
+	holidays   map[string]bool
+}
+
+type Status struct {
+	Open      bool
+	NextOpen  time.Time
+	NextClose time.Time
+}
+
+type Calendar struct {
+	exchanges map[string]*Exchange
+	fallback  *Exchange
+}
+
+type definition struct {
+	code     string
+	zone     string
+	open     time.Duration
+	close    time.Duration
+	names    []string
+	suffixes []string
+}
+
+var definitions = []definition{
+	{code: "NYSE", zone: "America/New_York", open: clock(9, 30), close: clock(16, 0), names: []string{"NYQ", "ASE", "PCX", "BTS"}},
+	{code: "NASDAQ", zone: "America/New_York", open: clock(9, 30), close: clock(16, 0), names: []string{"NMS", "NGM", "NCM", "NAS"}},
+	{code: "LSE", zone: "Europe/London", open: clock(8, 0), close: clock(16, 30), names: []string{"LSE", "IOB"}, suffixes: []string{".L", ".IL"}},
+	{code: "XETRA", zone: "Europe/Berlin", open: clock(9, 0), close: clock(17, 30), names: []string{"GER", "FRA"}, suffixes: []string{".DE", ".F"}},
+	{code: "MOEX", zone: "Europe/Moscow", open: clock(9, 50), close: clock(18, 50), names: []string{"MCX"}, suffixes: []string{".ME"}},
+	{code: "TSE", zone: "Asia/Tokyo", open: clock(9, 0), close: clock(15, 0), names: []string{"JPX"}, suffixes: []string{".T"}},
+}
+
+// New builds the calendar of the known exchanges. Holidays are keyed by the
+// exchange code and contain dates in the 2006-01-02 format.
+func New(holidays map[string][]string) (*Calendar, error) {
+	c := &Calendar{exchanges: make(map[string]*Exchange)}
+	for _, d := range definitions {
+		location, err := time.LoadLocation(d.zone)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't load time zone of %v: %w", d.code, err)
+		}
+		exchange := &Exchange{Code: d.code, Location: location, Open: d.open, Close: d.close, holidays: make(map[string]bool)}
+		c.exchanges[d.code] = exchange
+		for _, name := range d.names {
+			c.exchanges[name] = exchange
+		}
+		for _, suffix := range d.suffixes {
+			c.exchanges[suffix] = exchange
+		}
+	}
+	crypto := &Exchange{Code: "CRYPTO", Location: time.UTC, AlwaysOpen: true}
+	c.exchanges["CRYPTO"] = crypto
+	c.exchanges["CCC"] = crypto
+	c.fallback = c.exchanges["NYSE"]
+
+	for code, dates := range holidays {
+		exchange, ok := c.exchanges[strings.ToUpper(code)]
+		if !ok {
+			return nil, fmt.Errorf("unknown exchange %v", code)
+		}
+		for _, date := range dates {
+			if _, err := time.Parse(dayLayout, date); err != nil {
+				return nil, fmt.Errorf("incorrect holiday %v of %v: %w", date, code, err)
+			}
+			exchange.holidays[date] = true
+		}
+	}
+	return c, nil
+}
+
+// Exchange returns the exchange by its code, Yahoo exchange name or symbol suffix.
+func (c *Calendar) Exchange(code string) (*Exchange, bool) {
+	exchange, ok := c.exchanges[strings.ToUpper(code)]
+	return exchange, ok
+}
+
+// ForSymbol resolves the exchange by the exchange name reported by the
+// provider, then by the symbol suffix, and falls back to NYSE.
+func (c *Calendar) ForSymbol(symbol string, exchangeName string) *Exchange {
+	if exchange, ok := c.Exchange(exchangeName); ok && exchangeName != "" {
+		return exchange
+	}
+	if i := strings.LastIndex(symbol, "."); i > 0 {
+		if exchange, ok := c.Exchange(symbol[i:]); ok {
+			return exchange
+		}
+	}
+	return c.fallback
+}
+
+func (c *Calendar) Status(symbol string, exchangeName string, t time.Time) Status {
+	return c.ForSymbol(symbol, exchangeName).Status(t)
+}
+
+func (e *Exchange) IsTradingDay(t time.Time) bool {
+	if e.AlwaysOpen {
+		return true
+	}
+	local := t.In(e.Location)
+	if local.Weekday() == time.Saturday || local.Weekday() == time.Sunday {
+		return false
+	}
+	return !e.holidays[local.Format(dayLayout)]
+}
+
+func (e *Exchange) IsOpen(t time.Time) bool {
+	return e.Status(t).Open
+}
+
+func (e *Exchange) Status(t time.Time) Status {
+	if e.AlwaysOpen {
+		return Status{Open: true, NextOpen: t, NextClose: t.AddDate(100, 0, 0)}
+	}
+	local := t.In(e.Location)
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, e.Location)
+	var status Status
+	for i := 0; i <= lookahead; i++ {
+		date := day.AddDate(0, 0, i)
+		if !e.IsTradingDay(date) {
+			continue
+		}
+		openAt, closeAt := e.session(date)
+		if i == 0 && !local.Before(openAt) && local.Before(closeAt) {
+			status.Open = true
+			status.NextClose = closeAt
+			continue
+		}
+		if openAt.After(local) {
+			status.NextOpen = openAt
+			if !status.Open {
+				status.NextClose = closeAt
+			}
+			return status
+		}
+	}
+	return status
+}
+
+// SessionOpen returns the opening time of the session on the local date of t,
+// markets without sessions open at midnight.
+func (e *Exchange) SessionOpen(t time.Time) time.Time {
+	local := t.In(e.Location)
+	return e.at(local, e.Open)
+}
+
+// session returns the opening and closing time of the given day, the wall
+// clock is used so that sessions stay right on daylight saving switches.
+func (e *Exchange) session(date time.Time) (time.Time, time.Time) {
+	return e.at(date, e.Open), e.at(date, e.Close)
+}
+
+func (e *Exchange) at(date time.Time, offset time.Duration) time.Time {
+	return time.Date(date.Year(), date.Month(), date.Day(), int(offset/time.Hour), int(offset%time.Hour/time.Minute), 0, 0, e.Location)
+}
+
+func clock(hour int, minute int) time.Duration {
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+}
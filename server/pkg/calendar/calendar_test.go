@@ -0,0 +1,124 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatus(t *testing.T) {
+	c, err := New(map[string][]string{"NYSE": {"2022-11-24"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	moscow, _ := time.LoadLocation("Europe/Moscow")
+	testCases := []struct {
+		title         string
+		symbol        string
+		exchange      string
+		now           time.Time
+		wantOpen      bool
+		wantNextOpen  time.Time
+		wantNextClose time.Time
+	}{
+		{
+			title:         "open during the session",
+			symbol:        "AAPL",
+			exchange:      "NMS",
+			now:           time.Date(2022, time.October, 10, 11, 0, 0, 0, newYork),
+			wantOpen:      true,
+			wantNextOpen:  time.Date(2022, time.October, 11, 9, 30, 0, 0, newYork),
+			wantNextClose: time.Date(2022, time.October, 10, 16, 0, 0, 0, newYork),
+		},
+		{
+			title:         "closed before the session",
+			symbol:        "IBM",
+			now:           time.Date(2022, time.October, 10, 9, 0, 0, 0, newYork),
+			wantNextOpen:  time.Date(2022, time.October, 10, 9, 30, 0, 0, newYork),
+			wantNextClose: time.Date(2022, time.October, 10, 16, 0, 0, 0, newYork),
+		},
+		{
+			title:         "closed on friday evening until monday",
+			symbol:        "IBM",
+			now:           time.Date(2022, time.October, 7, 17, 0, 0, 0, newYork),
+			wantNextOpen:  time.Date(2022, time.October, 10, 9, 30, 0, 0, newYork),
+			wantNextClose: time.Date(2022, time.October, 10, 16, 0, 0, 0, newYork),
+		},
+		{
+			title:         "configured holiday is skipped",
+			symbol:        "IBM",
+			exchange:      "NYQ",
+			now:           time.Date(2022, time.November, 24, 12, 0, 0, 0, newYork),
+			wantNextOpen:  time.Date(2022, time.November, 25, 9, 30, 0, 0, newYork),
+			wantNextClose: time.Date(2022, time.November, 25, 16, 0, 0, 0, newYork),
+		},
+		{
+			title:         "symbol suffix selects the exchange",
+			symbol:        "SBER.ME",
+			now:           time.Date(2022, time.October, 10, 19, 0, 0, 0, moscow),
+			wantNextOpen:  time.Date(2022, time.October, 11, 9, 50, 0, 0, moscow),
+			wantNextClose: time.Date(2022, time.October, 11, 18, 50, 0, 0, moscow),
+		},
+		{
+			title:         "daylight saving switch keeps the wall clock",
+			symbol:        "IBM",
+			now:           time.Date(2022, time.November, 5, 12, 0, 0, 0, newYork),
+			wantNextOpen:  time.Date(2022, time.November, 7, 9, 30, 0, 0, newYork),
+			wantNextClose: time.Date(2022, time.November, 7, 16, 0, 0, 0, newYork),
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			got := c.Status(test.symbol, test.exchange, test.now)
+			assert.Equal(t, test.wantOpen, got.Open)
+			assert.True(t, test.wantNextOpen.Equal(got.NextOpen), "next open %v", got.NextOpen)
+			assert.True(t, test.wantNextClose.Equal(got.NextClose), "next close %v", got.NextClose)
+		})
+	}
+}
+
+func TestForSymbol(t *testing.T) {
+	c, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testCases := []struct {
+		symbol   string
+		exchange string
+		want     string
+	}{
+		{symbol: "AAPL", exchange: "NMS", want: "NASDAQ"},
+		{symbol: "IBM", exchange: "", want: "NYSE"},
+		{symbol: "VOD.L", exchange: "", want: "LSE"},
+		{symbol: "SAP.DE", exchange: "", want: "XETRA"},
+		{symbol: "BTC-USD", exchange: "CCC", want: "CRYPTO"},
+		{symbol: "BRK.B", exchange: "", want: "NYSE"},
+	}
+	for _, test := range testCases {
+		t.Run(test.symbol, func(t *testing.T) {
+			assert.Equal(t, test.want, c.ForSymbol(test.symbol, test.exchange).Code)
+		})
+	}
+}
+
+func TestNewRejectsUnknownHolidays(t *testing.T) {
+	_, err := New(map[string][]string{"MARS": {"2022-01-01"}})
+	assert.Error(t, err)
+	_, err = New(map[string][]string{"NYSE": {"01/01/2022"}})
+	assert.Error(t, err)
+}
+
+func TestSessionOpen(t *testing.T) {
+	c, err := New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newYork, _ := time.LoadLocation("America/New_York")
+	nyse, _ := c.Exchange("NYSE")
+	crypto, _ := c.Exchange("CRYPTO")
+	assert.Equal(t, time.Date(2022, time.October, 10, 9, 30, 0, 0, newYork), nyse.SessionOpen(time.Date(2022, time.October, 10, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2022, time.October, 10, 9, 30, 0, 0, newYork), nyse.SessionOpen(time.Date(2022, time.October, 10, 8, 0, 0, 0, newYork)), "pre-market belongs to the day of the session")
+	assert.Equal(t, time.Date(2022, time.October, 10, 0, 0, 0, 0, time.UTC), crypto.SessionOpen(time.Date(2022, time.October, 10, 15, 0, 0, 0, time.UTC)))
+}
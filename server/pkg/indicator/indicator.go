@@ -0,0 +1,276 @@
+package indicator
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	MaxSpecs  = 10
+	maxPeriod = 500
+)
+
+// Spec is a parsed indicator like sma:20 or macd:12:26:9, omitted parameters
+// take the usual defaults.
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+var defaults = map[string][]float64{
+	"sma":  {20},
+	"ema":  {20},
+	"rsi":  {14},
+	"macd": {12, 26, 9},
+	"bb":   {20, 2},
+}
+
+// Parse reads a comma separated list of indicators. Duplicates are dropped.
+func Parse(names string) ([]Spec, error) {
+	specs := make([]Spec, 0)
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(names, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		spec, err := parseSpec(name)
+		if err != nil {
+			return nil, err
+		}
+		if seen[spec.String()] {
+			continue
+		}
+		seen[spec.String()] = true
+		specs = append(specs, spec)
+	}
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("no indicators")
+	}
+	if len(specs) > MaxSpecs {
+		return nil, fmt.Errorf("at most %v indicators are allowed", MaxSpecs)
+	}
+	return specs, nil
+}
+
+func parseSpec(name string) (Spec, error) {
+	parts := strings.Split(name, ":")
+	params, ok := defaults[parts[0]]
+	if !ok {
+		return Spec{}, fmt.Errorf("unknown indicator %v", parts[0])
+	}
+	if len(parts)-1 > len(params) {
+		return Spec{}, fmt.Errorf("too many parameters of %v", parts[0])
+	}
+	spec := Spec{Name: parts[0], Params: append([]float64(nil), params...)}
+	for i, part := range parts[1:] {
+		value, err := strconv.ParseFloat(part, 64)
+		if err != nil || value <= 0 || value > maxPeriod {
+			return Spec{}, fmt.Errorf("incorrect parameter %v of %v", part, parts[0])
+		}
+		spec.Params[i] = value
+	}
+	// every parameter but the width of the bollinger bands is a period
+	for i, value := range spec.Params {
+		if (spec.Name != "bb" || i == 0) && value != math.Trunc(value) {
+			return Spec{}, fmt.Errorf("period of %v must be an integer", spec.Name)
+		}
+	}
+	if spec.Name == "macd" && spec.Params[0] >= spec.Params[1] {
+		return Spec{}, fmt.Errorf("fast period of macd must be shorter than the slow one")
+	}
+	return spec, nil
+}
+
+func (s Spec) String() string {
+	parts := []string{s.Name}
+	for _, value := range s.Params {
+		parts = append(parts, strconv.FormatFloat(value, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+// Compute returns the lines of the indicator keyed by name. Indicators with a
+// single line use the name of the spec, the others add a suffix to it. NaN
+// values of the input are gaps, they are skipped by the calculation and stay
+// NaN in the output, so the lines are aligned with the input.
+func Compute(spec Spec, values []float64) map[string][]float64 {
+	present, index := compact(values)
+	var lines map[string][]float64
+	switch spec.Name {
+	case "sma":
+		lines = map[string][]float64{spec.String(): SMA(present, int(spec.Params[0]))}
+	case "ema":
+		lines = map[string][]float64{spec.String(): EMA(present, int(spec.Params[0]))}
+	case "rsi":
+		lines = map[string][]float64{spec.String(): RSI(present, int(spec.Params[0]))}
+	case "macd":
+		macd, signal, histogram := MACD(present, int(spec.Params[0]), int(spec.Params[1]), int(spec.Params[2]))
+		lines = map[string][]float64{
+			spec.String() + ".macd":      macd,
+			spec.String() + ".signal":    signal,
+			spec.String() + ".histogram": histogram,
+		}
+	case "bb":
+		middle, upper, lower := Bollinger(present, int(spec.Params[0]), spec.Params[1])
+		lines = map[string][]float64{
+			spec.String() + ".middle": middle,
+			spec.String() + ".upper":  upper,
+			spec.String() + ".lower":  lower,
+		}
+	}
+	for name, line := range lines {
+		lines[name] = expand(line, index, len(values))
+	}
+	return lines
+}
+
+func compact(values []float64) ([]float64, []int) {
+	present := make([]float64, 0, len(values))
+	index := make([]int, 0, len(values))
+	for i, value := range values {
+		if math.IsNaN(value) {
+			continue
+		}
+		present = append(present, value)
+		index = append(index, i)
+	}
+	return present, index
+}
+
+func expand(line []float64, index []int, size int) []float64 {
+	out := nans(size)
+	for i, value := range line {
+		out[index[i]] = value
+	}
+	return out
+}
+
+func nans(size int) []float64 {
+	out := make([]float64, size)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+// SMA is the simple moving average, the first period-1 values are NaN.
+func SMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	var sum float64
+	for i, value := range values {
+		sum += value
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA is the exponential moving average seeded with the SMA of the first
+// period values. Leading NaN values are skipped.
+func EMA(values []float64, period int) []float64 {
+	out := nans(len(values))
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < period {
+		return out
+	}
+	alpha := 2 / float64(period+1)
+	var sum float64
+	for i := start; i < start+period; i++ {
+		sum += values[i]
+	}
+	ema := sum / float64(period)
+	out[start+period-1] = ema
+	for i := start + period; i < len(values); i++ {
+		ema = alpha*values[i] + (1-alpha)*ema
+		out[i] = ema
+	}
+	return out
+}
+
+// RSI is the relative strength index with the smoothing of Wilder.
+func RSI(values []float64, period int) []float64 {
+	out := nans(len(values))
+	if len(values) <= period {
+		return out
+	}
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsi(gain, loss)
+	for i := period + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		up, down := 0.0, 0.0
+		if change > 0 {
+			up = change
+		} else {
+			down = -change
+		}
+		gain = (gain*float64(period-1) + up) / float64(period)
+		loss = (loss*float64(period-1) + down) / float64(period)
+		out[i] = rsi(gain, loss)
+	}
+	return out
+}
+
+func rsi(gain float64, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// MACD returns the difference of the fast and slow EMA, its signal EMA and
+// the histogram between them.
+func MACD(values []float64, fast int, slow int, signal int) ([]float64, []float64, []float64) {
+	fastEma := EMA(values, fast)
+	slowEma := EMA(values, slow)
+	macd := make([]float64, len(values))
+	for i := range values {
+		macd[i] = fastEma[i] - slowEma[i]
+	}
+	signalEma := EMA(macd, signal)
+	histogram := make([]float64, len(values))
+	for i := range values {
+		histogram[i] = macd[i] - signalEma[i]
+	}
+	return macd, signalEma, histogram
+}
+
+// Bollinger returns the SMA and the bands width standard deviations away
+// from it.
+func Bollinger(values []float64, period int, width float64) ([]float64, []float64, []float64) {
+	middle := SMA(values, period)
+	upper := nans(len(values))
+	lower := nans(len(values))
+	for i := period - 1; i < len(values); i++ {
+		var variance float64
+		for _, value := range values[i-period+1 : i+1] {
+			variance += (value - middle[i]) * (value - middle[i])
+		}
+		deviation := math.Sqrt(variance / float64(period))
+		upper[i] = middle[i] + width*deviation
+		lower[i] = middle[i] - width*deviation
+	}
+	return middle, upper, lower
+}
@@ -0,0 +1,115 @@
+package indicator
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var n = math.NaN()
+
+func assertLine(t *testing.T, want []float64, got []float64) {
+	t.Helper()
+	if !assert.Len(t, got, len(want)) {
+		return
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) {
+			assert.True(t, math.IsNaN(got[i]), "value %v must be NaN, got %v", i, got[i])
+		} else {
+			assert.InDelta(t, want[i], got[i], 1e-9, "value %v", i)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		title   string
+		names   string
+		want    []string
+		isError bool
+	}{
+		{
+			title: "parameters default to the usual periods",
+			names: "sma:50, RSI,macd,bb:20:2.5",
+			want:  []string{"sma:50", "rsi:14", "macd:12:26:9", "bb:20:2.5"},
+		},
+		{
+			title: "duplicates are dropped",
+			names: "sma,sma:20",
+			want:  []string{"sma:20"},
+		},
+		{
+			title:   "unknown indicator",
+			names:   "vwap",
+			isError: true,
+		},
+		{
+			title:   "fractional period",
+			names:   "sma:2.5",
+			isError: true,
+		},
+		{
+			title:   "too many parameters",
+			names:   "rsi:14:2",
+			isError: true,
+		},
+		{
+			title:   "fast macd period longer than the slow one",
+			names:   "macd:26:12",
+			isError: true,
+		},
+		{
+			title:   "period over the limit",
+			names:   "ema:1000",
+			isError: true,
+		},
+		{
+			title:   "no indicators",
+			names:   " , ",
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			specs, err := Parse(test.names)
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			got := make([]string, 0, len(specs))
+			for _, spec := range specs {
+				got = append(got, spec.String())
+			}
+			assert.Equal(t, test.want, got)
+		})
+	}
+}
+
+func TestIndicators(t *testing.T) {
+	assertLine(t, []float64{n, n, 2, 3, 4}, SMA([]float64{1, 2, 3, 4, 5}, 3))
+	assertLine(t, []float64{n, n, 2, 3, 4}, EMA([]float64{1, 2, 3, 4, 5}, 3))
+	assertLine(t, []float64{n, n}, EMA([]float64{1, 2}, 3))
+	assertLine(t, []float64{n, n, 100, 50, 75}, RSI([]float64{1, 2, 3, 2, 3}, 2))
+
+	macd, signal, histogram := MACD([]float64{1, 2, 3}, 1, 2, 1)
+	assertLine(t, []float64{n, 0.5, 0.5}, macd)
+	assertLine(t, []float64{n, 0.5, 0.5}, signal)
+	assertLine(t, []float64{n, 0, 0}, histogram)
+
+	middle, upper, lower := Bollinger([]float64{1, 2, 3}, 2, 2)
+	assertLine(t, []float64{n, 1.5, 2.5}, middle)
+	assertLine(t, []float64{n, 2.5, 3.5}, upper)
+	assertLine(t, []float64{n, 0.5, 1.5}, lower)
+}
+
+func TestComputeSkipsGaps(t *testing.T) {
+	lines := Compute(Spec{Name: "sma", Params: []float64{2}}, []float64{1, n, 3, 5})
+	assertLine(t, []float64{n, n, 2, 4}, lines["sma:2"])
+
+	lines = Compute(Spec{Name: "bb", Params: []float64{2, 2}}, []float64{1, 2, n, 3})
+	assert.Len(t, lines, 3)
+	assertLine(t, []float64{n, 2.5, n, 3.5}, lines["bb:2:2.upper"])
+}
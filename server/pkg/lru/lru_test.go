@@ -0,0 +1,43 @@
+package lru
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEviction(t *testing.T) {
+	cache := New(2)
+	cache.Add("a", 1, time.Minute)
+	cache.Add("b", 2, time.Minute)
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Add("c", 3, time.Minute)
+
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+	value, ok = cache.Get("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, value)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestExpiration(t *testing.T) {
+	now := time.Unix(100, 0)
+	cache := New(2)
+	cache.now = func() time.Time { return now }
+	cache.Add("a", 1, time.Second)
+	cache.Add("b", 2, time.Minute)
+	cache.Add("b", 3, time.Second)
+
+	now = now.Add(time.Second)
+	_, ok := cache.Get("a")
+	assert.False(t, ok)
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
@@ -17,3 +17,11 @@ func balanceActivityCounter() *prometheus.CounterVec {
 		Help:      "Stock rate history",
 	}, []string{"activity", "client"})
 }
+
+func upstreamRequestCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "client",
+		Name:      "upstream_request_counter",
+		Help:      "Number of chart requests to the quote providers by whether they went upstream or were coalesced",
+	}, []string{"type"})
+}
@@ -5,6 +5,8 @@ import "github.com/prometheus/client_golang/prometheus"
 type Metric struct {
 	HTTPResponseCounter       *prometheus.CounterVec
 	ResponseDurationHistogram *prometheus.HistogramVec
+	UpstreamRequestCounter    *prometheus.CounterVec
+	BalanceActivityCounter    *prometheus.CounterVec
 }
 
 func NewMetric(registry *prometheus.Registry) Metric {
@@ -16,5 +18,10 @@ func NewMetric(registry *prometheus.Registry) Metric {
 	m.ResponseDurationHistogram = responseDurationHistogram()
 	registry.MustRegister(m.ResponseDurationHistogram)
 
+	m.UpstreamRequestCounter = upstreamRequestCounter()
+	registry.MustRegister(m.UpstreamRequestCounter)
+	m.BalanceActivityCounter = balanceActivityCounter()
+	registry.MustRegister(m.BalanceActivityCounter)
+
 	return *m
 }
@@ -0,0 +1,58 @@
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+)
+
+var ErrForbidden = errors.New("address is not public")
+
+// blocked are the ranges not covered by the checks of net.IP.
+var blocked = []*net.IPNet{
+	cidr("0.0.0.0/8"),
+	cidr("100.64.0.0/10"),
+	cidr("192.0.0.0/24"),
+	cidr("198.18.0.0/15"),
+	cidr("240.0.0.0/4"),
+	cidr("64:ff9b::/96"),
+}
+
+// Public reports whether the address may be reached by the requests made on
+// behalf of the users. Loopback, private, link-local and reserved addresses
+// are not public.
+func Public(ip net.IP) bool {
+	if ip == nil || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, network := range blocked {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
+
+// Control is the control function of a net.Dialer. It's called with the
+// resolved address right before the connection, so a host that resolves to
+// another address after it was checked is still refused.
+func Control(network string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if !Public(net.ParseIP(host)) {
+		return fmt.Errorf("dial %v: %w", host, ErrForbidden)
+	}
+	return nil
+}
+
+func cidr(s string) *net.IPNet {
+	_, network, err := net.ParseCIDR(s)
+	if err != nil {
+		panic(err)
+	}
+	return network
+}
@@ -0,0 +1,47 @@
+package netguard
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublic(t *testing.T) {
+	testCases := []struct {
+		address string
+		want    bool
+	}{
+		{address: "93.184.216.34", want: true},
+		{address: "2606:2800:220:1:248:1893:25c8:1946", want: true},
+		{address: "127.0.0.1"},
+		{address: "::1"},
+		{address: "10.1.2.3"},
+		{address: "172.16.0.1"},
+		{address: "192.168.1.1"},
+		{address: "169.254.169.254"},
+		{address: "fe80::1"},
+		{address: "fd00::1"},
+		{address: "0.0.0.0"},
+		{address: "100.64.0.1"},
+		{address: "::ffff:127.0.0.1"},
+		{address: "::ffff:169.254.169.254"},
+		{address: "64:ff9b::a9fe:a9fe"},
+	}
+	for _, test := range testCases {
+		t.Run(test.address, func(t *testing.T) {
+			assert.Equal(t, test.want, Public(net.ParseIP(test.address)))
+		})
+	}
+}
+
+func TestControl(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := &http.Client{Transport: &http.Transport{DialContext: (&net.Dialer{Control: Control}).DialContext}}
+	_, err := client.Get(server.URL)
+	assert.True(t, errors.Is(err, ErrForbidden))
+}
@@ -0,0 +1,293 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/golang-jwt/jwt"
+)
+
+const (
+	activeFile = "active"
+	// DefaultAlgorithm signs the tokens when the config doesn't name one.
+	DefaultAlgorithm = "RS512"
+)
+
+var methods = map[string]jwt.SigningMethod{
+	jwt.SigningMethodRS256.Alg(): jwt.SigningMethodRS256,
+	jwt.SigningMethodRS512.Alg(): jwt.SigningMethodRS512,
+	jwt.SigningMethodES256.Alg(): jwt.SigningMethodES256,
+	jwt.SigningMethodEdDSA.Alg(): jwt.SigningMethodEdDSA,
+}
+
+// Method returns the signing method of the algorithm.
+func Method(algorithm string) (jwt.SigningMethod, error) {
+	if algorithm == "" {
+		algorithm = DefaultAlgorithm
+	}
+	method, ok := methods[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("unsupported algorithm %q", algorithm)
+	}
+	return method, nil
+}
+
+// Key is a signing key of the ring, the keys without a private key only
+// verify the tokens signed before they were rotated out. The tokens of the
+// key are signed and accepted only with its method.
+type Key struct {
+	Id      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+	Public  crypto.PublicKey
+}
+
+// KeyRing holds the keys accepted for verification and the active key new
+// tokens are signed with.
+type KeyRing struct {
+	mu     sync.RWMutex
+	keys   map[string]Key
+	active string
+}
+
+func NewKeyRing(keys []Key, active string) (*KeyRing, error) {
+	ring := &KeyRing{}
+	if err := ring.Replace(keys, active); err != nil {
+		return nil, err
+	}
+	return ring, nil
+}
+
+// Replace swaps the keys of the ring, the active key must have a private key.
+func (r *KeyRing) Replace(keys []Key, active string) error {
+	byId := make(map[string]Key, len(keys))
+	for _, key := range keys {
+		if key.Method == nil {
+			return fmt.Errorf("no method for key %q", key.Id)
+		}
+		byId[key.Id] = key
+	}
+	if key, ok := byId[active]; !ok || key.Private == nil {
+		return fmt.Errorf("no private key for active key %q", active)
+	}
+	r.mu.Lock()
+	r.keys, r.active = byId, active
+	r.mu.Unlock()
+	return nil
+}
+
+func (r *KeyRing) Signer() Key {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.keys[r.active]
+}
+
+// Verifier returns the key of the kid, tokens without a kid are verified
+// with the active key.
+func (r *KeyRing) Verifier(kid string) (Key, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if kid == "" {
+		kid = r.active
+	}
+	key, ok := r.keys[kid]
+	return key, ok
+}
+
+func (r *KeyRing) Keys() []Key {
+	r.mu.RLock()
+	keys := make([]Key, 0, len(r.keys))
+	for _, key := range r.keys {
+		keys = append(keys, key)
+	}
+	r.mu.RUnlock()
+	sort.Slice(keys, func(i, j int) bool { return keys[i].Id < keys[j].Id })
+	return keys
+}
+
+// JWK is the public part of a key as published in the JWKS.
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the ring.
+func (r *KeyRing) JWKS() JWKSet {
+	keys := r.Keys()
+	set := JWKSet{Keys: make([]JWK, 0, len(keys))}
+	for _, key := range keys {
+		jwk, err := publicJWK(key.Public)
+		if err != nil {
+			continue
+		}
+		jwk.Use, jwk.Alg, jwk.Kid = "sig", key.Method.Alg(), key.Id
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+// ParseKey reads a private or a public key in PEM of the algorithm.
+func ParseKey(id string, algorithm string, data []byte) (Key, error) {
+	method, err := Method(algorithm)
+	if err != nil {
+		return Key{}, err
+	}
+	key := Key{Id: id, Method: method}
+	switch m := method.(type) {
+	case *jwt.SigningMethodRSA:
+		var private *rsa.PrivateKey
+		if private, err = jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			key.Private, key.Public = private, &private.PublicKey
+		} else {
+			key.Public, err = jwt.ParseRSAPublicKeyFromPEM(data)
+		}
+	case *jwt.SigningMethodECDSA:
+		var private *ecdsa.PrivateKey
+		var public *ecdsa.PublicKey
+		if private, err = jwt.ParseECPrivateKeyFromPEM(data); err == nil {
+			public = &private.PublicKey
+			key.Private = private
+		} else {
+			public, err = jwt.ParseECPublicKeyFromPEM(data)
+		}
+		if err == nil && public.Curve.Params().BitSize != m.CurveBits {
+			err = fmt.Errorf("curve %v doesn't match %v", public.Curve.Params().Name, m.Alg())
+		}
+		key.Public = public
+	case *jwt.SigningMethodEd25519:
+		var private crypto.PrivateKey
+		if private, err = jwt.ParseEdPrivateKeyFromPEM(data); err == nil {
+			signer := private.(ed25519.PrivateKey)
+			key.Private, key.Public = signer, signer.Public()
+		} else {
+			key.Public, err = jwt.ParseEdPublicKeyFromPEM(data)
+		}
+	}
+	if err != nil {
+		return Key{}, fmt.Errorf("couldn't parse %v key %v: %w", method.Alg(), id, err)
+	}
+	return key, nil
+}
+
+// LoadDir reads the keys from the directory. Every <kid>.<alg>.pem file holds
+// a private or a public key of the algorithm, so that a ring can mix the keys
+// of the old and the new algorithm during a migration. The keys of <kid>.pem
+// files are of the given algorithm. The file named active holds the kid of the
+// signer, it may be omitted when the directory has one private key.
+func LoadDir(dir string, algorithm string) ([]Key, string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, "", err
+	}
+	keys := make([]Key, 0, len(paths))
+	signers := make([]string, 0)
+	seen := make(map[string]string, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, "", err
+		}
+		id, keyAlgorithm := splitName(filepath.Base(path), algorithm)
+		if other, ok := seen[id]; ok {
+			return nil, "", fmt.Errorf("key %v is in both %v and %v", id, other, filepath.Base(path))
+		}
+		seen[id] = filepath.Base(path)
+		key, err := ParseKey(id, keyAlgorithm, data)
+		if err != nil {
+			return nil, "", err
+		}
+		keys = append(keys, key)
+		if key.Private != nil {
+			signers = append(signers, key.Id)
+		}
+	}
+	active, err := os.ReadFile(filepath.Join(dir, activeFile))
+	switch {
+	case err == nil:
+		return keys, strings.TrimSpace(string(active)), nil
+	case errors.Is(err, os.ErrNotExist) && len(signers) == 1:
+		return keys, signers[0], nil
+	case errors.Is(err, os.ErrNotExist):
+		return nil, "", fmt.Errorf("no active key in %v", dir)
+	}
+	return nil, "", err
+}
+
+// splitName returns the kid and the algorithm of a key file, the suffix of the
+// kid is the algorithm only when it's a supported one.
+func splitName(name string, algorithm string) (string, string) {
+	id := strings.TrimSuffix(name, ".pem")
+	if i := strings.LastIndex(id, "."); i > 0 {
+		if _, ok := methods[id[i+1:]]; ok {
+			return id[:i], id[i+1:]
+		}
+	}
+	return id, algorithm
+}
+
+// Thumbprint returns the RFC 7638 thumbprint of the key, it is the kid of the
+// keys that are not named.
+func Thumbprint(public crypto.PublicKey) (string, error) {
+	jwk, err := publicJWK(public)
+	if err != nil {
+		return "", err
+	}
+	// the required members only, json sorts the keys of a map
+	members := map[string]string{"kty": jwk.Kty}
+	switch jwk.Kty {
+	case "RSA":
+		members["n"], members["e"] = jwk.N, jwk.E
+	case "EC":
+		members["crv"], members["x"], members["y"] = jwk.Crv, jwk.X, jwk.Y
+	case "OKP":
+		members["crv"], members["x"] = jwk.Crv, jwk.X
+	}
+	data, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func publicJWK(public crypto.PublicKey) (JWK, error) {
+	encode := base64.RawURLEncoding.EncodeToString
+	switch key := public.(type) {
+	case *rsa.PublicKey:
+		return JWK{Kty: "RSA", N: encode(key.N.Bytes()), E: encode(big.NewInt(int64(key.E)).Bytes())}, nil
+	case *ecdsa.PublicKey:
+		// coordinates are padded to the size of the curve
+		size := (key.Curve.Params().BitSize + 7) / 8
+		x, y := make([]byte, size), make([]byte, size)
+		key.X.FillBytes(x)
+		key.Y.FillBytes(y)
+		return JWK{Kty: "EC", Crv: key.Curve.Params().Name, X: encode(x), Y: encode(y)}, nil
+	case ed25519.PublicKey:
+		return JWK{Kty: "OKP", Crv: "Ed25519", X: encode(key)}, nil
+	}
+	return JWK{}, fmt.Errorf("unsupported key type %T", public)
+}
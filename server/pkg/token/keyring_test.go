@@ -0,0 +1,320 @@
+package token
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadDir(t *testing.T) {
+	old, current := newKey(t, "2026-01"), newKey(t, "2026-02")
+	migrated, ed := ecKey(t, elliptic.P256()), edKey(t)
+	testCases := []struct {
+		title      string
+		files      map[string][]byte
+		wantActive string
+		wantKeys   []string
+		wantAlgs   []string
+		isError    bool
+	}{
+		{
+			title:      "active file selects the signer",
+			files:      map[string][]byte{"2026-01.pem": privatePem(old), "2026-02.pem": privatePem(current), "active": []byte("2026-02\n")},
+			wantActive: "2026-02",
+			wantKeys:   []string{"2026-01", "2026-02"},
+		},
+		{
+			title:      "single private key is the signer and public keys only verify",
+			files:      map[string][]byte{"2026-01.pem": publicPem(t, old.Public), "2026-02.pem": privatePem(current)},
+			wantActive: "2026-02",
+			wantKeys:   []string{"2026-01", "2026-02"},
+		},
+		{
+			title:   "several private keys without active file and return error",
+			files:   map[string][]byte{"2026-01.pem": privatePem(old), "2026-02.pem": privatePem(current)},
+			isError: true,
+		},
+		{
+			title:      "algorithm of every key is named by its file",
+			files:      map[string][]byte{"2026-01.pem": publicPem(t, old.Public), "2026-02.ES256.pem": pkcs8Pem(t, migrated), "2026-03.EdDSA.pem": publicPem(t, ed.Public())},
+			wantActive: "2026-02",
+			wantKeys:   []string{"2026-01", "2026-02", "2026-03"},
+			wantAlgs:   []string{"RS512", "ES256", "EdDSA"},
+		},
+		{
+			title:      "unsupported suffix is a part of the kid",
+			files:      map[string][]byte{"2026.01.pem": privatePem(old)},
+			wantActive: "2026.01",
+			wantKeys:   []string{"2026.01"},
+		},
+		{
+			title:   "same kid in two files and return error",
+			files:   map[string][]byte{"2026-01.pem": publicPem(t, old.Public), "2026-01.RS256.pem": privatePem(current)},
+			isError: true,
+		},
+		{
+			title:   "key doesn't match the algorithm of its file and return error",
+			files:   map[string][]byte{"2026-01.ES256.pem": privatePem(old)},
+			isError: true,
+		},
+		{
+			title:   "malformed key and return error",
+			files:   map[string][]byte{"2026-01.pem": []byte("not a key")},
+			isError: true,
+		},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range test.files {
+				assert.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0600))
+			}
+			keys, active, err := LoadDir(dir, "RS512")
+			if test.isError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.wantActive, active)
+			ring, err := NewKeyRing(keys, active)
+			assert.NoError(t, err)
+			ids := make([]string, 0)
+			algs := make([]string, 0)
+			for _, key := range ring.Keys() {
+				ids = append(ids, key.Id)
+				algs = append(algs, key.Method.Alg())
+			}
+			assert.Equal(t, test.wantKeys, ids)
+			if test.wantAlgs != nil {
+				assert.Equal(t, test.wantAlgs, algs)
+			}
+		})
+	}
+}
+
+func TestRotation(t *testing.T) {
+	old, current := newKey(t, "2026-01"), newKey(t, "2026-02")
+	ring, err := NewKeyRing([]Key{old}, "2026-01")
+	assert.NoError(t, err)
+	handler := NewTokenHandler(logging.GetLogger("debug"), ring, ring)
+	signedByOld, err := handler.CreateAccessToken(time.Minute, 1, "")
+	assert.NoError(t, err)
+
+	// the old key is kept for verification only
+	assert.NoError(t, ring.Replace([]Key{{Id: old.Id, Method: old.Method, Public: old.Public}, current}, "2026-02"))
+	signedByCurrent, err := handler.CreateAccessToken(time.Minute, 2, "laptop")
+	assert.NoError(t, err)
+	claims, err := handler.ValidateAccessToken(signedByOld)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, claims.Subject)
+	assert.Empty(t, claims.Session)
+	claims, err = handler.ValidateAccessToken(signedByCurrent)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, claims.Subject)
+	assert.NotEmpty(t, claims.Id)
+	assert.Equal(t, "laptop", claims.Session)
+
+	// the old key is retired
+	assert.NoError(t, ring.Replace([]Key{current}, "2026-02"))
+	_, err = handler.ValidateAccessToken(signedByOld)
+	assert.Error(t, err)
+
+	assert.Error(t, ring.Replace([]Key{{Id: old.Id, Method: old.Method, Public: old.Public}}, "2026-01"))
+	assert.Equal(t, "2026-02", ring.Signer().Id)
+}
+
+func TestMigration(t *testing.T) {
+	dir := t.TempDir()
+	old := newKey(t, "2026-01")
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2026-01.pem"), privatePem(old), 0600))
+	keys, active, err := LoadDir(dir, "RS512")
+	assert.NoError(t, err)
+	ring, err := NewKeyRing(keys, active)
+	assert.NoError(t, err)
+	reloader := NewReloader(logging.GetLogger("debug"), dir, "RS512", ring, time.Hour)
+	handler := NewTokenHandler(logging.GetLogger("debug"), ring, ring)
+	signedByOld, err := handler.CreateAccessToken(time.Minute, 1, "")
+	assert.NoError(t, err)
+
+	// the new key signs with another algorithm and the old one still verifies
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2026-02.ES256.pem"), pkcs8Pem(t, ecKey(t, elliptic.P256())), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("2026-02"), 0600))
+	reloader.Reload()
+	assert.Equal(t, "ES256", ring.Signer().Method.Alg())
+	signedByNew, err := handler.CreateAccessToken(time.Minute, 2, "")
+	assert.NoError(t, err)
+	claims, err := handler.ValidateAccessToken(signedByOld)
+	assert.NoError(t, err)
+	assert.Equal(t, 1.0, claims.Subject)
+	claims, err = handler.ValidateAccessToken(signedByNew)
+	assert.NoError(t, err)
+	assert.Equal(t, 2.0, claims.Subject)
+}
+
+func TestAlgorithms(t *testing.T) {
+	testCases := []struct {
+		title     string
+		algorithm string
+		private   crypto.Signer
+		wantKty   string
+		wantCrv   string
+	}{
+		{title: "RS256", algorithm: "RS256", private: rsaKey(t), wantKty: "RSA"},
+		{title: "default is RS512", algorithm: "", private: rsaKey(t), wantKty: "RSA"},
+		{title: "ES256", algorithm: "ES256", private: ecKey(t, elliptic.P256()), wantKty: "EC", wantCrv: "P-256"},
+		{title: "EdDSA", algorithm: "EdDSA", private: edKey(t), wantKty: "OKP", wantCrv: "Ed25519"},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			ring, err := NewPairRing(TokenPair{PrivateKey: pkcs8Pem(t, test.private), Algorithm: test.algorithm})
+			assert.NoError(t, err)
+			handler := NewTokenHandler(logging.GetLogger("debug"), ring, ring)
+			signed, err := handler.CreateAccessToken(time.Minute, 1, "")
+			assert.NoError(t, err)
+			claims, err := handler.ValidateAccessToken(signed)
+			assert.NoError(t, err)
+			assert.Equal(t, 1.0, claims.Subject)
+
+			set := ring.JWKS()
+			assert.Len(t, set.Keys, 1)
+			assert.Equal(t, ring.Signer().Id, set.Keys[0].Kid)
+			assert.Equal(t, ring.Signer().Method.Alg(), set.Keys[0].Alg)
+			assert.Equal(t, test.wantKty, set.Keys[0].Kty)
+			assert.Equal(t, test.wantCrv, set.Keys[0].Crv)
+
+			public, err := ParseKey("public", test.algorithm, publicPem(t, test.private.Public()))
+			assert.NoError(t, err)
+			assert.Nil(t, public.Private)
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	testCases := []struct {
+		title     string
+		algorithm string
+		data      []byte
+	}{
+		{title: "unsupported algorithm", algorithm: "HS256", data: pkcs8Pem(t, rsaKey(t))},
+		{title: "RSA key for EdDSA", algorithm: "EdDSA", data: pkcs8Pem(t, rsaKey(t))},
+		{title: "Ed25519 key for RS512", algorithm: "RS512", data: pkcs8Pem(t, edKey(t))},
+		{title: "P-384 key for ES256", algorithm: "ES256", data: pkcs8Pem(t, ecKey(t, elliptic.P384()))},
+		{title: "P-384 public key for ES256", algorithm: "ES256", data: publicPem(t, ecKey(t, elliptic.P384()).Public())},
+	}
+	for _, test := range testCases {
+		t.Run(test.title, func(t *testing.T) {
+			_, err := ParseKey("key", test.algorithm, test.data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStrictAlgorithm(t *testing.T) {
+	private := rsaKey(t)
+	ring, err := NewKeyRing([]Key{{Id: "key", Method: jwt.SigningMethodRS512, Private: private, Public: private.Public()}}, "key")
+	assert.NoError(t, err)
+	handler := NewTokenHandler(logging.GetLogger("debug"), ring, ring)
+
+	// the same key with another RSA algorithm
+	other, err := create(time.Minute, 1, "", Key{Id: "key", Method: jwt.SigningMethodRS256, Private: private})
+	assert.NoError(t, err)
+	_, err = handler.ValidateAccessToken(other)
+	assert.Error(t, err)
+
+	// the public key used as an HMAC secret
+	hmac := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": 1})
+	hmac.Header["kid"] = "key"
+	signed, err := hmac.SignedString(publicPem(t, private.Public()))
+	assert.NoError(t, err)
+	_, err = handler.ValidateAccessToken(signed)
+	assert.Error(t, err)
+
+	// no algorithm at all
+	none := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.MapClaims{"sub": 1})
+	none.Header["kid"] = "key"
+	signed, err = none.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	assert.NoError(t, err)
+	_, err = handler.ValidateAccessToken(signed)
+	assert.Error(t, err)
+}
+
+func TestReloader(t *testing.T) {
+	old, current := newKey(t, "2026-01"), newKey(t, "2026-02")
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2026-01.pem"), privatePem(old), 0600))
+	ring, err := NewKeyRing([]Key{old}, "2026-01")
+	assert.NoError(t, err)
+	reloader := NewReloader(logging.GetLogger("debug"), dir, "RS512", ring, time.Hour)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "2026-02.pem"), privatePem(current), 0600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("2026-02"), 0600))
+	reloader.Reload()
+	assert.Equal(t, "2026-02", ring.Signer().Id)
+
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "active"), []byte("2026-03"), 0600))
+	reloader.Reload()
+	assert.Equal(t, "2026-02", ring.Signer().Id)
+	assert.NoError(t, reloader.Close())
+}
+
+func newKey(t *testing.T, id string) Key {
+	private := rsaKey(t)
+	return Key{Id: id, Method: jwt.SigningMethodRS512, Private: private, Public: &private.PublicKey}
+}
+
+func rsaKey(t *testing.T) *rsa.PrivateKey {
+	private, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private
+}
+
+func ecKey(t *testing.T, curve elliptic.Curve) *ecdsa.PrivateKey {
+	private, err := ecdsa.GenerateKey(curve, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private
+}
+
+func edKey(t *testing.T) ed25519.PrivateKey {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return private
+}
+
+func privatePem(key Key) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key.Private.(*rsa.PrivateKey))})
+}
+
+func pkcs8Pem(t *testing.T, private crypto.Signer) []byte {
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+}
+
+func publicPem(t *testing.T, public crypto.PublicKey) []byte {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
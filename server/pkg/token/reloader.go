@@ -0,0 +1,59 @@
+package token
+
+import (
+	"sync"
+	"time"
+
+	"github.com/VrMolodyakov/stock-market/pkg/logging"
+)
+
+// Reloader reads the keys of the directory into the ring every interval, so
+// that keys are rotated without a restart. The ring keeps its keys when the
+// directory can't be read.
+type Reloader struct {
+	logger    *logging.Logger
+	dir       string
+	algorithm string
+	ring      *KeyRing
+	interval  time.Duration
+	done      chan struct{}
+	once      sync.Once
+	wg        sync.WaitGroup
+}
+
+func NewReloader(logger *logging.Logger, dir string, algorithm string, ring *KeyRing, interval time.Duration) *Reloader {
+	return &Reloader{logger: logger, dir: dir, algorithm: algorithm, ring: ring, interval: interval, done: make(chan struct{})}
+}
+
+func (r *Reloader) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.done:
+				return
+			case <-ticker.C:
+				r.Reload()
+			}
+		}
+	}()
+}
+
+func (r *Reloader) Reload() {
+	keys, active, err := LoadDir(r.dir, r.algorithm)
+	if err == nil {
+		err = r.ring.Replace(keys, active)
+	}
+	if err != nil {
+		r.logger.Errorf("cannot reload keys of %v due to : %v", r.dir, err)
+	}
+}
+
+func (r *Reloader) Close() error {
+	r.once.Do(func() { close(r.done) })
+	r.wg.Wait()
+	return nil
+}
@@ -1,7 +1,8 @@
 package token
 
 import (
-	"crypto/rsa"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -12,73 +13,109 @@ import (
 type TokenPair struct {
 	PrivateKey []byte
 	PublicKey  []byte
+	Algorithm  string
+}
+
+// Claims are the claims of a validated access token. Session is the session
+// the token was issued for, it's empty for the tokens issued before sessions.
+type Claims struct {
+	Subject   interface{}
+	Id        string
+	Session   string
+	ExpiresAt time.Time
 }
 
 type tokenHandler struct {
-	logger      *logging.Logger
-	accessPair  TokenPair
-	refreshPair TokenPair
+	logger  *logging.Logger
+	access  *KeyRing
+	refresh *KeyRing
 }
 
-func NewTokenHandler(logger *logging.Logger, accessPair TokenPair, refreshPair TokenPair) *tokenHandler {
-	return &tokenHandler{logger: logger, accessPair: accessPair, refreshPair: refreshPair}
+func NewTokenHandler(logger *logging.Logger, access *KeyRing, refresh *KeyRing) *tokenHandler {
+	return &tokenHandler{logger: logger, access: access, refresh: refresh}
 }
 
-func (t *tokenHandler) CreateAccessToken(ttl time.Duration, payload interface{}) (string, error) {
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(t.accessPair.PrivateKey)
+// NewPairRing returns the ring of a single key pair, the kid is the
+// thumbprint of the key.
+func NewPairRing(pair TokenPair) (*KeyRing, error) {
+	key, err := ParseKey("", pair.Algorithm, pair.PrivateKey)
 	if err != nil {
-		return "", fmt.Errorf("couldn't parse private key: %w ", err)
+		return nil, err
+	}
+	if key.Private == nil {
+		return nil, fmt.Errorf("couldn't parse private key")
+	}
+	if key.Id, err = Thumbprint(key.Public); err != nil {
+		return nil, err
 	}
-	return create(ttl, payload, key)
+	return NewKeyRing([]Key{key}, key.Id)
+}
+
+// CreateAccessToken signs an access token of the session, so that ending the
+// session also revokes its access tokens.
+func (t *tokenHandler) CreateAccessToken(ttl time.Duration, payload interface{}, session string) (string, error) {
+	return create(ttl, payload, session, t.access.Signer())
 }
 
 func (t *tokenHandler) CreateRefreshToken(ttl time.Duration, payload interface{}) (string, error) {
-	key, err := jwt.ParseRSAPrivateKeyFromPEM(t.refreshPair.PrivateKey)
-	if err != nil {
-		return "", fmt.Errorf("couldn't parse private key: %w ", err)
-	}
-	return create(ttl, payload, key)
+	return create(ttl, payload, "", t.refresh.Signer())
 }
 
-func create(ttl time.Duration, payload interface{}, key *rsa.PrivateKey) (string, error) {
+func create(ttl time.Duration, payload interface{}, session string, key Key) (string, error) {
 	now := time.Now().UTC()
+	// the id keeps two tokens of the same user issued in one second apart and
+	// lets a single token be revoked
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("couldn't generate token id due to %w", err)
+	}
 	claims := make(jwt.MapClaims)
+	claims["jti"] = hex.EncodeToString(id)
 	claims["sub"] = payload
 	claims["exp"] = now.Add(ttl).Unix()
 	claims["iat"] = now.Unix()
 	claims["nbf"] = now.Unix()
+	if session != "" {
+		claims["sid"] = session
+	}
 
-	token, err := jwt.NewWithClaims(jwt.SigningMethodRS512, claims).SignedString(key)
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.Id
+	signed, err := token.SignedString(key.Private)
 	if err != nil {
 		return "", fmt.Errorf("couldn't sign key due to %w", err)
 	}
-	return token, nil
+	return signed, nil
 }
 
-func (t *tokenHandler) ValidateAccessToken(token string) (interface{}, error) {
-	key, err := jwt.ParseRSAPublicKeyFromPEM(t.accessPair.PublicKey)
+func (t *tokenHandler) ValidateAccessToken(token string) (Claims, error) {
+	claims, err := validate(token, t.access)
 	if err != nil {
-		return -1, fmt.Errorf("couldn't parse public key: %w ", err)
+		return Claims{}, err
 	}
-	return validate(token, key)
+	id, _ := claims["jti"].(string)
+	session, _ := claims["sid"].(string)
+	exp, _ := claims["exp"].(float64)
+	return Claims{Subject: claims["sub"], Id: id, Session: session, ExpiresAt: time.Unix(int64(exp), 0)}, nil
 }
 
 func (t *tokenHandler) ValidateRefreshToken(token string) error {
-	key, err := jwt.ParseRSAPublicKeyFromPEM(t.refreshPair.PublicKey)
-	if err != nil {
-		return fmt.Errorf("couldn't parse public key: %w ", err)
-	}
-	_, err = validate(token, key)
+	_, err := validate(token, t.refresh)
 	return err
-
 }
 
-func validate(token string, key *rsa.PublicKey) (interface{}, error) {
+func validate(token string, ring *KeyRing) (jwt.MapClaims, error) {
 	parsedToken, err := jwt.Parse(token, func(t *jwt.Token) (interface{}, error) {
-		if _, signed := t.Method.(*jwt.SigningMethodRSA); !signed {
+		kid, _ := t.Header["kid"].(string)
+		key, ok := ring.Verifier(kid)
+		if !ok {
+			return nil, fmt.Errorf("unknown key - %v", kid)
+		}
+		// the key is accepted only with its own algorithm
+		if t.Method.Alg() != key.Method.Alg() {
 			return nil, fmt.Errorf("unexpected method - %v", t.Header["alg"])
 		}
-		return key, nil
+		return key.Public, nil
 	})
 	if err != nil {
 		return nil, fmt.Errorf("couldn't parse : %w", err)
@@ -87,5 +124,5 @@ func validate(token string, key *rsa.PublicKey) (interface{}, error) {
 	if !ok || !parsedToken.Valid {
 		return nil, fmt.Errorf("invalid token : %w", err)
 	}
-	return claims["sub"], nil
+	return claims, nil
 }
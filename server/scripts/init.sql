@@ -1,6 +1,116 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
 CREATE TABLE users(
     u_id SERIAL PRIMARY KEY,
     u_password VARCHAR(200) NOT NULL,
     u_name VARCHAR(200) NOT NULL,
     create_at TIMESTAMP NOT  NULL
-);
\ No newline at end of file
+);
+
+CREATE TABLE bars(
+    b_symbol VARCHAR(32) NOT NULL,
+    b_interval VARCHAR(8) NOT NULL,
+    b_timestamp TIMESTAMPTZ NOT NULL,
+    b_open DOUBLE PRECISION NOT NULL,
+    b_high DOUBLE PRECISION NOT NULL,
+    b_low DOUBLE PRECISION NOT NULL,
+    b_close DOUBLE PRECISION NOT NULL,
+    b_volume DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (b_symbol, b_interval, b_timestamp)
+);
+
+CREATE TABLE tracked_symbols(
+    ts_symbol VARCHAR(32) PRIMARY KEY,
+    create_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE watchlists(
+    w_id SERIAL PRIMARY KEY,
+    w_user_id INT NOT NULL REFERENCES users(u_id) ON DELETE CASCADE,
+    w_name VARCHAR(100) NOT NULL,
+    create_at TIMESTAMP NOT NULL,
+    UNIQUE (w_user_id, w_name)
+);
+
+CREATE TABLE watchlist_symbols(
+    ws_watchlist_id INT NOT NULL REFERENCES watchlists(w_id) ON DELETE CASCADE,
+    ws_symbol VARCHAR(32) NOT NULL,
+    ws_position INT NOT NULL,
+    PRIMARY KEY (ws_watchlist_id, ws_symbol)
+);
+
+CREATE TABLE portfolios(
+    p_user_id INT PRIMARY KEY REFERENCES users(u_id) ON DELETE CASCADE,
+    p_cash DOUBLE PRECISION NOT NULL,
+    p_realized DOUBLE PRECISION NOT NULL,
+    create_at TIMESTAMP NOT NULL
+);
+
+CREATE TABLE positions(
+    pos_user_id INT NOT NULL REFERENCES portfolios(p_user_id) ON DELETE CASCADE,
+    pos_symbol VARCHAR(32) NOT NULL,
+    pos_quantity BIGINT NOT NULL,
+    pos_avg_cost DOUBLE PRECISION NOT NULL,
+    PRIMARY KEY (pos_user_id, pos_symbol)
+);
+
+CREATE TABLE orders(
+    o_id SERIAL PRIMARY KEY,
+    o_user_id INT NOT NULL REFERENCES portfolios(p_user_id) ON DELETE CASCADE,
+    o_symbol VARCHAR(32) NOT NULL,
+    o_side VARCHAR(4) NOT NULL,
+    o_quantity BIGINT NOT NULL,
+    o_price DOUBLE PRECISION NOT NULL,
+    o_realized DOUBLE PRECISION NOT NULL,
+    create_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX orders_user_idx ON orders(o_user_id, o_id DESC);
+
+CREATE TABLE book_orders(
+    bo_id SERIAL PRIMARY KEY,
+    bo_user_id INT NOT NULL REFERENCES users(u_id) ON DELETE CASCADE,
+    bo_symbol VARCHAR(32) NOT NULL,
+    bo_side VARCHAR(4) NOT NULL,
+    bo_type VARCHAR(10) NOT NULL,
+    bo_quantity BIGINT NOT NULL,
+    bo_filled BIGINT NOT NULL,
+    bo_limit_price DOUBLE PRECISION NOT NULL,
+    bo_stop_price DOUBLE PRECISION NOT NULL,
+    bo_triggered BOOLEAN NOT NULL,
+    bo_tif VARCHAR(3) NOT NULL,
+    bo_state VARCHAR(16) NOT NULL,
+    bo_expires_at TIMESTAMPTZ,
+    create_at TIMESTAMP NOT NULL,
+    update_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX book_orders_open_idx ON book_orders(bo_symbol) WHERE bo_state IN ('open', 'partially_filled');
+
+CREATE TABLE alerts(
+    a_id SERIAL PRIMARY KEY,
+    a_user_id INT NOT NULL REFERENCES users(u_id) ON DELETE CASCADE,
+    a_symbol VARCHAR(32) NOT NULL,
+    a_condition VARCHAR(16) NOT NULL,
+    a_threshold DOUBLE PRECISION NOT NULL,
+    a_channel VARCHAR(16) NOT NULL,
+    a_target VARCHAR(320) NOT NULL,
+    a_rearm BOOLEAN NOT NULL,
+    a_armed BOOLEAN NOT NULL,
+    a_active BOOLEAN NOT NULL,
+    a_fired_at TIMESTAMP,
+    create_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX alerts_symbol_idx ON alerts(a_symbol) WHERE a_active;
+
+CREATE TABLE symbols(
+    s_symbol VARCHAR(32) PRIMARY KEY,
+    s_name VARCHAR(200) NOT NULL,
+    s_exchange VARCHAR(32) NOT NULL,
+    s_type VARCHAR(16) NOT NULL,
+    s_currency VARCHAR(8) NOT NULL
+);
+
+CREATE INDEX symbols_symbol_trgm_idx ON symbols USING GIN (s_symbol gin_trgm_ops);
+CREATE INDEX symbols_name_trgm_idx ON symbols USING GIN (s_name gin_trgm_ops);